@@ -0,0 +1,45 @@
+package uriuniq
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// IndexOf returns the index of c in charset, or -1 if c is not in it.
+//
+// The mapping is stable: the char at index i of a charset generated with
+// Options is charset[i], and the order of the preset charsets will not
+// change. Options without CustomCharset build their charset as Numeric,
+// Lowercase and Uppercase in that order, skipping excluded ones, so
+// external systems can decode numeric fields embedded in IDs.
+func IndexOf(charset Charset, c byte) int {
+	return strings.IndexByte(string(charset), c)
+}
+
+// Reorder returns charset permuted under key, to obfuscate the mapping of
+// IndexOf from readers without the key. The same charset and key always
+// yield the same order.
+//
+// The permutation is a Fisher-Yates shuffle: for i from len(charset)-1
+// down to 1, charset[i] is swapped with charset[j], where j is the next
+// big-endian uint16 v of the keystream of DeriveGenerator (with info
+// "uriuniq reorder") reduced as v % (i+1), skipping any v above
+// 65535 - 65536 % (i+1).
+func Reorder(charset Charset, key []byte) Charset {
+	out := []byte(charset)
+	stream := deriveStream(key, "uriuniq reorder")
+	var b [2]byte
+	for i := len(out) - 1; i > 0; i-- {
+		n := uint16(i + 1)
+		limit := ^uint16(0) - uint16(65536%int(n))
+		for {
+			stream.Read(b[:])
+			if v := binary.BigEndian.Uint16(b[:]); v <= limit {
+				j := int(v % n)
+				out[i], out[j] = out[j], out[i]
+				break
+			}
+		}
+	}
+	return Charset(out)
+}
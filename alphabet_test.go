@@ -0,0 +1,59 @@
+package uriuniq
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestIndexOf checks the documented mapping of preset charsets.
+func TestIndexOf(t *testing.T) {
+	tests := []struct {
+		charset  Charset
+		char     byte
+		expected int
+	}{
+		{Numeric, '7', 7},
+		{Lowercase, 'a', 0},
+		{Uppercase, 'Z', 25},
+		{Alphanumeric, '0', 52},
+		{Numeric, 'a', -1},
+	}
+	for _, tc := range tests {
+		if got := IndexOf(tc.charset, tc.char); got != tc.expected {
+			t.Errorf("IndexOf(%q, %q) = %d, expected %d", tc.charset, tc.char, got, tc.expected)
+		}
+	}
+
+	opts := NewOpts()
+	opts.ExcludeUppercase = true
+	_, charset, _ := prepare(opts)
+	if got := IndexOf(Charset(charset), 'a'); got != 10 {
+		t.Errorf("Expected 'a' at index 10 of the default charset, got %d", got)
+	}
+}
+
+// TestReorder checks that Reorder is a stable, keyed permutation.
+func TestReorder(t *testing.T) {
+	a := Reorder(Alphanumeric, []byte("key 1"))
+	if a != Reorder(Alphanumeric, []byte("key 1")) {
+		t.Errorf("Reorder is not deterministic")
+	}
+	if a == Alphanumeric || a == Reorder(Alphanumeric, []byte("key 2")) {
+		t.Errorf("Reorder should depend on the key")
+	}
+	sorted := func(c Charset) string {
+		b := []byte(c)
+		sort.Slice(b, func(i, j int) bool { return b[i] < b[j] })
+		return string(b)
+	}
+	if sorted(a) != sorted(Alphanumeric) {
+		t.Errorf("Reorder is not a permutation: %q", a)
+	}
+	// Pinned so the documented algorithm cannot drift.
+	if got := Reorder(Numeric, []byte("key 1")); got != "4980521763" {
+		t.Errorf("Unexpected pinned order %q", got)
+	}
+	if got := Reorder("x", []byte("key")); got != "x" {
+		t.Errorf("Unexpected single char result %q", got)
+	}
+}
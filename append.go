@@ -0,0 +1,88 @@
+package uriuniq
+
+import "io"
+
+// AppendTo appends a random string generated with opts, as returned by
+// Generate, to dst and returns the extended buffer. For IDs in a tight
+// loop, the AppendTo method of a Generator avoids the allocations as well.
+func AppendTo(dst []byte, opts Options) ([]byte, error) {
+	output, err := generate(opts)
+	if err != nil {
+		return dst, err
+	}
+	dst = append(dst, idPrefix(opts)...)
+	dst = appendOutput(dst, opts, output)
+	if opts.Sensitive {
+		wipe(output)
+	}
+	return dst, nil
+}
+
+// FillBytes fills all of dst with random chars of the charset of opts,
+// ignoring Length. Options shaping an ID beyond its charset, such as the
+// Prefix, check char or DNSLabel, cannot apply to bare chars and are
+// rejected. See the FillBytes method of a Generator to avoid allocations.
+func FillBytes(dst []byte, opts Options) error {
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return err
+	}
+	if err := checkFill(opts); err != nil {
+		return err
+	}
+	if len(charset) > 256 {
+		return newError(CodeCharsetSize, "uriuniq: charset size 2-256")
+	}
+	buffer := getBuffer(readSize(len(dst), len(charset)))
+	defer putBuffer(buffer)
+	_, err = randFill(opts, charset, dst, *buffer)
+	return err
+}
+
+// checkFill rejects the Options FillBytes cannot honor, naming the first.
+func checkFill(opts Options) error {
+	var name string
+	switch {
+	case opts.Prefix != "":
+		name = "Prefix"
+	case opts.CheckChar:
+		name = "CheckChar"
+	case opts.GroupSize > 0:
+		name = "GroupSize"
+	case opts.Escape == EscapePercent:
+		name = "EscapePercent"
+	case opts.InstanceLabel != "":
+		name = "InstanceLabel"
+	case opts.NodeChars > 0:
+		name = "NodeChars"
+	case opts.DNSLabel:
+		name = "DNSLabel"
+	case opts.NoLeadingDigit:
+		name = "NoLeadingDigit"
+	case composed(opts):
+		name = "MinDigits, MinLowercase or MinUppercase"
+	case opts.Blocklist != nil:
+		name = "Blocklist"
+	default:
+		return nil
+	}
+	return errorf(CodeInvalidArgument, "uriuniq: FillBytes cannot honor %s", name)
+}
+
+// Reader returns an io.Reader whose Read fills p with random chars of the
+// charset of g, as by FillBytes, for piping into writers and encoders
+// without intermediate strings. Read returns len(p) or an error from the
+// EntropySource; it never returns io.EOF.
+func (g *Generator) Reader() io.Reader {
+	return charReader{g}
+}
+
+// charReader is the io.Reader returned by Generator.Reader.
+type charReader struct{ g *Generator }
+
+func (r charReader) Read(p []byte) (int, error) {
+	if err := r.g.FillBytes(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
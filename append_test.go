@@ -0,0 +1,126 @@
+package uriuniq
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestAppendTo checks appending IDs to a reused buffer.
+func TestAppendTo(t *testing.T) {
+	opts := Options{Length: 12, Prefix: "ord", CheckChar: true}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	buf := []byte("ids:")
+	for i := 0; i < 3; i++ {
+		if buf, err = g.AppendTo(append(buf, ' ')); err != nil {
+			t.Fatalf("AppendTo failed: %s", err)
+		}
+	}
+	if buf, err = AppendTo(append(buf, ' '), opts); err != nil {
+		t.Fatalf("AppendTo failed: %s", err)
+	}
+	fields := strings.Fields(string(buf))
+	if len(fields) != 5 || fields[0] != "ids:" {
+		t.Fatalf("Unexpected buffer %q", buf)
+	}
+	for _, id := range fields[1:] {
+		if err := Validate(id, opts); err != nil {
+			t.Errorf("Invalid ID %q: %s", id, err)
+		}
+	}
+
+	escaped := Options{Length: 8, CustomCharset: "a/", Escape: EscapePercent}
+	if buf, err := AppendTo(nil, escaped); err != nil || strings.Trim(string(buf), "a%2F") != "" || len(buf) < 8 {
+		t.Errorf("Unexpected escaped ID %q, %v", buf, err)
+	}
+}
+
+// TestAppendToAllocs checks that a buffered Generator does not allocate
+// once the buffer has room.
+func TestAppendToAllocs(t *testing.T) {
+	g, err := NewGenerator(NewOpts())
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = g.AppendTo(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %.1f", allocs)
+	}
+	dst := make([]byte, 100)
+	allocs = testing.AllocsPerRun(100, func() {
+		g.FillBytes(dst)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations for FillBytes, got %.1f", allocs)
+	}
+}
+
+// TestFillBytes checks that every byte is filled from the charset.
+func TestFillBytes(t *testing.T) {
+	dst := make([]byte, 500)
+	if err := FillBytes(dst, Options{CustomCharset: Hex, Length: 1}); err != nil {
+		t.Fatalf("FillBytes failed: %s", err)
+	}
+	if err := checkCharset(string(dst), Hex); err != nil {
+		t.Errorf("Unexpected char: %s", err)
+	}
+	g, err := NewGenerator(Options{Length: 4, CustomCharset: Numeric})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	if err := g.FillBytes(dst); err != nil || checkCharset(string(dst), Numeric) != nil {
+		t.Errorf("Unexpected fill %q, %v", dst[:20], err)
+	}
+}
+
+// TestReader checks that the Reader of a Generator serves chars of the
+// charset and passes on source errors.
+func TestReader(t *testing.T) {
+	g, err := NewGenerator(Options{CustomCharset: Hex, Length: 8})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(g.Reader(), 5000))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %s", err)
+	}
+	if len(data) != 5000 || checkCharset(string(data), Hex) != nil {
+		t.Errorf("Unexpected data of length %d", len(data))
+	}
+
+	want := errors.New("broken")
+	g, _ = NewGenerator(Options{Length: 8, EntropySource: errReader{want}, EntropyBufferSize: -1})
+	if n, err := g.Reader().Read(make([]byte, 10)); n != 0 || !errors.Is(err, want) {
+		t.Errorf("Expected 0 and the source error, got %d, %v", n, err)
+	}
+}
+
+// TestFillBytesOptions checks that FillBytes rejects the Options it cannot
+// honor instead of ignoring them.
+func TestFillBytesOptions(t *testing.T) {
+	for _, opts := range []Options{
+		{Length: 8, DNSLabel: true},
+		{Length: 8, NoLeadingDigit: true},
+		{Length: 8, CheckChar: true},
+		{Length: 8, Prefix: "cus"},
+		{Length: 8, MinDigits: 2},
+	} {
+		if err := FillBytes(make([]byte, 8), opts); CodeOf(err) != CodeInvalidArgument {
+			t.Errorf("Expected %s for %+v, got %v", CodeInvalidArgument, opts, err)
+		}
+		g, err := NewGenerator(opts)
+		if err != nil {
+			t.Fatalf("NewGenerator failed: %s", err)
+		}
+		if err := g.FillBytes(make([]byte, 8)); CodeOf(err) != CodeInvalidArgument {
+			t.Errorf("Expected %s from a Generator for %+v, got %v", CodeInvalidArgument, opts, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package uriuniq
+
+// Arena holds many same-length IDs in a single backing string. Use it for
+// bulk jobs where one allocation per ID would dominate: At returns a
+// substring of the backing string and allocates nothing.
+type Arena struct {
+	data   string
+	length int
+}
+
+// GenerateArena creates n random strings using Options, as Generate does,
+// stored back to back in one Arena. The IDs must all have the same length,
+// so variable lengths, multi-byte charsets and percent-encoding that
+// changes the length are rejected. As with Generate, the strings are not
+// checked for uniqueness.
+func GenerateArena(opts Options, n int) (*Arena, error) {
+	if n < 0 {
+		return nil, newError(CodeInvalidArgument, "uriuniq: negative count")
+	}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	opts = g.opts
+	if g.runes != nil {
+		return nil, newError(CodeInvalidArgument, "uriuniq: arena needs a single-byte charset")
+	}
+	if opts.MaxLength > 0 {
+		return nil, newError(CodeInvalidLength, "uriuniq: arena needs a fixed length")
+	}
+	if opts.Escape == EscapePercent && checkCharset(string(g.charset), unreserved) != nil {
+		return nil, newError(CodeInvalidLength, "uriuniq: arena needs a fixed length, not percent-encoding")
+	}
+
+	length := len(idPrefix(opts)) + groupedLength(opts, opts.Length+checkChars(opts))
+	data := make([]byte, 0, n*length)
+	for i := 0; i < n; i++ {
+		if data, err = g.AppendTo(data); err != nil {
+			return nil, err
+		}
+	}
+	return &Arena{data: string(data), length: length}, nil
+}
+
+// Len returns the number of IDs in the Arena.
+func (a *Arena) Len() int {
+	if a.length == 0 {
+		return 0
+	}
+	return len(a.data) / a.length
+}
+
+// At returns the i-th ID. It panics if i is out of range.
+func (a *Arena) At(i int) string {
+	return a.data[i*a.length : (i+1)*a.length]
+}
+
+// Offset returns the byte offset of the i-th ID in the backing data, for
+// callers that store offsets rather than strings.
+func (a *Arena) Offset(i int) int {
+	return i * a.length
+}
+
+// Data returns the backing string holding all IDs back to back.
+func (a *Arena) Data() string {
+	return a.data
+}
@@ -0,0 +1,45 @@
+package uriuniq
+
+import "testing"
+
+// TestGenerateArena checks the layout of IDs in an Arena.
+func TestGenerateArena(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 10
+	arena, err := GenerateArena(opts, 1000)
+	if err != nil {
+		t.Fatalf("GenerateArena failed: %s", err)
+	}
+	if arena.Len() != 1000 || len(arena.Data()) != 10000 {
+		t.Fatalf("Unexpected arena size %d", arena.Len())
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < arena.Len(); i++ {
+		id := arena.At(i)
+		if len(id) != 10 || checkCharset(id, Alphanumeric) != nil || arena.Data()[arena.Offset(i):][:10] != id {
+			t.Errorf("Unexpected ID %q at %d", id, i)
+		}
+		seen[id] = true
+	}
+	if len(seen) != 1000 {
+		t.Errorf("Expected 1000 distinct IDs, got %d", len(seen))
+	}
+
+	empty, err := GenerateArena(opts, 0)
+	if err != nil || empty.Len() != 0 {
+		t.Errorf("Expected empty arena, got %v", err)
+	}
+	if _, err := GenerateArena(opts, -1); err == nil {
+		t.Errorf("Expected error for negative count")
+	}
+}
+
+// BenchmarkGenerateArena benchmarks bulk generation into an Arena.
+func BenchmarkGenerateArena(b *testing.B) {
+	opts := NewOpts()
+	for i := 0; i < b.N; i++ {
+		if _, err := GenerateArena(opts, 1000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
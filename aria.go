@@ -0,0 +1,42 @@
+package uriuniq
+
+import "strings"
+
+// DefaultAriaGroupSize is the group size of AriaLabel.
+const DefaultAriaGroupSize = 4
+
+// AriaLabel returns a form of id for the aria-label attribute, so screen
+// readers announce it char by char instead of as a word: chars are
+// separated by spaces, groups of groupSize chars by a comma, which makes
+// readers pause, and symbols are named as in SpellOut. Uppercase letters
+// are prefixed with "capital", as readers often do not announce case.
+// groupSize <= 0 uses DefaultAriaGroupSize.
+//
+// Show the visible ID in groups of the same size, so what is heard
+// matches what is seen.
+func AriaLabel(id string, groupSize int) string {
+	if groupSize <= 0 {
+		groupSize = DefaultAriaGroupSize
+	}
+	var b strings.Builder
+	for i := 0; i < len(id); i++ {
+		if i > 0 {
+			if i%groupSize == 0 {
+				b.WriteString(", ")
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		c := id[i]
+		switch {
+		case 'A' <= c && c <= 'Z':
+			b.WriteString(capitalWord + " ")
+			b.WriteByte(c)
+		case symbolWords[c] != "":
+			b.WriteString(symbolWords[c])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
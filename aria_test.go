@@ -0,0 +1,22 @@
+package uriuniq
+
+import "testing"
+
+// TestAriaLabel checks spacing, grouping and named chars.
+func TestAriaLabel(t *testing.T) {
+	tests := []struct {
+		id        string
+		groupSize int
+		expected  string
+	}{
+		{"ab12cd34", 4, "a b 1 2, c d 3 4"},
+		{"ab12cd", 0, "a b 1 2, c d"},
+		{"aB-3", 2, "a capital B, dash 3"},
+		{"", 4, ""},
+	}
+	for _, tc := range tests {
+		if got := AriaLabel(tc.id, tc.groupSize); got != tc.expected {
+			t.Errorf("AriaLabel(%q, %d) = %q, expected %q", tc.id, tc.groupSize, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package uriuniq
+
+import "math"
+
+// GenerateN creates n random strings using Options, all distinct from
+// each other. They share one Generator and its entropy buffer, so a batch
+// costs far less than n calls to Generate. Duplicates within the batch are
+// dropped and replaced; if the keyspace of opts is too small for n, or so
+// nearly filled that duplicates keep coming, an error is returned.
+// Uniqueness against earlier batches is not checked.
+func GenerateN(opts Options, n int) ([]string, error) {
+	if n < 0 {
+		return nil, newError(CodeInvalidArgument, "uriuniq: negative count")
+	}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	// Allow for rounding in Entropy, which is exact only in bits.
+	if keyspace := math.Exp2(Entropy(g.opts)); float64(n) > keyspace*(1+1e-9) {
+		return nil, errorf(CodeInvalidArgument, "uriuniq: %d IDs exceed the keyspace of %.0f", n, keyspace)
+	}
+
+	ids := make([]string, 0, n)
+	seen := make(map[string]struct{}, n)
+	// Even drawing the whole keyspace takes about n ln n draws.
+	maxDups := 20*n + 100
+	for dups := 0; len(ids) < n; {
+		id, err := g.Next()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[id]; ok {
+			if dups++; dups > maxDups {
+				return nil, newError(CodeInvalidArgument, "uriuniq: too many duplicates, keyspace nearly exhausted")
+			}
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
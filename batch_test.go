@@ -0,0 +1,47 @@
+package uriuniq
+
+import "testing"
+
+// TestGenerateN checks batch size, uniqueness and keyspace limits.
+func TestGenerateN(t *testing.T) {
+	ids, err := GenerateN(NewOpts(), 1000)
+	if err != nil || len(ids) != 1000 {
+		t.Fatalf("Expected 1000 IDs, got %d, %v", len(ids), err)
+	}
+
+	// 100 two-digit codes leave room for every one of them.
+	opts := Options{Length: 2, CustomCharset: Numeric}
+	ids, err = GenerateN(opts, 100)
+	if err != nil {
+		t.Fatalf("GenerateN failed: %s", err)
+	}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("Duplicate ID %q", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != 100 {
+		t.Errorf("Expected all 100 codes, got %d", len(seen))
+	}
+
+	if _, err := GenerateN(opts, 101); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected keyspace error, got %v", err)
+	}
+	if _, err := GenerateN(opts, -1); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected error for negative count, got %v", err)
+	}
+	if ids, err := GenerateN(opts, 0); err != nil || len(ids) != 0 {
+		t.Errorf("Expected empty batch, got %v, %v", ids, err)
+	}
+}
+
+// BenchmarkGenerateN benchmarks a batch of 10000 default IDs.
+func BenchmarkGenerateN(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := GenerateN(NewOpts(), 10000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
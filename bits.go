@@ -0,0 +1,173 @@
+package uriuniq
+
+import (
+	"fmt"
+	"math"
+)
+
+// Entropy in bits per char of the preset charsets.
+const (
+	AlphanumericBits = 5.954196310386875 // log2(62)
+	LowercaseBits    = 4.700439718141092 // log2(26)
+	UppercaseBits    = 4.700439718141092 // log2(26)
+	NumericBits      = 3.321928094887362 // log2(10)
+	Base58Bits       = 5.857980995127572 // log2(58)
+)
+
+// PresetBits maps each preset charset to its entropy in bits per char.
+var PresetBits = map[Charset]float64{
+	Alphanumeric: AlphanumericBits,
+	Lowercase:    LowercaseBits,
+	Uppercase:    UppercaseBits,
+	Numeric:      NumericBits,
+
+	Base58:          Base58Bits,
+	Base32Crockford: 5,
+	Base64URL:       6,
+	Hex:             4,
+}
+
+// BitsPerChar returns the entropy in bits of one char drawn from charset.
+// Duplicate chars are accounted for, so "aab" yields less than log2(3).
+func BitsPerChar(charset Charset) float64 {
+	if len(charset) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(charset); i++ {
+		counts[charset[i]]++
+	}
+	n := float64(len(charset))
+	bits := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / n
+			bits -= p * math.Log2(p)
+		}
+	}
+	return bits
+}
+
+// Entropy returns the total entropy in bits of a string generated with opts.
+// With a length range, it includes the entropy of the length and uses the
+// mean length. Fingerprint and node chars carry no entropy, and the first
+// and last chars of NoLeadingDigit and DNSLabel less.
+func Entropy(opts Options) float64 {
+	if isRuneCharset(opts.CustomCharset) {
+		opts, alphabet, err := prepareRunes(opts)
+		if err != nil {
+			return 0
+		}
+		return float64(opts.Length) * math.Log2(float64(len(alphabet)))
+	}
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return 0
+	}
+	min, max := lengthRange(opts)
+	mean := float64(min+max)/2 - float64(fixedChars(opts))
+	return math.Log2(float64(max-min+1)) + mean*BitsPerChar(Charset(charset)) - edgeBitsLoss(opts, charset)
+}
+
+// shortestEntropy returns the entropy in bits of the shortest strings
+// generated with prepared opts, without that of their length.
+func shortestEntropy(opts Options, charset []byte) float64 {
+	min, _ := lengthRange(opts)
+	return float64(min-fixedChars(opts))*BitsPerChar(Charset(charset)) - edgeBitsLoss(opts, charset)
+}
+
+// checkEntropyFloor checks that bits, the entropy of the shortest string
+// of opts, reach opts.RequireEntropyBits.
+func checkEntropyFloor(opts Options, bits float64) error {
+	if opts.RequireEntropyBits < 0 {
+		return errorf(CodeInvalidArgument, "uriuniq: negative required entropy %d", opts.RequireEntropyBits)
+	}
+	// The epsilon keeps exact multiples from failing on rounding.
+	if bits < float64(opts.RequireEntropyBits)-1e-9 {
+		return fmt.Errorf("%w: %.1f bits, want at least %d", ErrInsufficientEntropy, bits, opts.RequireEntropyBits)
+	}
+	return nil
+}
+
+// CollisionProbability returns the chance that n strings generated with
+// opts are not all distinct, by the birthday bound 1 - exp(-n(n-1)/2N)
+// over the N = 2^Entropy(opts) possible strings. It returns 1 for invalid
+// opts.
+func CollisionProbability(opts Options, n uint64) float64 {
+	if n < 2 {
+		return 0
+	}
+	bits := Entropy(opts)
+	if bits == 0 {
+		return 1
+	}
+	pairs := float64(n) * float64(n-1) / 2
+	return -math.Expm1(-pairs * math.Exp2(-bits))
+}
+
+// RecommendedLength returns the shortest Length for which n strings drawn
+// from charsetSize chars collide with a chance of at most maxProb, such as
+// 15 for a billion Alphanumeric IDs at 1e-9. It returns 0 if charsetSize
+// is below 2 or maxProb is not between 0 and 1.
+func RecommendedLength(charsetSize int, n uint64, maxProb float64) int {
+	if charsetSize < 2 || !(maxProb > 0 && maxProb < 1) {
+		return 0
+	}
+	if n < 2 {
+		return 1
+	}
+	// The keyspace N must satisfy n(n-1)/2N <= -log(1-maxProb).
+	pairs := float64(n) * float64(n-1) / 2
+	bits := math.Log2(pairs) - math.Log2(-math.Log1p(-maxProb))
+	length := int(math.Ceil(bits / math.Log2(float64(charsetSize))))
+	if length < 1 {
+		length = 1
+	}
+	return length
+}
+
+// MustHaveEntropy panics if strings generated with opts have less than bits
+// of entropy. With a length range, the shortest strings are checked. Call it
+// at startup to fail fast on a weakened configuration.
+func MustHaveEntropy(opts Options, bits float64) {
+	opts, charset, err := prepare(opts)
+	got := 0.0
+	if err == nil {
+		got = shortestEntropy(opts, charset)
+	}
+	if got < bits {
+		panic(fmt.Sprintf("uriuniq: %.1f bits of entropy, need %.1f", got, bits))
+	}
+}
+
+// raiseToEntropy raises the lengths of prepared opts to reach
+// opts.MinEntropyBits.
+func raiseToEntropy(opts Options, charset []byte) (Options, error) {
+	perChar := BitsPerChar(Charset(charset))
+	if opts.MinEntropyBits < 0 || perChar == 0 {
+		return opts, errorf(CodeInvalidLength, "uriuniq: cannot reach %d bits of entropy", opts.MinEntropyBits)
+	}
+	// The epsilon keeps exact multiples, such as 128 bits of hex, from
+	// rounding up.
+	need := int(math.Ceil((float64(opts.MinEntropyBits)+edgeBitsLoss(opts, charset))/perChar - 1e-9))
+	if opts.InstanceLabel != "" {
+		if opts.FingerprintChars > 0 {
+			need += opts.FingerprintChars
+		} else {
+			need++ // The default fingerprint
+		}
+	}
+	if opts.NodeChars > 0 {
+		need += opts.NodeChars
+	}
+	if opts.MinLength < need && opts.MaxLength > 0 {
+		opts.MinLength = need
+	}
+	if opts.Length < need {
+		opts.Length = need
+		if opts.MaxLength > 0 {
+			opts.MaxLength = need
+		}
+	}
+	return opts, nil
+}
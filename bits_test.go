@@ -0,0 +1,187 @@
+package uriuniq
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestBitsPerChar checks the entropy of presets and custom charsets.
+func TestBitsPerChar(t *testing.T) {
+	for charset, bits := range PresetBits {
+		if got := BitsPerChar(charset); math.Abs(got-bits) > 1e-9 {
+			t.Errorf("%q: expected %v, got %v", charset, bits, got)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		charset Charset
+		bits    float64
+	}{
+		{"Empty", "", 0},
+		{"Two Chars", "ab", 1},
+		{"Duplicates", "aabb", 1},
+		{"Biased", "aab", 0.9182958340544896},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := BitsPerChar(tc.charset); math.Abs(got-tc.bits) > 1e-9 {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.bits, got)
+			}
+		})
+	}
+}
+
+// TestMustHaveEntropy verifies the guard panics only on weak options.
+func TestMustHaveEntropy(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 22
+	MustHaveEntropy(opts, 128)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for weak options")
+		}
+	}()
+	opts.ExcludeUppercase = true
+	MustHaveEntropy(opts, 128)
+}
+
+// TestEntropyLengthRange checks the accounting of variable lengths.
+func TestEntropyLengthRange(t *testing.T) {
+	opts := NewOpts()
+	opts.ExcludeUppercase, opts.ExcludeLowercase = true, true
+	opts.MinLength, opts.MaxLength = 10, 13
+	if got, want := Entropy(opts), 2+11.5*NumericBits; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected %f bits, got %f", want, got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for short strings")
+		}
+	}()
+	MustHaveEntropy(opts, 12*NumericBits)
+}
+
+// TestMinEntropyBits checks the lengths raised to reach MinEntropyBits.
+func TestMinEntropyBits(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		min, max int
+	}{
+		{"alphanumeric", Options{MinEntropyBits: 128}, 22, 22},
+		{"hex exact", Options{MinEntropyBits: 128, CustomCharset: "0123456789abcdef"}, 32, 32},
+		{"numeric", Options{MinEntropyBits: 128, CustomCharset: Numeric}, 39, 39},
+		{"longer length kept", Options{Length: 40, MinEntropyBits: 128}, 40, 40},
+		{"range raised", Options{MinLength: 10, MaxLength: 30, MinEntropyBits: 128}, 22, 30},
+		{"range below", Options{MinLength: 10, MaxLength: 12, MinEntropyBits: 128}, 22, 22},
+		{"fingerprint", Options{MinEntropyBits: 128, InstanceLabel: "a"}, 23, 23},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, _, err := prepare(tt.opts)
+			if err != nil {
+				t.Fatalf("prepare failed: %s", err)
+			}
+			if min, max := lengthRange(opts); min != tt.min || max != tt.max {
+				t.Errorf("Expected lengths %d-%d, got %d-%d", tt.min, tt.max, min, max)
+			}
+		})
+	}
+	if _, err := Generate(Options{MinEntropyBits: -1}); CodeOf(err) != CodeInvalidLength {
+		t.Errorf("Expected CodeInvalidLength, got %v", err)
+	}
+	if _, err := BuildOptions(WithMinEntropyBits(0)); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+}
+
+// TestRequireEntropyBits checks that weak Options are rejected with
+// ErrInsufficientEntropy, counting only the shortest strings.
+func TestRequireEntropyBits(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		ok   bool
+	}{
+		{"Six Digits", Options{Length: 6, CustomCharset: Numeric, RequireEntropyBits: 64}, false},
+		{"Enough", Options{Length: 22, RequireEntropyBits: 128}, true},
+		{"Hex Exact", Options{Length: 32, CustomCharset: Hex, RequireEntropyBits: 128}, true},
+		{"Raised", Options{MinEntropyBits: 128, RequireEntropyBits: 128}, true},
+		{"Short Range", Options{MinLength: 8, MaxLength: 30, RequireEntropyBits: 64}, false},
+		{"Fingerprint", Options{Length: 11, InstanceLabel: "a", RequireEntropyBits: 64}, false},
+		{"Runes", Options{Length: 4, CustomCharset: "äöüß", RequireEntropyBits: 16}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewGenerator(tc.opts)
+			if tc.ok && err != nil {
+				t.Errorf("NewGenerator failed: %s", err)
+			}
+			if !tc.ok && (!errors.Is(err, ErrInsufficientEntropy) || CodeOf(err) != CodeLowEntropy) {
+				t.Errorf("Expected ErrInsufficientEntropy, got %v", err)
+			}
+		})
+	}
+	// Sortable IDs count only the chars after the timestamp.
+	if _, err := GenerateSortable(Options{Length: 16, RequireEntropyBits: 64}); !errors.Is(err, ErrInsufficientEntropy) {
+		t.Errorf("Expected ErrInsufficientEntropy for a sortable ID, got %v", err)
+	}
+	if _, err := GenerateSortable(Options{Length: 24, RequireEntropyBits: 64}); err != nil {
+		t.Errorf("GenerateSortable failed: %s", err)
+	}
+	if _, err := Generate(Options{RequireEntropyBits: -1}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+	if _, err := BuildOptions(WithRequireEntropyBits(0)); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+}
+
+// TestCollisionProbability checks the birthday bound against known values
+// and the lengths RecommendedLength picks from it.
+func TestCollisionProbability(t *testing.T) {
+	if p := CollisionProbability(Options{Length: 8}, 1); p != 0 {
+		t.Errorf("Expected 0 for one string, got %g", p)
+	}
+	if p := CollisionProbability(Options{Length: 8, CustomCharset: Hex}, 77163); math.Abs(p-0.5) > 0.01 {
+		t.Errorf("Expected about 0.5 at the 32-bit birthday bound, got %g", p)
+	}
+	if p := CollisionProbability(Options{Length: 8, CustomCharset: "aa"}, 2); p != 1 {
+		t.Errorf("Expected 1 for no entropy, got %g", p)
+	}
+
+	tests := []struct {
+		name    string
+		size    int
+		n       uint64
+		maxProb float64
+		want    int
+	}{
+		{"Billion Alphanumeric", 62, 1e9, 1e-9, 15},
+		{"Hex Birthday", 16, 77163, 0.51, 8},
+		{"Single", 62, 1, 1e-9, 1},
+		{"Small Charset", 1, 100, 0.1, 0},
+		{"Invalid Probability", 62, 100, 1, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := RecommendedLength(tc.size, tc.n, tc.maxProb)
+			if got != tc.want {
+				t.Fatalf("Expected %d, got %d", tc.want, got)
+			}
+			if got > 1 && tc.n > 1 {
+				charset := Charset(Alphanumeric[:tc.size])
+				if p := CollisionProbability(Options{Length: got, CustomCharset: charset}, tc.n); p > tc.maxProb {
+					t.Errorf("Length %d collides with %g", got, p)
+				}
+				if p := CollisionProbability(Options{Length: got - 1, CustomCharset: charset}, tc.n); p <= tc.maxProb {
+					t.Errorf("Length %d is enough already, with %g", got-1, p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package uriuniq
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// DefaultBlockRetries is the number of times a string containing a
+// blocked term is regenerated before giving up.
+const DefaultBlockRetries = 100
+
+// leetFold maps the digits commonly used as letters in blocked terms to
+// those letters.
+var leetFold = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t")
+
+// blocklist is a Wordlist folded for matching.
+type blocklist [][]byte
+
+// newBlocklist folds the words of w, which may be nil.
+func newBlocklist(w Wordlist) blocklist {
+	if w == nil {
+		return nil
+	}
+	var b blocklist
+	for _, word := range w.Words() {
+		b = append(b, []byte(fold(word)))
+	}
+	return b
+}
+
+// fold lowercases s and maps the digits of leetFold.
+func fold(s string) string {
+	return leetFold.Replace(strings.ToLower(s))
+}
+
+// matches reports whether output contains a blocked term.
+func (b blocklist) matches(output []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	folded := []byte(fold(string(output)))
+	for _, word := range b {
+		if bytes.Contains(folded, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// drawUnblocked calls draw until it returns a string without a blocked
+// term, at most Options.BlockRetries more times.
+func drawUnblocked(opts Options, b blocklist, draw func() ([]byte, error)) ([]byte, error) {
+	retries := opts.BlockRetries
+	if retries <= 0 {
+		retries = DefaultBlockRetries
+	}
+	for try := 0; ; try++ {
+		output, err := draw()
+		if err != nil || !b.matches(output) {
+			return output, err
+		}
+		if opts.Sensitive {
+			wipe(output)
+		}
+		if try == retries {
+			return nil, fmt.Errorf("%w: every string contained a blocked term", ErrMaxRetriesExceeded)
+		}
+	}
+}
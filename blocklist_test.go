@@ -0,0 +1,49 @@
+package uriuniq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestBlocklist checks that strings with blocked terms are regenerated
+// by Generate and Generator.
+func TestBlocklist(t *testing.T) {
+	// Of the 8 strings over "ab" of length 3, only "bbb" avoids "a".
+	opts := Options{Length: 3, CustomCharset: "ab", Blocklist: &StaticWordlist{List: []string{"A"}}}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	for i := 0; i < 20; i++ {
+		for _, next := range []func() (string, error){g.Next, func() (string, error) { return Generate(opts) }} {
+			if id, err := next(); err != nil || id != "bbb" {
+				t.Fatalf("Expected bbb, got %q, %v", id, err)
+			}
+		}
+	}
+
+	opts.Blocklist = &StaticWordlist{List: []string{"a", "b"}}
+	opts.BlockRetries = 3
+	if _, err := Generate(opts); !errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Errorf("Expected ErrMaxRetriesExceeded, got %v", err)
+	}
+}
+
+// TestBlocklistMatches checks case and digit folding.
+func TestBlocklistMatches(t *testing.T) {
+	b := newBlocklist(&StaticWordlist{List: []string{"Test"}})
+	for _, s := range []string{"xtestx", "TEST", "t35t", "7e57"} {
+		if !b.matches([]byte(s)) {
+			t.Errorf("Expected %q to match", s)
+		}
+	}
+	if b.matches([]byte("tset")) || blocklist(nil).matches([]byte("test")) {
+		t.Errorf("Unexpected match")
+	}
+	for _, w := range DefaultBlocklist().Words() {
+		if w != strings.ToLower(w) {
+			t.Errorf("Expected lowercase blocklist word, got %q", w)
+		}
+	}
+}
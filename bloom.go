@@ -0,0 +1,91 @@
+package uriuniq
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// BloomChecker is a probabilistic UniquenessChecker backed by a Bloom
+// filter. It never misses a recorded ID, but reports an ID that was not
+// recorded as seen with a small probability, so GenerateUnique may
+// regenerate an ID that was in fact unique. In exchange it needs about 10
+// bits per ID at a 1% false positive rate, whatever the ID length, which
+// suits processes minting hundreds of millions of short IDs.
+type BloomChecker struct {
+	mu     sync.Mutex
+	bits   []uint64
+	m      uint64 // Number of bits
+	hashes int
+}
+
+// NewBloomChecker creates an empty BloomChecker sized so that, after
+// expectedN IDs are recorded, an unrecorded ID is reported as seen with
+// probability fpRate. Recording more IDs raises that probability.
+func NewBloomChecker(expectedN int, fpRate float64) (*BloomChecker, error) {
+	if expectedN <= 0 {
+		return nil, newError(CodeInvalidArgument, "uriuniq: expected count must be positive")
+	}
+	if !(fpRate > 0 && fpRate < 1) {
+		return nil, newError(CodeInvalidArgument, "uriuniq: false positive rate must be in (0, 1)")
+	}
+	m, k := bloomSize(expectedN, fpRate)
+	return &BloomChecker{bits: make([]uint64, (m+63)/64), m: m, hashes: k}, nil
+}
+
+// bloomSize returns the optimal number of bits, -n ln p / (ln 2)², and
+// hash functions, m/n ln 2, for n items at false positive rate p.
+func bloomSize(n int, p float64) (m uint64, k int) {
+	bits := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	m = uint64(bits)
+	if m < 64 {
+		m = 64
+	}
+	k = int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return m, k
+}
+
+// Params returns the number of bits and hash functions of the filter.
+func (c *BloomChecker) Params() (bits uint64, hashes int) {
+	return c.m, c.hashes
+}
+
+// Seen implements UniquenessChecker.
+func (c *BloomChecker) Seen(id string) (bool, error) {
+	h1, h2 := bloomHashes(id)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < c.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % c.m
+		if c.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Record implements UniquenessChecker.
+func (c *BloomChecker) Record(id string) error {
+	h1, h2 := bloomHashes(id)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < c.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % c.m
+		c.bits[bit/64] |= 1 << (bit % 64)
+	}
+	return nil
+}
+
+// bloomHashes returns two independent hashes of id, from which the k
+// indexes are derived by double hashing. h2 is odd, so the indexes do not
+// collapse onto one.
+func bloomHashes(id string) (h1, h2 uint64) {
+	a := fnv.New64a()
+	a.Write([]byte(id))
+	b := fnv.New64()
+	b.Write([]byte(id))
+	return a.Sum64(), b.Sum64() | 1
+}
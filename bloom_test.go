@@ -0,0 +1,50 @@
+package uriuniq
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestBloomChecker checks that recorded IDs are always seen and that the
+// false positive rate stays near the requested one.
+func TestBloomChecker(t *testing.T) {
+	const n = 10000
+	c, err := NewBloomChecker(n, 0.01)
+	if err != nil {
+		t.Fatalf("NewBloomChecker failed: %s", err)
+	}
+	if bits, hashes := c.Params(); bits != 95851 || hashes != 7 {
+		t.Errorf("Expected 95851 bits and 7 hashes, got %d and %d", bits, hashes)
+	}
+	for i := 0; i < n; i++ {
+		c.Record("id" + strconv.Itoa(i))
+	}
+	for i := 0; i < n; i++ {
+		if seen, _ := c.Seen("id" + strconv.Itoa(i)); !seen {
+			t.Fatalf("Recorded ID %d not seen", i)
+		}
+	}
+	falsePositives := 0
+	for i := n; i < 2*n; i++ {
+		if seen, _ := c.Seen("id" + strconv.Itoa(i)); seen {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / n; rate > 0.02 {
+		t.Errorf("False positive rate %.4f, expected about 0.01", rate)
+	}
+
+	g, _ := NewGenerator(NewOpts())
+	if _, err := GenerateUnique(g, c, 10); err != nil {
+		t.Errorf("GenerateUnique failed: %s", err)
+	}
+
+	for _, tc := range []struct {
+		n int
+		p float64
+	}{{0, 0.01}, {10, 0}, {10, 1}} {
+		if _, err := NewBloomChecker(tc.n, tc.p); CodeOf(err) != CodeInvalidArgument {
+			t.Errorf("NewBloomChecker(%d, %g): expected CodeInvalidArgument, got %v", tc.n, tc.p, err)
+		}
+	}
+}
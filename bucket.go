@@ -0,0 +1,40 @@
+package uriuniq
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+)
+
+// Bucket assigns id to one of n buckets, stably under key, for sampling
+// and experiments such as a 1% rollout keyed on request IDs. Buckets are
+// uniform for any IDs, even sequential ones: the bucket is the top of
+// HMAC-SHA256(key, id) scaled to n, which is biased by less than n/2^64.
+// Different keys give independent assignments. It fails if n <= 0.
+func Bucket(id string, n int, key []byte) (int, error) {
+	if n <= 0 {
+		return 0, newError(CodeInvalidArgument, "uriuniq: Bucket needs n > 0")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	v := binary.BigEndian.Uint64(mac.Sum(nil))
+	hi, _ := bits.Mul64(v, uint64(n))
+	return int(hi), nil
+}
+
+// Assign returns the arm of experiment that id is in, stably under key.
+// The experiment name salts the assignment, so an id lands in independent
+// arms across experiments. Arms are equally likely; list an arm several
+// times to weight it. It returns "" if arms is empty.
+func Assign(id, experiment string, arms []string, key []byte) string {
+	if len(arms) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("uriuniq experiment\x00"))
+	mac.Write([]byte(experiment))
+	// arms is not empty, so Bucket cannot fail.
+	b, _ := Bucket(id, len(arms), mac.Sum(nil))
+	return arms[b]
+}
@@ -0,0 +1,75 @@
+package uriuniq
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestBucket checks stability, keying and uniformity of buckets.
+func TestBucket(t *testing.T) {
+	key := []byte("rollout-1")
+	b1, err := Bucket("req-1", 100, key)
+	if err != nil {
+		t.Fatalf("Bucket failed: %s", err)
+	}
+	if b2, _ := Bucket("req-1", 100, key); b1 != b2 {
+		t.Errorf("Bucket is not stable")
+	}
+
+	const n, ids = 10, 20000
+	var counts [n]int
+	same := 0
+	for i := 0; i < ids; i++ {
+		id := fmt.Sprintf("req-%d", i)
+		b, _ := Bucket(id, n, key)
+		if b < 0 || b >= n {
+			t.Fatalf("Bucket %d out of range", b)
+		}
+		counts[b]++
+		if other, _ := Bucket(id, n, []byte("rollout-2")); b == other {
+			same++
+		}
+	}
+	// Each bucket expects 2000 sequential IDs, 5 sigma is about 210.
+	for b, c := range counts {
+		if c < 1790 || c > 2210 {
+			t.Errorf("Bucket %d has %d IDs", b, c)
+		}
+	}
+	if same > ids/n+300 {
+		t.Errorf("Keys are not independent: %d shared buckets", same)
+	}
+	if _, err := Bucket("req-1", 0, key); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected %s for n = 0, got %v", CodeInvalidArgument, err)
+	}
+}
+
+// TestAssign checks that experiments assign independently.
+func TestAssign(t *testing.T) {
+	key := []byte("key")
+	arms := []string{"control", "treatment"}
+	if Assign("user-1", "exp-a", arms, key) != Assign("user-1", "exp-a", arms, key) {
+		t.Errorf("Assign is not stable")
+	}
+
+	const ids = 10000
+	var treated, both int
+	for i := 0; i < ids; i++ {
+		id := fmt.Sprintf("user-%d", i)
+		a := Assign(id, "exp-a", arms, key) == "treatment"
+		b := Assign(id, "exp-b", arms, key) == "treatment"
+		if a {
+			treated++
+		}
+		if a && b {
+			both++
+		}
+	}
+	// Independent arms put a quarter of IDs in both treatments.
+	if treated < 4800 || treated > 5200 || both < 2300 || both > 2700 {
+		t.Errorf("Unexpected split: %d treated, %d in both", treated, both)
+	}
+	if got := Assign("user-1", "exp-a", nil, key); got != "" {
+		t.Errorf("Expected no arm, got %q", got)
+	}
+}
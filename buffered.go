@@ -0,0 +1,104 @@
+package uriuniq
+
+import (
+	"io"
+	"sync"
+)
+
+// Bounds of the entropy buffer picked for a Generator by default.
+const (
+	minEntropyBufferSize = 512
+	maxEntropyBufferSize = 64 << 10
+)
+
+// entropyBuffers pools entropy buffers of MaxBuffLength bytes, the most
+// readSize asks for, so calls without a buffered Generator do not
+// allocate one each.
+var entropyBuffers = sync.Pool{New: func() any {
+	b := make([]byte, MaxBuffLength)
+	return &b
+}}
+
+// getBuffer returns a pooled entropy buffer of n bytes, at most
+// MaxBuffLength. Return it with putBuffer.
+func getBuffer(n int) *[]byte {
+	b := entropyBuffers.Get().(*[]byte)
+	*b = (*b)[:n]
+	return b
+}
+
+// putBuffer zeroes b, so no entropy outlives its call, and returns it to
+// the pool.
+func putBuffer(b *[]byte) {
+	wipe(*b)
+	entropyBuffers.Put(b)
+}
+
+// readSize returns the entropy to request for length chars from a charset
+// of charsetLen chars: the expected need given the rejection rate, plus
+// headroom so one read almost always suffices.
+func readSize(length, charsetLen int) int {
+	maxByte := int(rejectThreshold(charsetLen))
+	n := length * 256 / (maxByte + 1)
+	n += n/4 + 8
+	if n > MaxBuffLength {
+		n = MaxBuffLength
+	}
+	return n
+}
+
+// bufferedSource serves small reads from a larger buffer filled from src,
+// amortizing syscalls over many calls. It is not safe for concurrent use.
+type bufferedSource struct {
+	src  io.Reader
+	buf  []byte
+	r, w int // Unread bytes are buf[r:w]
+}
+
+func newBufferedSource(src io.Reader, size int) *bufferedSource {
+	return &bufferedSource{src: src, buf: make([]byte, size)}
+}
+
+// Read fills p completely. If src fails, buffered bytes are discarded and
+// the error is returned, so no entropy is served across a failure.
+func (b *bufferedSource) Read(p []byte) (int, error) {
+	if len(p) >= len(b.buf) {
+		n := copy(p, b.buf[b.r:b.w])
+		b.Discard()
+		if _, err := io.ReadFull(b.src, p[n:]); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if b.w-b.r < len(p) {
+		n := copy(b.buf, b.buf[b.r:b.w])
+		wipe(b.buf[n:b.w])
+		b.r, b.w = 0, n
+		read, err := io.ReadFull(b.src, b.buf[b.w:])
+		b.w += read
+		if err != nil {
+			b.Discard()
+			return 0, err
+		}
+	}
+	n := copy(p, b.buf[b.r:b.w])
+	wipe(b.buf[b.r : b.r+n])
+	b.r += n
+	return n, nil
+}
+
+// Discard zeroes and drops all buffered bytes.
+func (b *bufferedSource) Discard() {
+	wipe(b.buf)
+	b.r, b.w = 0, 0
+}
+
+// unread returns the tail p of the last Read to the buffer, to be served
+// again. It is a no-op if the last Read bypassed the buffer.
+func (b *bufferedSource) unread(p []byte) {
+	if len(p) > b.r {
+		return
+	}
+	b.r -= len(p)
+	copy(b.buf[b.r:], p)
+}
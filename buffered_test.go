@@ -0,0 +1,138 @@
+package uriuniq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// countingReader counts the reads made on an underlying reader.
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.reads++
+	return c.r.Read(p)
+}
+
+// TestBufferedSource checks the byte stream and error handling.
+func TestBufferedSource(t *testing.T) {
+	data := make([]byte, 60)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	b := newBufferedSource(bytes.NewReader(data), 16)
+	var got []byte
+	for _, n := range []int{3, 10, 7, 20, 1} {
+		p := make([]byte, n)
+		if _, err := b.Read(p); err != nil {
+			t.Fatalf("Read %d failed: %s", n, err)
+		}
+		got = append(got, p...)
+	}
+	if !bytes.Equal(got, data[:len(got)]) {
+		t.Errorf("Stream mismatch: %v", got)
+	}
+
+	if _, err := b.Read(make([]byte, 15)); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if _, err := b.Read(make([]byte, 15)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected short read error, got %v", err)
+	}
+	if b.r != 0 || b.w != 0 {
+		t.Errorf("Expected buffer to be discarded after error")
+	}
+}
+
+// TestGeneratorBuffering verifies that calls share reads from the source.
+func TestGeneratorBuffering(t *testing.T) {
+	src := &countingReader{r: CryptoRand.Reader}
+	opts := NewOpts()
+	opts.EntropySource = src
+	gen, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	for i := 0; i < 32; i++ {
+		if _, err := gen.Next(); err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+	}
+	if src.reads > 2 {
+		t.Errorf("Expected at most 2 reads, got %d", src.reads)
+	}
+
+	gen.Discard()
+	reads := src.reads
+	if _, err := gen.Next(); err != nil {
+		t.Fatalf("Next failed: %s", err)
+	}
+	if src.reads != reads+1 {
+		t.Errorf("Expected a fresh read after Discard")
+	}
+
+	opts.EntropyBufferSize = -1
+	src.reads = 0
+	gen, err = NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := gen.Next(); err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+	}
+	if src.reads < 4 {
+		t.Errorf("Expected unbuffered reads, got %d", src.reads)
+	}
+}
+
+// BenchmarkGeneratorNext benchmarks Next with the default buffering.
+func BenchmarkGeneratorNext(b *testing.B) {
+	gen, err := NewGenerator(NewOpts())
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := gen.Next(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestPooledBuffers checks that unbuffered calls take their entropy buffer
+// from the pool and return it zeroed.
+func TestPooledBuffers(t *testing.T) {
+	g, err := NewGenerator(Options{Length: 16, EntropyBufferSize: -1})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = g.AppendTo(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %.1f", allocs)
+	}
+	// One allocation is left for the output.
+	allocs = testing.AllocsPerRun(100, func() {
+		randBytes(Options{Length: 16, MaxBadReads: DefaultMaxBadReads}, []byte(Alphanumeric))
+	})
+	if allocs > 1 {
+		t.Errorf("Expected at most 1 allocation for randBytes, got %.1f", allocs)
+	}
+
+	b := getBuffer(32)
+	copy(*b, "entropy")
+	putBuffer(b)
+	if !bytes.Equal(*b, make([]byte, 32)) {
+		t.Errorf("Expected a zeroed buffer, got %q", *b)
+	}
+	if b := getBuffer(MaxBuffLength); len(*b) != MaxBuffLength {
+		t.Errorf("Expected %d bytes, got %d", MaxBuffLength, len(*b))
+	}
+}
@@ -0,0 +1,117 @@
+package uriuniq
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CampaignMarkerLength is the number of chars every campaign code starts
+// with to name its campaign.
+const CampaignMarkerLength = 3
+
+// Campaign is the namespace of one time-boxed promotion. Its codes are its
+// Marker followed by random chars from its Charset, so codes of campaigns
+// with different markers never overlap, and a code names its campaign.
+type Campaign struct {
+	Name       string
+	Start, End time.Time
+	Marker     string  // CampaignMarkerLength chars of Alphanumeric
+	Charset    Charset // Alphanumeric permuted per campaign
+
+	now func() time.Time
+}
+
+// CampaignNamespace derives the namespace of a campaign from its name,
+// time window and key. The same arguments always yield the same Marker and
+// Charset, so a namespace can be reproduced anywhere the key is known.
+// Markers are derived, not assigned, so two campaigns may share one with
+// a chance of 1 in 62^3; add campaigns to a CampaignRegistry to rule that
+// out.
+func CampaignNamespace(name string, start, end time.Time, key []byte) (*Campaign, error) {
+	if name == "" || !start.Before(end) {
+		return nil, newError(CodeInvalidArgument, "uriuniq: campaign needs a name and start before end")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	var window [16]byte
+	binary.BigEndian.PutUint64(window[:8], uint64(start.Unix()))
+	binary.BigEndian.PutUint64(window[8:], uint64(end.Unix()))
+	mac.Write([]byte{0})
+	mac.Write(window[:])
+	sum := mac.Sum(nil)
+
+	marker := make([]byte, CampaignMarkerLength)
+	v := binary.BigEndian.Uint64(sum)
+	for i := range marker {
+		marker[i] = Alphanumeric[v%62]
+		v /= 62
+	}
+	return &Campaign{
+		Name:    name,
+		Start:   start,
+		End:     end,
+		Marker:  string(marker),
+		Charset: Reorder(Alphanumeric, sum[8:]),
+		now:     time.Now,
+	}, nil
+}
+
+// Issue creates a code of the campaign with length random chars. It fails
+// outside the campaign window [Start, End).
+func (c *Campaign) Issue(length int) (string, error) {
+	if now := c.now(); now.Before(c.Start) || !now.Before(c.End) {
+		return "", errorf(CodeTokenExpired, "uriuniq: campaign %s is not running", c.Name)
+	}
+	output, err := generate(Options{Length: length, CustomCharset: c.Charset})
+	if err != nil {
+		return "", err
+	}
+	return c.Marker + string(output), nil
+}
+
+// Owns reports whether code has the marker of c.
+func (c *Campaign) Owns(code string) bool {
+	return strings.HasPrefix(code, c.Marker)
+}
+
+// CampaignRegistry holds campaigns with distinct markers, so their codes
+// are provably disjoint. A CampaignRegistry is safe for concurrent use.
+type CampaignRegistry struct {
+	mu       sync.RWMutex
+	byMarker map[string]*Campaign
+}
+
+// NewCampaignRegistry creates an empty CampaignRegistry.
+func NewCampaignRegistry() *CampaignRegistry {
+	return &CampaignRegistry{byMarker: make(map[string]*Campaign)}
+}
+
+// Add registers c. It fails if another campaign has the same marker; pick
+// another name for one of them.
+func (r *CampaignRegistry) Add(c *Campaign) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if other, ok := r.byMarker[c.Marker]; ok && other != c {
+		return errorf(CodeInvalidArgument, "uriuniq: campaigns %s and %s share marker %s", other.Name, c.Name, c.Marker)
+	}
+	r.byMarker[c.Marker] = c
+	return nil
+}
+
+// CampaignOf returns the campaign that issued code, going by its marker.
+func (r *CampaignRegistry) CampaignOf(code string) (*Campaign, error) {
+	if len(code) < CampaignMarkerLength {
+		return nil, newError(CodeInvalidLength, "uriuniq: code shorter than a campaign marker")
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.byMarker[code[:CampaignMarkerLength]]
+	if !ok {
+		return nil, newError(CodeNoFormatMatch, "uriuniq: code of no known campaign")
+	}
+	return c, nil
+}
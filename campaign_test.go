@@ -0,0 +1,55 @@
+package uriuniq
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCampaignNamespace checks derivation, issuance and attribution.
+func TestCampaignNamespace(t *testing.T) {
+	key := []byte("key")
+	start := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	spring, err := CampaignNamespace("spring", start, end, key)
+	if err != nil {
+		t.Fatalf("CampaignNamespace failed: %s", err)
+	}
+	again, _ := CampaignNamespace("spring", start, end, key)
+	if again.Marker != spring.Marker || again.Charset != spring.Charset {
+		t.Errorf("Expected a reproducible namespace")
+	}
+	fall, _ := CampaignNamespace("fall", start, end, key)
+	if fall.Marker == spring.Marker || len(spring.Marker) != CampaignMarkerLength {
+		t.Errorf("Unexpected markers %q and %q", spring.Marker, fall.Marker)
+	}
+
+	registry := NewCampaignRegistry()
+	for _, c := range []*Campaign{spring, fall} {
+		c.now = func() time.Time { return start.Add(time.Hour) }
+		if err := registry.Add(c); err != nil {
+			t.Fatalf("Add failed: %s", err)
+		}
+	}
+	code, err := spring.Issue(8)
+	if err != nil || len(code) != CampaignMarkerLength+8 || !spring.Owns(code) || fall.Owns(code) {
+		t.Fatalf("Unexpected code %q, %v", code, err)
+	}
+	if c, err := registry.CampaignOf(code); err != nil || c != spring {
+		t.Errorf("Expected spring, got %v, %v", c, err)
+	}
+	if _, err := registry.CampaignOf("~~~abc"); CodeOf(err) != CodeNoFormatMatch {
+		t.Errorf("Expected unknown campaign, got %v", err)
+	}
+
+	clash := &Campaign{Name: "clash", Marker: spring.Marker}
+	if err := registry.Add(clash); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected marker clash, got %v", err)
+	}
+	spring.now = func() time.Time { return end }
+	if _, err := spring.Issue(8); CodeOf(err) != CodeTokenExpired {
+		t.Errorf("Expected campaign to be over, got %v", err)
+	}
+	if _, err := CampaignNamespace("x", end, start, key); err == nil {
+		t.Errorf("Expected error for inverted window")
+	}
+}
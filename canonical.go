@@ -0,0 +1,88 @@
+package uriuniq
+
+import (
+	"regexp"
+	"strings"
+)
+
+// crockford maps the chars Crockford base32 decodes as aliases.
+var crockford = strings.NewReplacer("I", "1", "L", "1", "O", "0")
+
+// Canonical maps equivalent representations of an ID in format to a single
+// string, so IDs can be indexed and compared as stored. It folds case,
+// decodes Crockford aliases of ULIDs, adds the hyphens of a UUID written
+// without them and removes grouping separators the format does not contain.
+// The result is checked against format.
+func Canonical(id string, format Format) (string, error) {
+	switch format.Name {
+	case FormatULID.Name:
+		id = crockford.Replace(strings.ToUpper(stripSeparators(id, "-")))
+	case FormatUUIDv7.Name:
+		id = strings.ToLower(id)
+		id = strings.TrimPrefix(id, "urn:uuid:")
+		id = strings.TrimSuffix(strings.TrimPrefix(id, "{"), "}")
+		if id = stripSeparators(id, "-"); len(id) == 32 {
+			id = id[:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:]
+		}
+	case FormatTypeID.Name:
+		id = strings.ToLower(id)
+	default:
+		if format.Options != nil {
+			id = canonicalOptions(id, *format.Options)
+		}
+	}
+
+	re, err := regexp.Compile(format.Pattern)
+	if err != nil {
+		return "", err
+	}
+	if len(id) < format.MinLength || len(id) > format.MaxLength || !re.MatchString(id) {
+		return "", ErrNoFormatMatch
+	}
+	return id, nil
+}
+
+// canonicalOptions folds id to the case of the charset of opts and removes
+// grouping separators not in it. The prefix of opts is kept as is.
+func canonicalOptions(id string, opts Options) string {
+	_, charset, err := prepare(opts)
+	if err != nil {
+		return id
+	}
+	prefix := idPrefix(opts)
+	if prefix != "" && strings.HasPrefix(id, prefix) {
+		return prefix + canonicalOptions(id[len(prefix):], Options{CustomCharset: Charset(charset), Length: opts.Length})
+	}
+	var lower, upper bool
+	for _, c := range charset {
+		lower = lower || 'a' <= c && c <= 'z'
+		upper = upper || 'A' <= c && c <= 'Z'
+	}
+	switch {
+	case lower && !upper:
+		id = strings.ToLower(id)
+	case upper && !lower:
+		id = strings.ToUpper(id)
+	}
+	var seps []byte
+	for _, c := range []byte("- ") {
+		if strings.IndexByte(string(charset), c) < 0 {
+			seps = append(seps, c)
+		}
+	}
+	return stripSeparators(id, string(seps))
+}
+
+// stripSeparators removes the chars of seps from s.
+func stripSeparators(s, seps string) string {
+	if seps == "" || !strings.ContainsAny(s, seps) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if !strings.ContainsRune(seps, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
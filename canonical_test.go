@@ -0,0 +1,60 @@
+package uriuniq
+
+import "testing"
+
+// TestCanonical checks that equivalent representations map to one form.
+func TestCanonical(t *testing.T) {
+	lower := NewOpts()
+	lower.Length = 8
+	lower.ExcludeUppercase = true
+	lowerFormat, err := OptionsFormat("lower", lower)
+	if err != nil {
+		t.Fatalf("OptionsFormat failed: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		format   Format
+		inputs   []string
+		expected string
+	}{
+		{"ULID", FormatULID,
+			[]string{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01arz3ndektsv4rrffq69g5fav", "OIARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3ND-EKTSV4RR-FFQ69G5FAV"},
+			"01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{"UUIDv7", FormatUUIDv7,
+			[]string{"01890a5d-ac96-774b-bcce-b302099a8057", "01890A5DAC96774BBCCEB302099A8057", "{01890a5d-ac96-774b-bcce-b302099a8057}", "urn:uuid:01890a5d-ac96-774b-bcce-b302099a8057"},
+			"01890a5d-ac96-774b-bcce-b302099a8057"},
+		{"Options", lowerFormat,
+			[]string{"ab12cd34", "AB12CD34", "ab12-cd34", "ab12 cd34"},
+			"ab12cd34"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, input := range tc.inputs {
+				got, err := Canonical(input, tc.format)
+				if err != nil || got != tc.expected {
+					t.Errorf("Canonical(%q) = %q, %v, expected %q", input, got, err, tc.expected)
+				}
+			}
+		})
+	}
+
+	if _, err := Canonical("01ARZ3NDEKTSV4RRFFQ69G5FAU!", FormatULID); err == nil {
+		t.Errorf("Expected error for invalid ULID")
+	}
+	if _, err := Canonical("ab12cd3", lowerFormat); err == nil {
+		t.Errorf("Expected error for short ID")
+	}
+}
+
+// TestCanonicalPrefix checks that the prefix of an Options format is kept
+// while the random part is folded.
+func TestCanonicalPrefix(t *testing.T) {
+	format, err := OptionsFormat("customer", Options{Length: 6, Prefix: "Cus", ExcludeUppercase: true})
+	if err != nil {
+		t.Fatalf("OptionsFormat failed: %s", err)
+	}
+	if got, err := Canonical("Cus_AB-12CD", format); err != nil || got != "Cus_ab12cd" {
+		t.Errorf("Expected Cus_ab12cd, got %q, %v", got, err)
+	}
+}
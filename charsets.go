@@ -0,0 +1,158 @@
+package uriuniq
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+// AmbiguousChars are the chars dropped by Options.ExcludeAmbiguous: those
+// most often confused when read aloud or typed from a screen, 0 with O and
+// o, and 1 with l and I.
+const AmbiguousChars Charset = "0Oo1lI"
+
+// Well-known charsets, in the order of their standard encodings, so
+// IndexOf yields the digit values those encodings use.
+const (
+	// Base58 is the Bitcoin alphabet: alphanumerics without 0, O, I and l,
+	// which are easily confused. 58 chars, about 5.86 bits each.
+	Base58 Charset = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	// Base32Crockford is Douglas Crockford's base32: digits and uppercase
+	// letters without I, L, O and U. Decoders accept lowercase and read I
+	// and L as 1 and O as 0. 32 chars, 5 bits each. Same as
+	// ShortCodeCharset.
+	Base32Crockford Charset = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// Base64URL is the URL- and filename-safe alphabet of RFC 4648. 64
+	// chars, 6 bits each; all are URI unreserved chars.
+	Base64URL Charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	// Hex is lowercase hexadecimal, as printed by encoding/hex. 16 chars, 4
+	// bits each.
+	Hex Charset = "0123456789abcdef"
+)
+
+var (
+	charsetsMu sync.RWMutex
+	charsets   = map[string]Charset{
+		"alphanumeric": Alphanumeric,
+		"base62":       Alphanumeric,
+		"lowercase":    Lowercase,
+		"uppercase":    Uppercase,
+		"numeric":      Numeric,
+		"hex":          Hex,
+		"base58":       Base58,
+		"base32":       Base32Crockford,
+		"base64url":    Base64URL,
+		"nanoid":       NanoIDCharset,
+	}
+)
+
+// RegisterCharset makes cs available under name to LookupCharset and
+// Options.CharsetName, so services can pick charsets by name from their
+// configuration. The presets are registered as alphanumeric (also base62),
+// lowercase, uppercase, numeric, hex, base58, base32 (Crockford),
+// base64url and nanoid. Registering a name again replaces its charset; cs must have
+// at least 2 distinct chars.
+func RegisterCharset(name string, cs Charset) error {
+	if name == "" {
+		return newError(CodeInvalidArgument, "uriuniq: empty charset name")
+	}
+	if len(cs) < 2 {
+		return newError(CodeCharsetSize, "uriuniq: charset needs at least 2 chars")
+	}
+	var seen [256]bool
+	for i := 0; i < len(cs); i++ {
+		if seen[cs[i]] {
+			return errorf(CodeDuplicateChar, "uriuniq: duplicate char %q in charset", cs[i])
+		}
+		seen[cs[i]] = true
+	}
+	charsetsMu.Lock()
+	defer charsetsMu.Unlock()
+	charsets[name] = cs
+	return nil
+}
+
+// LookupCharset returns the charset registered under name.
+func LookupCharset(name string) (Charset, bool) {
+	charsetsMu.RLock()
+	defer charsetsMu.RUnlock()
+	cs, ok := charsets[name]
+	return cs, ok
+}
+
+// Contains reports whether r is a char of c.
+func (c Charset) Contains(r rune) bool {
+	return strings.ContainsRune(string(c), r)
+}
+
+// Dedupe returns c with every char after its first occurrence removed.
+func (c Charset) Dedupe() Charset {
+	return c.Union()
+}
+
+// Union returns the chars of c followed by those of others not seen
+// before, without duplicates, as in Lowercase.Union(Numeric, "-_").
+func (c Charset) Union(others ...Charset) Charset {
+	seen := make(map[rune]bool)
+	var b strings.Builder
+	for _, cs := range append([]Charset{c}, others...) {
+		for _, r := range string(cs) {
+			if !seen[r] {
+				seen[r] = true
+				b.WriteRune(r)
+			}
+		}
+	}
+	return Charset(b.String())
+}
+
+// Remove returns c without the chars of chars, as in
+// Alphanumeric.Remove(AmbiguousChars).
+func (c Charset) Remove(chars Charset) Charset {
+	var b strings.Builder
+	for _, r := range string(c) {
+		if !chars.Contains(r) {
+			b.WriteRune(r)
+		}
+	}
+	return Charset(b.String())
+}
+
+// EntropyPerChar returns the entropy in bits of one char drawn from c, as
+// BitsPerChar does, counting the runes of multi-byte charsets.
+func (c Charset) EntropyPerChar() float64 {
+	if !isRuneCharset(c) {
+		return BitsPerChar(c)
+	}
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range string(c) {
+		counts[r]++
+		n++
+	}
+	bits := 0.0
+	for _, k := range counts {
+		p := float64(k) / float64(n)
+		bits -= p * math.Log2(p)
+	}
+	return bits
+}
+
+// Validate checks that c can be drawn from without bias: it needs at
+// least 2 chars, at most 256 unless made of multi-byte runes, and no
+// duplicates, which would make their chars more likely.
+func (c Charset) Validate() error {
+	n := 0
+	seen := make(map[rune]bool)
+	for _, r := range string(c) {
+		if seen[r] {
+			return errorf(CodeDuplicateChar, "uriuniq: duplicate char %q in charset", r)
+		}
+		seen[r] = true
+		n++
+	}
+	if n < 2 || n > 256 && !isRuneCharset(c) {
+		return errorf(CodeCharsetSize, "uriuniq: charset has %d chars, want 2-256", n)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package uriuniq
+
+import (
+	"encoding/base64"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestCharsets checks the sizes, uniqueness and properties of the
+// well-known charsets.
+func TestCharsets(t *testing.T) {
+	tests := []struct {
+		name    string
+		charset Charset
+		size    int
+		absent  string
+	}{
+		{"Base58", Base58, 58, "0OIl+/"},
+		{"Base32Crockford", Base32Crockford, 32, "ILOU"},
+		{"Base64URL", Base64URL, 64, "+/="},
+		{"Hex", Hex, 16, "ABCDEF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.charset) != tt.size {
+				t.Errorf("Expected %d chars, got %d", tt.size, len(tt.charset))
+			}
+			if _, err := BuildOptions(WithCharset(tt.charset)); err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if strings.ContainsAny(string(tt.charset), tt.absent) {
+				t.Errorf("Expected none of %q", tt.absent)
+			}
+			if bits := PresetBits[tt.charset]; math.Abs(bits-BitsPerChar(tt.charset)) > 1e-9 {
+				t.Errorf("Expected PresetBits %f, got %f", BitsPerChar(tt.charset), bits)
+			}
+		})
+	}
+	for i := 0; i < 64; i++ {
+		if c := base64.RawURLEncoding.EncodeToString([]byte{byte(i << 2)})[0]; Base64URL[i] != c {
+			t.Errorf("Expected Base64URL[%d] = %q, got %q", i, c, Base64URL[i])
+		}
+	}
+	if Base32Crockford != ShortCodeCharset {
+		t.Errorf("Expected Base32Crockford to equal ShortCodeCharset")
+	}
+}
+
+// TestExcludeAmbiguous checks dropping AmbiguousChars alone and combined
+// with the other exclusions and CustomCharset.
+func TestExcludeAmbiguous(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want Charset
+	}{
+		{"all", Options{ExcludeAmbiguous: true}, "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"},
+		{"numeric only", Options{ExcludeAmbiguous: true, ExcludeLowercase: true, ExcludeUppercase: true}, "23456789"},
+		{"custom", Options{ExcludeAmbiguous: true, CustomCharset: "0a1b"}, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Length = 8
+			if got, err := CharsetOf(tt.opts); err != nil || got != tt.want {
+				t.Errorf("Expected %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+	opts, err := BuildOptions(ExcludeAmbiguous(), WithLength(200))
+	if err != nil {
+		t.Fatalf("BuildOptions failed: %s", err)
+	}
+	id, err := Generate(opts)
+	if err != nil || strings.ContainsAny(id, string(AmbiguousChars)) {
+		t.Errorf("Unexpected ID %q, %v", id, err)
+	}
+}
+
+// TestRegisterCharset checks named charsets in LookupCharset and
+// Options.CharsetName.
+func TestRegisterCharset(t *testing.T) {
+	if cs, ok := LookupCharset("base58"); !ok || cs != Base58 {
+		t.Errorf("Expected Base58, got %q, %t", cs, ok)
+	}
+	if err := RegisterCharset("test-vowels", "aeiou"); err != nil {
+		t.Fatalf("RegisterCharset failed: %s", err)
+	}
+	t.Cleanup(func() {
+		charsetsMu.Lock()
+		delete(charsets, "test-vowels")
+		charsetsMu.Unlock()
+	})
+	s, err := Generate(Options{Length: 20, CharsetName: "test-vowels"})
+	if err != nil || checkCharset(s, "aeiou") != nil {
+		t.Errorf("Expected vowels, got %q, %v", s, err)
+	}
+	g, err := NewGenerator(Options{Length: 20, CharsetName: "hex", CheckChar: true})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	if id, _ := g.Next(); checkCharset(id, Hex) != nil {
+		t.Errorf("Expected hex, got %q", id)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		code Code
+	}{
+		{"Empty Name", RegisterCharset("", "ab"), CodeInvalidArgument},
+		{"Too Small", RegisterCharset("one", "a"), CodeCharsetSize},
+		{"Duplicates", RegisterCharset("dup", "aba"), CodeDuplicateChar},
+		{"Unknown Name", Validate("abc", Options{Length: 3, CharsetName: "bogus"}), CodeInvalidArgument},
+		{"Both", Validate("abc", Options{Length: 3, CharsetName: "hex", CustomCharset: Hex}), CodeInvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if CodeOf(tc.err) != tc.code {
+				t.Errorf("Expected %s, got %v", tc.code, tc.err)
+			}
+		})
+	}
+}
+
+// TestCharsetHelpers checks building charsets from the presets.
+func TestCharsetHelpers(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Charset
+		want Charset
+	}{
+		{"Union", Hex.Union(Numeric, "-_a"), "0123456789abcdef-_"},
+		{"Remove", Alphanumeric.Remove(AmbiguousChars + Uppercase), "abcdefghijkmnpqrstuvwxyz23456789"},
+		{"Dedupe", Charset("abcabd").Dedupe(), "abcd"},
+		{"Runes", Charset("äöü").Union("aä").Remove("ö"), "äüa"},
+	}
+	for _, tc := range tests {
+		if tc.got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, tc.got)
+		}
+	}
+	if !Base58.Contains('z') || Base58.Contains('0') || !Charset("äö").Contains('ö') {
+		t.Errorf("Unexpected Contains results")
+	}
+	if bits := Base64URL.EntropyPerChar(); bits != 6 {
+		t.Errorf("Expected 6 bits, got %g", bits)
+	}
+	if bits := Charset("äöüß").EntropyPerChar(); bits != 2 {
+		t.Errorf("Expected 2 bits for 4 runes, got %g", bits)
+	}
+
+	for cs, code := range map[Charset]Code{Alphanumeric: "", "äöü": "", "abca": CodeDuplicateChar, "a": CodeCharsetSize, "": CodeCharsetSize} {
+		if err := cs.Validate(); CodeOf(err) != code {
+			t.Errorf("Validate(%q): expected %q, got %v", cs, code, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package uriuniq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrMissingPrefix is returned by Validate for an ID without the
+// Options.Prefix and separator.
+var ErrMissingPrefix = newError(CodeMissingPrefix, "uriuniq: missing prefix")
+
+// Validate checks that s can have been generated with opts and returns
+// what failed otherwise: ErrMissingPrefix, an error with CodeInvalidLength,
+// a *ParseError indexed into s for a char outside the charset or a first
+// or last char NoLeadingDigit or DNSLabel forbid, an error with CodeInvalidArgument
+// for too few chars of a class required by Options.MinDigits and the like,
+// or ErrBadCheckChar with Options.CheckChar. IDs generated with EscapePercent must be unescaped
+// first; the group separators of Options.GroupSize must be in place.
+// Errors of opts itself are returned as is. Inputs far longer than any ID
+// of opts are rejected with ErrInputTooLong before their chars are read.
+func Validate(s string, opts Options) error {
+	if isRuneCharset(opts.CustomCharset) {
+		return validateRunes(s, opts)
+	}
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return err
+	}
+	if err := checkInputLength(s, inputLimit(opts)); err != nil {
+		return err
+	}
+	prefix := idPrefix(opts)
+	if !strings.HasPrefix(s, prefix) {
+		return fmt.Errorf("%w %q", ErrMissingPrefix, prefix)
+	}
+	id, err := ungroup(opts, s[len(prefix):])
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Index += len(prefix)
+		}
+		return err
+	}
+	min, max := lengthRange(opts)
+	n := checkChars(opts)
+	if len(id) < min+n || len(id) > max+n {
+		return errorf(CodeInvalidLength, "uriuniq: length %d, want %d-%d", len(id), min+n, max+n)
+	}
+	if err := checkCharset(id, Charset(charset)); err != nil {
+		perr := err.(*ParseError)
+		perr.Index = len(prefix) + groupedIndex(opts, perr.Index)
+		return err
+	}
+	if opts.NoLeadingDigit && !inCharset(id[0], Charset(leadingSet(opts, charset))) {
+		return &ParseError{Index: len(prefix), Char: rune(id[0]), Charset: Charset(leadingSet(opts, charset))}
+	}
+	if opts.DNSLabel && len(id) > 1 && id[len(id)-1] == '-' {
+		return &ParseError{Index: len(s) - 1, Char: '-', Charset: Charset(trailingSet(charset))}
+	}
+	if composed(opts) {
+		if err := checkCounts(opts, id[fixedChars(opts):len(id)-n]); err != nil {
+			return err
+		}
+	}
+	if n > 0 && luhnMod(id[:len(id)-1], Charset(charset)) != id[len(id)-1] {
+		return ErrBadCheckChar
+	}
+	return nil
+}
+
+// Verify reports whether Validate accepts id, catching most mistyped and
+// truncated IDs without a database lookup.
+func Verify(id string, opts Options) bool {
+	return Validate(id, opts) == nil
+}
+
+// checkChars returns the number of check chars of strings generated with
+// opts.
+func checkChars(opts Options) int {
+	if opts.CheckChar {
+		return 1
+	}
+	return 0
+}
+
+// appendCheckChar appends the check char of output to it if opts asks
+// for one.
+func appendCheckChar(opts Options, charset, output []byte) []byte {
+	if !opts.CheckChar {
+		return output
+	}
+	out := append(output, luhnMod(string(output), Charset(charset)))
+	if opts.Sensitive && cap(output) == len(output) {
+		wipe(output) // Copied by append
+	}
+	return out
+}
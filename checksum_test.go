@@ -0,0 +1,134 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCheckCharOption checks that IDs with a check char verify and that
+// single-char typos and truncation are caught.
+func TestCheckCharOption(t *testing.T) {
+	opts := Options{Length: 12, CheckChar: true, Prefix: "ord"}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	re, err := Pattern(opts)
+	if err != nil {
+		t.Fatalf("Pattern failed: %s", err)
+	}
+	for i := 0; i < 50; i++ {
+		id, err := g.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+		if len(id) != 4+13 || !Verify(id, opts) || !re.MatchString(id) {
+			t.Fatalf("Expected %q to verify", id)
+		}
+		if Verify(id[:len(id)-1], opts) {
+			t.Errorf("Truncated %q verifies", id)
+		}
+		typo := []byte(id)
+		if typo[6] == 'a' {
+			typo[6] = 'b'
+		} else {
+			typo[6] = 'a'
+		}
+		if Verify(string(typo), opts) {
+			t.Errorf("Typo %q of %q verifies", typo, id)
+		}
+	}
+	id, err := Generate(opts)
+	if err != nil || !Verify(id, opts) {
+		t.Errorf("Expected Generate output %q to verify, %v", id, err)
+	}
+}
+
+// TestCheckCharCharsetSize checks that odd charset sizes, for which Luhn
+// mod N misses single-char errors, are rejected, and that even ones catch
+// every single-char error.
+func TestCheckCharCharsetSize(t *testing.T) {
+	odd := Options{Length: 6, CustomCharset: "abcde", CheckChar: true}
+	if _, err := NewGenerator(odd); CodeOf(err) != CodeCharsetSize {
+		t.Errorf("Expected %s for an odd charset, got %v", CodeCharsetSize, err)
+	}
+	if err := Validate("abcdea", odd); CodeOf(err) != CodeCharsetSize {
+		t.Errorf("Expected %s from Validate, got %v", CodeCharsetSize, err)
+	}
+	if _, err := GenerateDual(Options{Length: 6, CustomCharset: "abcde"}, DisplayFormat{CheckChar: true}); CodeOf(err) != CodeCharsetSize {
+		t.Errorf("Expected %s from GenerateDual, got %v", CodeCharsetSize, err)
+	}
+
+	even := Options{Length: 6, CustomCharset: "abcdef", CheckChar: true}
+	g, err := NewGenerator(even)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	for i := 0; i < 20; i++ {
+		id, _ := g.Next()
+		for pos := range id {
+			for _, c := range []byte(even.CustomCharset) {
+				if c == id[pos] {
+					continue
+				}
+				typo := []byte(id)
+				typo[pos] = c
+				if Verify(string(typo), even) {
+					t.Fatalf("Typo %q of %q verifies", typo, id)
+				}
+			}
+		}
+	}
+}
+
+// TestVerify checks Verify without a check char.
+func TestVerify(t *testing.T) {
+	opts := Options{Length: 4, CustomCharset: Numeric}
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"1234", true},
+		{"123", false},
+		{"12345", false},
+		{"12a4", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := Verify(tt.id, opts); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+	if Verify("1234", Options{Length: 4, MinLength: 5, MaxLength: 2}) {
+		t.Errorf("Expected invalid Options to fail")
+	}
+}
+
+// TestValidate checks the error reported for each kind of invalid ID.
+func TestValidate(t *testing.T) {
+	opts := Options{Length: 4, CustomCharset: Numeric, Prefix: "inv", CheckChar: true}
+	valid := "inv_1234" + string(luhnMod("1234", Numeric))
+	tests := []struct {
+		name string
+		id   string
+		code Code
+	}{
+		{"valid", valid, ""},
+		{"no prefix", valid[4:], CodeMissingPrefix},
+		{"short", valid[:len(valid)-1], CodeInvalidLength},
+		{"char", "inv_12a4" + valid[8:], CodeInvalidChar},
+		{"check char", "inv_1243" + valid[8:], CodeBadCheckChar},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.id, opts); CodeOf(err) != tt.code {
+				t.Errorf("Expected %q, got %v", tt.code, err)
+			}
+		})
+	}
+	var perr *ParseError
+	if err := Validate("inv_12a45", opts); !errors.As(err, &perr) || perr.Index != 6 {
+		t.Errorf("Expected *ParseError at index 6, got %v", err)
+	}
+}
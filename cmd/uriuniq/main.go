@@ -0,0 +1,235 @@
+// Command uriuniq works with uriuniq ID policies from the command line.
+//
+// Usage:
+//
+//	uriuniq gen [-length 16] [-count 1] [-charset alphanumeric] [-exclude ambiguous] [-prefix ord] [-sortable]
+//	uriuniq lint --config policy.json
+//	uriuniq stats [-length 16] [-samples 100000] [-charset alphanumeric]
+//
+// gen prints count IDs, one per line. -charset names a charset registered
+// with uriuniq.RegisterCharset, such as alphanumeric, lowercase, uppercase,
+// numeric, hex, base58, base32, base64url or nanoid; -exclude is a comma-separated list of numeric, lowercase,
+// uppercase and ambiguous. -sortable starts every ID with a timestamp.
+//
+// The policy file holds uriuniq.Options as JSON, such as
+// {"Length": 12, "CustomCharset": "abc123"}. lint prints every finding
+// and exits with status 1 if any has error severity, so it can gate CI.
+//
+// stats generates samples IDs and prints the chi-square test of their
+// chars for uniformity, overall and at the worst position. It exits with
+// status 1 if the output is biased at significance 0.001.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/laofun/uriuniq"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status. A
+// panic is reported with its stack and exits with status 1.
+func run(args []string, stdout, stderr io.Writer) int {
+	status := 1
+	uriuniq.Recover(func() error {
+		status = command(args, stdout, stderr)
+		return nil
+	}, func(p *uriuniq.PanicError) {
+		fmt.Fprintf(stderr, "%s\n%s", p, p.Stack)
+	})
+	return status
+}
+
+// command runs the command named by args[0].
+func command(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: uriuniq gen [flags] | uriuniq lint --config policy.json | uriuniq stats [flags]")
+		return 2
+	}
+	switch args[0] {
+	case "gen":
+		return gen(args[1:], stdout, stderr)
+	case "lint":
+		return lint(args[1:], stdout, stderr)
+	case "stats":
+		return stats(args[1:], stdout, stderr)
+	}
+	fmt.Fprintf(stderr, "uriuniq: unknown command %q\n", args[0])
+	return 2
+}
+
+func gen(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	length := fs.Int("length", uriuniq.DefaultLength, "length of the random part")
+	count := fs.Int("count", 1, "number of IDs to print")
+	charset := fs.String("charset", "alphanumeric", "charset preset")
+	exclude := fs.String("exclude", "", "comma-separated char classes to exclude")
+	prefix := fs.String("prefix", "", "prefix, followed by _")
+	sortable := fs.Bool("sortable", false, "start IDs with a timestamp")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 || *count < 0 {
+		fmt.Fprintln(stderr, "uriuniq gen: unexpected arguments")
+		return 2
+	}
+
+	opts := uriuniq.Options{Length: *length, Prefix: *prefix}
+	if *charset != "alphanumeric" {
+		var ok bool
+		if opts.CustomCharset, ok = uriuniq.LookupCharset(*charset); !ok {
+			fmt.Fprintf(stderr, "uriuniq gen: unknown charset %q\n", *charset)
+			return 2
+		}
+	}
+	if *exclude != "" {
+		for _, class := range strings.Split(*exclude, ",") {
+			switch class {
+			case "numeric":
+				opts.ExcludeNumeric = true
+			case "lowercase":
+				opts.ExcludeLowercase = true
+			case "uppercase":
+				opts.ExcludeUppercase = true
+			case "ambiguous":
+				opts.ExcludeAmbiguous = true
+			default:
+				fmt.Fprintf(stderr, "uriuniq gen: unknown exclusion %q\n", class)
+				return 2
+			}
+		}
+		if opts.CustomCharset != "" && (opts.ExcludeNumeric || opts.ExcludeLowercase || opts.ExcludeUppercase) {
+			fmt.Fprintln(stderr, "uriuniq gen: only ambiguous can be excluded from a -charset preset")
+			return 2
+		}
+	}
+
+	next := func() (string, error) { return uriuniq.GenerateSortable(opts) }
+	if !*sortable {
+		g, err := uriuniq.NewGenerator(opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "uriuniq gen: %s\n", err)
+			return 1
+		}
+		next = g.Next
+	}
+	w := bufio.NewWriter(stdout)
+	for i := 0; i < *count; i++ {
+		id, err := next()
+		if err != nil {
+			w.Flush()
+			fmt.Fprintf(stderr, "uriuniq gen: %s\n", err)
+			return 1
+		}
+		fmt.Fprintln(w, id)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(stderr, "uriuniq gen: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func lint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	config := fs.String("config", "", "policy file with Options as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *config == "" {
+		fmt.Fprintln(stderr, "uriuniq lint: --config is required")
+		return 2
+	}
+	opts, err := loadOptions(*config)
+	if err != nil {
+		fmt.Fprintf(stderr, "uriuniq lint: %s\n", err)
+		return 2
+	}
+
+	status := 0
+	for _, f := range uriuniq.Lint(opts) {
+		fmt.Fprintln(stdout, f)
+		if f.Severity == uriuniq.SeverityError {
+			status = 1
+		}
+	}
+	return status
+}
+
+// stats runs a chi-square test over IDs of a charset preset and reports
+// the overall and worst per-position p-values. It exits with 1 if the
+// output looks biased.
+func stats(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	length := fs.Int("length", uriuniq.DefaultLength, "length of the random part")
+	samples := fs.Int("samples", 100000, "number of IDs to generate")
+	charset := fs.String("charset", "alphanumeric", "charset preset")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 || *samples <= 0 {
+		fmt.Fprintln(stderr, "uriuniq stats: unexpected arguments")
+		return 2
+	}
+	opts := uriuniq.Options{Length: *length}
+	if *charset != "alphanumeric" {
+		var ok bool
+		if opts.CustomCharset, ok = uriuniq.LookupCharset(*charset); !ok {
+			fmt.Fprintf(stderr, "uriuniq stats: unknown charset %q\n", *charset)
+			return 2
+		}
+	}
+	g, err := uriuniq.NewGenerator(opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "uriuniq stats: %s\n", err)
+		return 1
+	}
+	r, err := uriuniq.ChiSquare(g, *samples)
+	if err != nil {
+		fmt.Fprintf(stderr, "uriuniq stats: %s\n", err)
+		return 1
+	}
+	worst := 0
+	for i, p := range r.PositionPValues {
+		if p < r.PositionPValues[worst] {
+			worst = i
+		}
+	}
+	fmt.Fprintf(stdout, "samples: %d\n", r.Samples)
+	fmt.Fprintf(stdout, "chi-square: %.2f (%d degrees of freedom), p-value %.4f\n", r.ChiSquare, len(r.Charset)-1, r.PValue)
+	fmt.Fprintf(stdout, "worst position: %d, p-value %.4f\n", worst, r.PositionPValues[worst])
+	if r.Biased(0.001) {
+		fmt.Fprintln(stdout, "biased")
+		return 1
+	}
+	return 0
+}
+
+// loadOptions reads Options from a JSON file. Unknown fields are an
+// error, so a misspelled field cannot silently weaken a policy.
+func loadOptions(path string) (uriuniq.Options, error) {
+	var opts uriuniq.Options
+	f, err := os.Open(path)
+	if err != nil {
+		return opts, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&opts); err != nil {
+		return opts, fmt.Errorf("%s: %w", path, err)
+	}
+	return opts, nil
+}
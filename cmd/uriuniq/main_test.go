@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestGen checks the IDs printed by the gen command.
+func TestGen(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		charset uriuniq.Charset
+		length  int
+	}{
+		{"Default", nil, uriuniq.Alphanumeric, uriuniq.DefaultLength},
+		{"Hex", []string{"-charset", "hex", "-length", "8"}, uriuniq.Hex, 8},
+		{"Exclusions", []string{"-exclude", "uppercase,numeric"}, uriuniq.Lowercase, uriuniq.DefaultLength},
+		{"Sortable", []string{"-sortable", "-charset", "base32", "-length", "20"}, uriuniq.Base32Crockford, 20},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"gen", "-count", "3", "-prefix", "ord"}, tc.args...)
+			if status := run(args, &stdout, &stderr); status != 0 {
+				t.Fatalf("Expected status 0, got %d (%s)", status, stderr.String())
+			}
+			lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+			if len(lines) != 3 {
+				t.Fatalf("Expected 3 lines, got %q", stdout.String())
+			}
+			for _, id := range lines {
+				rest := strings.TrimPrefix(id, "ord_")
+				if len(rest) != tc.length || strings.Trim(rest, string(tc.charset)) != "" {
+					t.Errorf("Unexpected ID %q", id)
+				}
+			}
+		})
+	}
+	for _, args := range [][]string{{"-charset", "bogus"}, {"-exclude", "bogus"}, {"-charset", "hex", "-exclude", "numeric"}, {"extra"}} {
+		if status := run(append([]string{"gen"}, args...), &bytes.Buffer{}, &bytes.Buffer{}); status != 2 {
+			t.Errorf("Expected status 2 for %q, got %d", args, status)
+		}
+	}
+}
+
+// TestLint checks the output and exit status of the lint command.
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		policy string
+		status int
+		output string
+	}{
+		{"Clean", `{"Length": 16, "ExcludeUppercase": true, "ExcludeNumeric": true}`, 0, ""},
+		{"Warning", `{"Length": 8, "ExcludeUppercase": true, "ExcludeNumeric": true}`, 0, "warning: weak-entropy"},
+		{"Error", `{"Length": 50, "CustomCharset": "abca"}`, 1, "error: duplicate-chars"},
+		{"Unknown Field", `{"Lenght": 16}`, 2, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "policy.json")
+			if err := os.WriteFile(path, []byte(tc.policy), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			var stdout, stderr bytes.Buffer
+			status := run([]string{"lint", "--config", path}, &stdout, &stderr)
+			if status != tc.status {
+				t.Errorf("Expected status %d, got %d (%s)", tc.status, status, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tc.output) || tc.output == "" && stdout.Len() > 0 {
+				t.Errorf("Expected output %q, got %q", tc.output, stdout.String())
+			}
+		})
+	}
+	if status := run([]string{"lint"}, &bytes.Buffer{}, &bytes.Buffer{}); status != 2 {
+		t.Errorf("Expected status 2 without --config, got %d", status)
+	}
+	if status := run([]string{"bogus"}, &bytes.Buffer{}, &bytes.Buffer{}); status != 2 {
+		t.Errorf("Expected status 2 for unknown command, got %d", status)
+	}
+}
+
+// TestStats checks the report of the stats command.
+func TestStats(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"stats", "-charset", "hex", "-length", "4", "-samples", "5000"}, &stdout, &stderr); status != 0 {
+		t.Fatalf("Expected status 0, got %d (%s%s)", status, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "(15 degrees of freedom)") {
+		t.Errorf("Unexpected report %q", stdout.String())
+	}
+	if status := run([]string{"stats", "-samples", "0"}, &bytes.Buffer{}, &bytes.Buffer{}); status != 2 {
+		t.Errorf("Expected status 2 for no samples, got %d", status)
+	}
+}
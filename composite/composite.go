@@ -0,0 +1,262 @@
+// Package composite defines multi-field IDs such as "ord_0kx3n2q1_07_h8aK3f",
+// made of a type tag, a timestamp, a shard and a random part. A Schema
+// generates and parses them symmetrically and validates every field.
+//
+// Example:
+//
+//	schema, err := composite.NewSchema(
+//	    composite.Field{Name: "type", Kind: composite.Tag, Value: "ord"},
+//	    composite.Field{Name: "ts", Kind: composite.Timestamp, Width: 9},
+//	    composite.Field{Name: "rand", Kind: composite.Random, Width: 12},
+//	)
+//	id, err := schema.Generate(composite.Values{Time: time.Now()})
+//	parsed, err := schema.Parse(id)
+package composite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// Kind is the kind of a Field.
+type Kind int
+
+const (
+	Tag       Kind = iota // Fixed value, such as an entity type
+	Timestamp             // Milliseconds since the Unix epoch
+	Shard                 // Unsigned shard or node number
+	Random                // Random chars
+)
+
+// DefaultCharset is used by fields without a Charset: lowercase base36 for
+// Timestamp and Shard, and Alphanumeric for Tag and Random.
+const DefaultCharset = uriuniq.Numeric + uriuniq.Lowercase
+
+// Field describes one field of a composite ID.
+type Field struct {
+	Name    string
+	Kind    Kind
+	Value   string          // Value of a Tag field
+	Width   int             // Width in chars, unused for Tag
+	Charset uriuniq.Charset // Chars of the field, see DefaultCharset
+}
+
+// Schema generates and parses composite IDs. Fields are joined with the
+// uriuniq.SafeDelimiter of their combined charsets.
+type Schema struct {
+	fields  []Field
+	charset uriuniq.Charset
+	delim   byte
+}
+
+// Values holds the inputs for the Timestamp and Shard fields.
+type Values struct {
+	Time  time.Time
+	Shard uint64
+}
+
+// Parsed is a composite ID decoded by Schema.Parse.
+type Parsed struct {
+	Fields map[string]string // Raw value of each field by name
+	Time   time.Time
+	Shard  uint64
+}
+
+// FieldError reports the field of a composite ID that failed to parse.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("composite: field %s: %s", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code of the underlying error, or
+// uriuniq.CodeInvalidField if it has none.
+func (e *FieldError) ErrorCode() uriuniq.Code {
+	if code := uriuniq.CodeOf(e.Err); code != uriuniq.CodeUnknown {
+		return code
+	}
+	return uriuniq.CodeInvalidField
+}
+
+// NewSchema creates a Schema from fields, checking that they are consistent.
+func NewSchema(fields ...Field) (*Schema, error) {
+	if len(fields) == 0 {
+		return nil, &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: errors.New("composite: no fields")}
+	}
+	s := &Schema{fields: make([]Field, len(fields))}
+	names := make(map[string]bool)
+	for i, f := range fields {
+		if f.Name == "" || names[f.Name] {
+			return nil, &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: fmt.Errorf("composite: missing or duplicate field name %q", f.Name)}
+		}
+		names[f.Name] = true
+		if f.Charset == "" {
+			f.Charset = DefaultCharset
+			if f.Kind == Tag || f.Kind == Random {
+				f.Charset = uriuniq.Alphanumeric
+			}
+		}
+		switch f.Kind {
+		case Tag:
+			if f.Value == "" {
+				return nil, &FieldError{f.Name, errors.New("empty tag")}
+			}
+			if err := checkChars(f.Value, f.Charset); err != nil {
+				return nil, &FieldError{f.Name, err}
+			}
+		case Timestamp, Shard, Random:
+			if f.Width <= 0 {
+				return nil, &FieldError{f.Name, errors.New("width must be positive")}
+			}
+			if len(f.Charset) < 2 {
+				return nil, &FieldError{f.Name, errors.New("charset needs at least 2 chars")}
+			}
+		default:
+			return nil, &FieldError{f.Name, fmt.Errorf("unknown kind %d", f.Kind)}
+		}
+		s.fields[i] = f
+		s.charset += f.Charset
+	}
+
+	delim, err := uriuniq.SafeDelimiter(s.charset)
+	if err != nil {
+		return nil, err
+	}
+	s.delim = delim
+	return s, nil
+}
+
+// Delimiter returns the byte separating fields.
+func (s *Schema) Delimiter() byte {
+	return s.delim
+}
+
+// Generate creates a composite ID from v.
+func (s *Schema) Generate(v Values) (string, error) {
+	parts := make([]string, len(s.fields))
+	for i, f := range s.fields {
+		var part string
+		var err error
+		switch f.Kind {
+		case Tag:
+			part = f.Value
+		case Timestamp:
+			ms := v.Time.UnixMilli()
+			if ms < 0 {
+				return "", &FieldError{f.Name, errors.New("time before Unix epoch")}
+			}
+			part, err = encodeUint(uint64(ms), f.Charset, f.Width)
+		case Shard:
+			part, err = encodeUint(v.Shard, f.Charset, f.Width)
+		case Random:
+			opts := uriuniq.NewOpts()
+			opts.Length = f.Width
+			opts.CustomCharset = f.Charset
+			opts.EntropyBufferSize = -1
+			var g *uriuniq.Generator
+			if g, err = uriuniq.NewGenerator(opts); err == nil {
+				part, err = g.Next()
+			}
+		}
+		if err != nil {
+			return "", &FieldError{f.Name, err}
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, string(s.delim)), nil
+}
+
+// Parse decodes and validates a composite ID.
+func (s *Schema) Parse(id string) (Parsed, error) {
+	parts := strings.Split(id, string(s.delim))
+	if len(parts) != len(s.fields) {
+		return Parsed{}, &uriuniq.Error{Code: uriuniq.CodeInvalidField, Err: fmt.Errorf("composite: got %d fields, want %d", len(parts), len(s.fields))}
+	}
+
+	parsed := Parsed{Fields: make(map[string]string, len(parts))}
+	for i, f := range s.fields {
+		part := parts[i]
+		if f.Kind == Tag {
+			if part != f.Value {
+				return Parsed{}, &FieldError{f.Name, fmt.Errorf("got tag %q, want %q", part, f.Value)}
+			}
+		} else if len(part) != f.Width {
+			return Parsed{}, &FieldError{f.Name, fmt.Errorf("got width %d, want %d", len(part), f.Width)}
+		}
+
+		switch f.Kind {
+		case Timestamp:
+			ms, err := decodeUint(part, f.Charset)
+			if err != nil {
+				return Parsed{}, &FieldError{f.Name, err}
+			}
+			parsed.Time = time.UnixMilli(int64(ms))
+		case Shard:
+			shard, err := decodeUint(part, f.Charset)
+			if err != nil {
+				return Parsed{}, &FieldError{f.Name, err}
+			}
+			parsed.Shard = shard
+		case Random:
+			if err := checkChars(part, f.Charset); err != nil {
+				return Parsed{}, &FieldError{f.Name, err}
+			}
+		}
+		parsed.Fields[f.Name] = part
+	}
+	return parsed, nil
+}
+
+// encodeUint writes v in the given charset as a big-endian, zero-padded
+// number of exactly width chars.
+func encodeUint(v uint64, charset uriuniq.Charset, width int) (string, error) {
+	base := uint64(len(charset))
+	out := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		out[i] = charset[v%base]
+		v /= base
+	}
+	if v != 0 {
+		return "", fmt.Errorf("value does not fit in %d chars", width)
+	}
+	return string(out), nil
+}
+
+// decodeUint is the inverse of encodeUint.
+func decodeUint(s string, charset uriuniq.Charset) (uint64, error) {
+	base := uint64(len(charset))
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(string(charset), s[i])
+		if digit < 0 {
+			return 0, &uriuniq.ParseError{Index: i, Char: rune(s[i]), Charset: charset}
+		}
+		if v > (math.MaxUint64-uint64(digit))/base {
+			return 0, errors.New("value overflows uint64")
+		}
+		v = v*base + uint64(digit)
+	}
+	return v, nil
+}
+
+// checkChars returns a *uriuniq.ParseError for the first byte of s not in charset.
+func checkChars(s string, charset uriuniq.Charset) error {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(string(charset), s[i]) < 0 {
+			return &uriuniq.ParseError{Index: i, Char: rune(s[i]), Charset: charset}
+		}
+	}
+	return nil
+}
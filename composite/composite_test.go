@@ -0,0 +1,109 @@
+package composite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+func testSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := NewSchema(
+		Field{Name: "type", Kind: Tag, Value: "ord"},
+		Field{Name: "ts", Kind: Timestamp, Width: 9},
+		Field{Name: "shard", Kind: Shard, Width: 2},
+		Field{Name: "rand", Kind: Random, Width: 12},
+	)
+	if err != nil {
+		t.Fatalf("NewSchema failed: %s", err)
+	}
+	return schema
+}
+
+// TestRoundTrip verifies that Parse decodes what Generate produced.
+func TestRoundTrip(t *testing.T) {
+	schema := testSchema(t)
+	now := time.UnixMilli(1700000000123)
+	id, err := schema.Generate(Values{Time: now, Shard: 7})
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if len(id) != 3+9+2+12+3 || id[:4] != "ord-" {
+		t.Fatalf("Unexpected id %q", id)
+	}
+
+	parsed, err := schema.Parse(id)
+	if err != nil {
+		t.Fatalf("Parse failed: %s", err)
+	}
+	if !parsed.Time.Equal(now) || parsed.Shard != 7 || parsed.Fields["type"] != "ord" || len(parsed.Fields["rand"]) != 12 {
+		t.Errorf("Unexpected parse result %+v", parsed)
+	}
+}
+
+// TestParseErrors checks that each field is validated.
+func TestParseErrors(t *testing.T) {
+	schema := testSchema(t)
+	tests := []struct {
+		name  string
+		id    string
+		field string
+	}{
+		{"Wrong Tag", "cus-0000000ab-07-abcdefghijkl", "type"},
+		{"Short Timestamp", "ord-000000ab-07-abcdefghijkl", "ts"},
+		{"Bad Shard", "ord-0000000ab-0!-abcdefghijkl", "shard"},
+		{"Bad Random", "ord-0000000ab-07-abcdefghij_l", "rand"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := schema.Parse(tc.id)
+			var ferr *FieldError
+			if !errors.As(err, &ferr) || ferr.Field != tc.field {
+				t.Errorf("%s: expected error on %s, got %v", tc.name, tc.field, err)
+			}
+		})
+	}
+
+	if _, err := schema.Parse("ord-0000000ab-07"); err == nil {
+		t.Errorf("Expected error for missing field")
+	}
+	var perr *uriuniq.ParseError
+	if _, err := schema.Parse("ord-0000000ab-07-abcdefghij_l"); !errors.As(err, &perr) || perr.Index != 10 {
+		t.Errorf("Expected ParseError at index 10, got %v", err)
+	}
+	for id, want := range map[string]uriuniq.Code{
+		"cus-0000000ab-07-abcdefghijkl": uriuniq.CodeInvalidField,
+		"ord-0000000ab-07-abcdefghij_l": uriuniq.CodeInvalidChar,
+	} {
+		if _, err := schema.Parse(id); uriuniq.CodeOf(err) != want {
+			t.Errorf("%q: expected code %s, got %s", id, want, uriuniq.CodeOf(err))
+		}
+	}
+}
+
+// TestSchemaErrors checks schema validation and value overflow.
+func TestSchemaErrors(t *testing.T) {
+	if _, err := NewSchema(); err == nil {
+		t.Errorf("Expected error for empty schema")
+	}
+	if _, err := NewSchema(Field{Name: "a", Kind: Tag, Value: "x"}, Field{Name: "a", Kind: Random, Width: 4}); err == nil {
+		t.Errorf("Expected error for duplicate name")
+	}
+	if _, err := NewSchema(Field{Name: "r", Kind: Random}); err == nil {
+		t.Errorf("Expected error for missing width")
+	}
+	if _, err := NewSchema(Field{Name: "t", Kind: Tag, Value: "a_b"}); err == nil {
+		t.Errorf("Expected error for tag outside charset")
+	}
+
+	schema, err := NewSchema(Field{Name: "shard", Kind: Shard, Width: 1})
+	if err != nil {
+		t.Fatalf("NewSchema failed: %s", err)
+	}
+	if _, err := schema.Generate(Values{Shard: 36}); err == nil {
+		t.Errorf("Expected error for shard overflow")
+	}
+}
@@ -0,0 +1,243 @@
+package uriuniq
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// classMin is a char class with the minimum count Options require of it.
+type classMin struct {
+	name  string
+	min   int
+	chars Charset
+}
+
+// charClasses returns the classes of opts with their minimum counts.
+func charClasses(opts Options) []classMin {
+	return []classMin{
+		{"digits", opts.MinDigits, Numeric},
+		{"lowercase", opts.MinLowercase, Lowercase},
+		{"uppercase", opts.MinUppercase, Uppercase},
+	}
+}
+
+// composed reports whether opts require a composition.
+func composed(opts Options) bool {
+	return opts.MinDigits != 0 || opts.MinLowercase != 0 || opts.MinUppercase != 0
+}
+
+// classMembers returns the chars of charset in class.
+func classMembers(charset []byte, class Charset) []byte {
+	var members []byte
+	for _, c := range charset {
+		if inCharset(c, class) {
+			members = append(members, c)
+		}
+	}
+	return members
+}
+
+// inCharset reports whether c is one of the chars of charset.
+func inCharset(c byte, charset Charset) bool {
+	for i := 0; i < len(charset); i++ {
+		if charset[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// checkComposition checks that the charset has chars of every class opts
+// require and the shortest string has room for all of them.
+func checkComposition(opts Options, charset []byte) error {
+	need := 0
+	for _, class := range charClasses(opts) {
+		if class.min < 0 {
+			return errorf(CodeInvalidArgument, "uriuniq: negative minimum of %s", class.name)
+		}
+		if class.min > 0 && len(classMembers(charset, class.chars)) == 0 {
+			return errorf(CodeInvalidArgument, "uriuniq: charset has no %s", class.name)
+		}
+		need += class.min
+	}
+	if min, _ := lengthRange(opts); need > min-fixedChars(opts)-leadingChars(opts)-trailingChars(opts) {
+		return errorf(CodeInvalidLength, "uriuniq: %d required chars exceed length %d", need, min)
+	}
+	return nil
+}
+
+// compose overwrites the first chars of the random part of output with
+// uniform draws from each required class, then shuffles the random part
+// with Fisher-Yates, so the required chars land at uniform positions. The
+// result is not uniform over the strings meeting the minimums, which
+// rejection sampling would need far too many draws for under strict
+// policies; see Options.MinDigits. The first and last chars drawn by
+// drawEdges stay in place.
+func compose(opts Options, charset, output []byte) error {
+	output = output[fixedChars(opts)+leadingChars(opts) : len(output)-trailingChars(opts)]
+	pos := 0
+	for _, class := range charClasses(opts) {
+		if class.min == 0 {
+			continue
+		}
+		if err := drawFrom(opts, classMembers(charset, class.chars), output[pos:pos+class.min]); err != nil {
+			return err
+		}
+		pos += class.min
+	}
+	return shuffle(opts, output)
+}
+
+// shuffle permutes b uniformly with entropy from opts.EntropySource.
+func shuffle(opts Options, b []byte) error {
+	src := newEntropyReader(opts)
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := randIndex(src, i+1)
+		if err != nil {
+			return err
+		}
+		b[i], b[j] = b[j], b[i]
+	}
+	return nil
+}
+
+// randIndex returns a uniform int in [0, n), rejecting the top values of
+// a 32-bit read that would bias it.
+func randIndex(src entropyReader, n int) (int, error) {
+	var buf [4]byte
+	limit := uint32(1<<32 - (1<<32)%uint64(n))
+	for {
+		if err := src.ReadFull(buf[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.BigEndian.Uint32(buf[:]); limit == 0 || v < limit {
+			return int(v % uint32(n)), nil
+		}
+	}
+}
+
+// checkCounts checks that the random part of id has the chars opts
+// require.
+func checkCounts(opts Options, id string) error {
+	for _, class := range charClasses(opts) {
+		if class.min == 0 {
+			continue
+		}
+		n := 0
+		for i := 0; i < len(id); i++ {
+			if inCharset(id[i], class.chars) {
+				n++
+			}
+		}
+		if n < class.min {
+			return errorf(CodeInvalidArgument, "uriuniq: %d %s, want at least %d", n, class.name, class.min)
+		}
+	}
+	return nil
+}
+
+// leadingChars returns 1 if opts draw the first char apart, for
+// Options.NoLeadingDigit, or 0.
+func leadingChars(opts Options) int {
+	if opts.NoLeadingDigit {
+		return 1
+	}
+	return 0
+}
+
+// trailingChars returns 1 if opts draw the last char apart, for
+// Options.DNSLabel, or 0.
+func trailingChars(opts Options) int {
+	if opts.DNSLabel {
+		return 1
+	}
+	return 0
+}
+
+// leadingSet returns the chars of charset a string may start with under
+// Options.NoLeadingDigit: the non-digits, without the hyphen for
+// Options.DNSLabel.
+func leadingSet(opts Options, charset []byte) []byte {
+	var chars []byte
+	for _, c := range charset {
+		if (c < '0' || c > '9') && !(opts.DNSLabel && c == '-') {
+			chars = append(chars, c)
+		}
+	}
+	return chars
+}
+
+// trailingSet returns the chars of charset a string may end with under
+// Options.DNSLabel: all but the hyphen.
+func trailingSet(charset []byte) []byte {
+	var chars []byte
+	for _, c := range charset {
+		if c != '-' {
+			chars = append(chars, c)
+		}
+	}
+	return chars
+}
+
+// checkLeading checks that opts with NoLeadingDigit have a non-digit to
+// start with and no fingerprint or node in the way.
+func checkLeading(opts Options, charset []byte) error {
+	if !opts.NoLeadingDigit {
+		return nil
+	}
+	if len(leadingSet(opts, charset)) == 0 {
+		return newError(CodeNoValidChars, "uriuniq: NoLeadingDigit needs a charset with non-digits")
+	}
+	if fixedChars(opts) > 0 {
+		return newError(CodeInvalidArgument, "uriuniq: NoLeadingDigit cannot be combined with InstanceLabel or NodeChars")
+	}
+	return nil
+}
+
+// drawEdges replaces the first char of output with a uniform draw from
+// the leadingSet of charset under NoLeadingDigit, and the last one from
+// the trailingSet under DNSLabel. The other chars keep their draws from
+// the whole charset, so they are not biased.
+func drawEdges(opts Options, charset, output []byte) error {
+	if len(output) == 0 {
+		return nil
+	}
+	if opts.NoLeadingDigit {
+		if err := drawFrom(opts, leadingSet(opts, charset), output[:1]); err != nil {
+			return err
+		}
+	}
+	if opts.DNSLabel && len(output) > 1 {
+		return drawFrom(opts, trailingSet(charset), output[len(output)-1:])
+	}
+	return nil
+}
+
+// drawFrom fills dst with uniform draws from chars.
+func drawFrom(opts Options, chars, dst []byte) error {
+	if len(chars) == 1 {
+		for i := range dst {
+			dst[i] = chars[0]
+		}
+		return nil
+	}
+	buffer := getBuffer(readSize(len(dst), len(chars)))
+	defer putBuffer(buffer)
+	_, err := randFill(opts, chars, dst, *buffer)
+	return err
+}
+
+// edgeBitsLoss returns the entropy the first and last chars lack under
+// Options.NoLeadingDigit and Options.DNSLabel, compared with chars of the
+// whole charset.
+func edgeBitsLoss(opts Options, charset []byte) float64 {
+	loss := 0.0
+	perChar := BitsPerChar(Charset(charset))
+	if opts.NoLeadingDigit {
+		loss += perChar - math.Log2(float64(len(leadingSet(opts, charset))))
+	}
+	if opts.DNSLabel {
+		loss += perChar - math.Log2(float64(len(trailingSet(charset))))
+	}
+	return loss
+}
@@ -0,0 +1,129 @@
+package uriuniq
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"sort"
+	"testing"
+)
+
+// TestComposition checks that every string has the required chars, at
+// uniform positions, and that impossible policies are rejected.
+func TestComposition(t *testing.T) {
+	opts := Options{Length: 8, MinDigits: 2, MinLowercase: 1, MinUppercase: 3, CheckChar: true, InstanceLabel: "pod-1"}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	var digitAt [8]int
+	for i := 0; i < 2000; i++ {
+		id, err := g.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+		if err := Validate(id, opts); err != nil {
+			t.Fatalf("Validate(%q) failed: %s", id, err)
+		}
+		for j := 0; j < 8; j++ {
+			if inCharset(id[j], Numeric) {
+				digitAt[j]++
+			}
+		}
+	}
+	// Position 0 holds the fingerprint; the others should each get a
+	// fair share of the required digits.
+	for j := 1; j < 8; j++ {
+		if digitAt[j] < 2000/4 {
+			t.Errorf("Digits at position %d: %d, counts %v", j, digitAt[j], digitAt)
+		}
+	}
+
+	if s, err := Generate(Options{Length: 4, MinDigits: 4}); err != nil || checkCharset(s, Numeric) != nil {
+		t.Errorf("Expected 4 digits, got %q, %v", s, err)
+	}
+	if err := Validate("abcdefgh", Options{Length: 8, MinDigits: 1}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		code Code
+	}{
+		{"Too Many", Options{Length: 4, MinDigits: 3, MinUppercase: 2}, CodeInvalidLength},
+		{"Missing Class", Options{Length: 8, MinDigits: 1, ExcludeNumeric: true}, CodeInvalidArgument},
+		{"Negative", Options{Length: 8, MinLowercase: -1}, CodeInvalidArgument},
+		{"Length Range", Options{MinLength: 2, MaxLength: 10, MinDigits: 3}, CodeInvalidLength},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.opts); CodeOf(err) != tc.code {
+				t.Errorf("Expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+// TestShuffle checks that shuffle only permutes and passes on source
+// errors.
+func TestShuffle(t *testing.T) {
+	b := []byte("abcdefghij")
+	if err := shuffle(Options{}, b); err != nil {
+		t.Fatalf("shuffle failed: %s", err)
+	}
+	sorted := append([]byte(nil), b...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if !bytes.Equal(sorted, []byte("abcdefghij")) {
+		t.Errorf("Expected a permutation, got %q", b)
+	}
+	if err := shuffle(Options{EntropySource: bytes.NewReader(nil)}, b); err == nil {
+		t.Errorf("Expected error for an empty source")
+	}
+}
+
+// TestNoLeadingDigit checks that no string starts with a digit, while the
+// other positions still get digits at the usual rate.
+func TestNoLeadingDigit(t *testing.T) {
+	opts := Options{Length: 6, NoLeadingDigit: true, MinDigits: 1}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	digits := 0
+	for i := 0; i < 2000; i++ {
+		id, err := g.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+		if inCharset(id[0], Numeric) {
+			t.Fatalf("ID %q starts with a digit", id)
+		}
+		if err := Validate(id, opts); err != nil {
+			t.Fatalf("Validate(%q) failed: %s", id, err)
+		}
+		if inCharset(id[5], Numeric) {
+			digits++
+		}
+	}
+	// The last char is a required digit with a chance of 1/5, else a digit
+	// with a chance of 10/62.
+	if want := 2000 * (0.2 + 0.8*10.0/62); math.Abs(float64(digits)-want) > 100 {
+		t.Errorf("Expected about %.0f digits at the end, got %d", want, digits)
+	}
+
+	var perr *ParseError
+	if err := Validate("1abcde", Options{Length: 6, NoLeadingDigit: true}); !errors.As(err, &perr) || perr.Index != 0 {
+		t.Errorf("Expected ParseError at 0, got %v", err)
+	}
+	want := 5*AlphanumericBits + math.Log2(52)
+	if got := Entropy(Options{Length: 6, NoLeadingDigit: true}); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected %f bits, got %f", want, got)
+	}
+	if _, err := NewGenerator(Options{Length: 6, NoLeadingDigit: true, CustomCharset: Numeric}); CodeOf(err) != CodeNoValidChars {
+		t.Errorf("Expected CodeNoValidChars, got %v", err)
+	}
+	if _, err := NewGenerator(Options{Length: 6, NoLeadingDigit: true, NodeChars: 1}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument with a node, got %v", err)
+	}
+}
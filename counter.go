@@ -0,0 +1,70 @@
+package uriuniq
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// totalIssued counts the strings issued by Generate and all Generators.
+var totalIssued atomic.Uint64
+
+// TotalIssued returns the number of strings issued by Generate and all
+// Generators since the process started.
+func TotalIssued() uint64 {
+	return totalIssued.Load()
+}
+
+// CounterStore persists issuance counters across restarts, as in a file or
+// a database row per name.
+type CounterStore interface {
+	LoadCounter(name string) (uint64, error)
+	SaveCounter(name string, n uint64) error
+}
+
+// Issued returns the number of strings g issued, including any count
+// restored with RestoreCounter.
+func (g *Generator) Issued() uint64 {
+	return g.issued.Load()
+}
+
+// SaveCounter saves the issued count of g under name.
+func (g *Generator) SaveCounter(store CounterStore, name string) error {
+	return store.SaveCounter(name, g.issued.Load())
+}
+
+// RestoreCounter adds the count saved under name to the issued count of g.
+// Call it before the first Next.
+func (g *Generator) RestoreCounter(store CounterStore, name string) error {
+	n, err := store.LoadCounter(name)
+	if err != nil {
+		return err
+	}
+	g.issued.Add(n)
+	return nil
+}
+
+// count records an issued string and fires the keyspace alert once.
+func (g *Generator) count() {
+	totalIssued.Add(1)
+	countGenerated(g.opts)
+	n := g.issued.Add(1)
+	if g.alertAt != 0 && n >= g.alertAt && g.alerted.CompareAndSwap(false, true) {
+		g.opts.OnKeyspaceAlert(n, math.Exp2(Entropy(g.opts)))
+	}
+}
+
+// alertThreshold returns the issued count firing the keyspace alert of
+// prepared opts, or 0 if it never fires.
+func alertThreshold(opts Options) uint64 {
+	if opts.OnKeyspaceAlert == nil || opts.KeyspaceAlertFraction <= 0 {
+		return 0
+	}
+	at := math.Ceil(opts.KeyspaceAlertFraction * math.Exp2(Entropy(opts)))
+	if at >= math.MaxUint64 {
+		return 0
+	}
+	if at < 1 {
+		return 1
+	}
+	return uint64(at)
+}
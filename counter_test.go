@@ -0,0 +1,73 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+)
+
+// mapCounterStore is a CounterStore in a map.
+type mapCounterStore map[string]uint64
+
+func (s mapCounterStore) LoadCounter(name string) (uint64, error) {
+	n, ok := s[name]
+	if !ok {
+		return 0, errors.New("not found")
+	}
+	return n, nil
+}
+
+func (s mapCounterStore) SaveCounter(name string, n uint64) error {
+	s[name] = n
+	return nil
+}
+
+// TestIssuedCounter checks counting, the keyspace alert and persistence.
+func TestIssuedCounter(t *testing.T) {
+	var alerts []uint64
+	opts := NewOpts()
+	opts.Length = 2
+	opts.CustomCharset = Numeric
+	opts.KeyspaceAlertFraction = 0.05 // 5 of 100 codes
+	opts.OnKeyspaceAlert = func(issued uint64, keyspace float64) {
+		if keyspace < 99.9 || keyspace > 100.1 {
+			t.Errorf("Unexpected keyspace %f", keyspace)
+		}
+		alerts = append(alerts, issued)
+	}
+
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	before := TotalIssued()
+	for i := 0; i < 7; i++ {
+		if _, err := g.Next(); err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+	}
+	if g.Issued() != 7 || TotalIssued()-before < 7 {
+		t.Errorf("Unexpected counts %d, %d", g.Issued(), TotalIssued()-before)
+	}
+	if len(alerts) != 1 || alerts[0] != 5 {
+		t.Errorf("Expected one alert at 5, got %v", alerts)
+	}
+
+	store := mapCounterStore{}
+	if err := g.SaveCounter(store, "codes"); err != nil {
+		t.Fatalf("SaveCounter failed: %s", err)
+	}
+	restored, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	if err := restored.RestoreCounter(store, "codes"); err != nil {
+		t.Fatalf("RestoreCounter failed: %s", err)
+	}
+	restored.Next()
+	if restored.Issued() != 8 || len(alerts) != 2 || alerts[1] != 8 {
+		t.Errorf("Unexpected restored count %d, alerts %v", restored.Issued(), alerts)
+	}
+	if err := restored.RestoreCounter(store, "missing"); err == nil {
+		t.Errorf("Expected store error")
+	}
+}
@@ -0,0 +1,86 @@
+package uriuniq
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrDeadlineExceeded is returned when GenerateWithin runs out of time. The
+// returned error is a *DeadlineError with diagnostics.
+var ErrDeadlineExceeded = newError(CodeDeadline, "uriuniq: deadline exceeded")
+
+// DeadlineError reports how far GenerateWithin got within its budget.
+type DeadlineError struct {
+	Budget  time.Duration
+	Elapsed time.Duration
+	Reads   int64 // Entropy reads completed
+}
+
+func (e *DeadlineError) Error() string {
+	return fmt.Sprintf("uriuniq: deadline exceeded after %s of %s, %d entropy reads", e.Elapsed, e.Budget, e.Reads)
+}
+
+// Is reports whether target is ErrDeadlineExceeded.
+func (e *DeadlineError) Is(target error) bool { return target == ErrDeadlineExceeded }
+
+// ErrorCode returns CodeDeadline.
+func (e *DeadlineError) ErrorCode() Code { return CodeDeadline }
+
+// GenerateWithin is like Generate but returns within d, covering every
+// entropy read and rejection retry. On timeout it returns a *DeadlineError.
+// An entropy read still blocked at that point completes in the background
+// and its result is dropped.
+func GenerateWithin(d time.Duration, opts Options) (string, error) {
+	start := time.Now()
+	src := opts.EntropySource
+	if src == nil {
+		src = rand.Reader
+	}
+	dr := &deadlineReader{r: src, deadline: start.Add(d)}
+	opts.EntropySource = dr
+
+	type result struct {
+		id  string
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		id, err := generateID(opts)
+		done <- result{id, err}
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		if errors.Is(r.err, errPastDeadline) {
+			break
+		}
+		return r.id, r.err
+	case <-timer.C:
+	}
+	return "", &DeadlineError{Budget: d, Elapsed: time.Since(start), Reads: dr.reads.Load()}
+}
+
+// errPastDeadline is returned by a deadlineReader past its deadline.
+var errPastDeadline = errors.New("uriuniq: past deadline")
+
+// deadlineReader fails every read started after deadline.
+type deadlineReader struct {
+	r        io.Reader
+	deadline time.Time
+	reads    atomic.Int64
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	if !time.Now().Before(d.deadline) {
+		return 0, errPastDeadline
+	}
+	n, err := d.r.Read(p)
+	d.reads.Add(1)
+	return n, err
+}
@@ -0,0 +1,48 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowReader waits before every read from crypto/rand.
+type slowReader time.Duration
+
+func (r slowReader) Read(p []byte) (int, error) {
+	time.Sleep(time.Duration(r))
+	return (&switchReader{}).Read(p)
+}
+
+// TestGenerateWithin checks the budget and diagnostics.
+func TestGenerateWithin(t *testing.T) {
+	id, err := GenerateWithin(time.Second, NewOpts())
+	if err != nil || len(id) != DefaultLength {
+		t.Fatalf("GenerateWithin = %q, %v", id, err)
+	}
+
+	opts := NewOpts()
+	opts.EntropySource = slowReader(200 * time.Millisecond)
+	start := time.Now()
+	_, err = GenerateWithin(20*time.Millisecond, opts)
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Returned after %s, budget was 20ms", elapsed)
+	}
+	var derr *DeadlineError
+	if !errors.Is(err, ErrDeadlineExceeded) || !errors.As(err, &derr) || derr.Reads != 0 {
+		t.Fatalf("Expected DeadlineError before any read, got %v", err)
+	}
+	if CodeOf(err) != CodeDeadline {
+		t.Errorf("Expected %s, got %s", CodeDeadline, CodeOf(err))
+	}
+
+	// Rejected reads are retried only within the budget.
+	opts.EntropySource = slowReader(5 * time.Millisecond)
+	opts.CustomCharset = "ab"
+	opts.Length = 100000
+	opts.MaxBadReads = 1000
+	_, err = GenerateWithin(30*time.Millisecond, opts)
+	if !errors.As(err, &derr) || derr.Reads == 0 {
+		t.Errorf("Expected DeadlineError after some reads, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package dedupid creates message deduplication IDs for queues such as
+// Amazon SQS FIFO queues and SNS FIFO topics, which accept at most
+// MaxLength alphanumeric or punctuation chars.
+//
+// Example:
+//
+//	id := dedupid.For(body, 5*time.Minute, key)
+//	input.MessageDeduplicationId = &id
+package dedupid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// MaxLength is the longest deduplication ID SQS and SNS accept.
+const MaxLength = 128
+
+// ErrInvalid is returned by Validate.
+var ErrInvalid = &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: errors.New("dedupid: invalid deduplication ID")}
+
+// For returns a deterministic ID for payload, the same for every call
+// under key within one window, so a producer that resends a message after
+// a timeout gets it deduplicated. Windows are aligned to the Unix epoch:
+// two sends straddling a window boundary get different IDs. window <= 0
+// makes the ID depend on payload and key only.
+func For(payload []byte, window time.Duration, key []byte) string {
+	return forTime(payload, window, key, time.Now())
+}
+
+func forTime(payload []byte, window time.Duration, key []byte, now time.Time) string {
+	var bucket [8]byte
+	if window > 0 {
+		binary.BigEndian.PutUint64(bucket[:], uint64(now.UnixNano()/int64(window)))
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(bucket[:])
+	h.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// New returns a random ID of 32 alphanumeric chars, for messages that
+// should never be deduplicated against each other.
+func New() (string, error) {
+	return ids.Next()
+}
+
+// ids generates the IDs of New.
+var ids, _ = uriuniq.NewGenerator(uriuniq.Options{Length: 32})
+
+// Validate checks that id has 1 to MaxLength alphanumeric or ASCII
+// punctuation chars.
+func Validate(id string) error {
+	if id == "" || len(id) > MaxLength {
+		return ErrInvalid
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return ErrInvalid
+		}
+	}
+	return nil
+}
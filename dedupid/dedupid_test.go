@@ -0,0 +1,59 @@
+package dedupid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFor checks that IDs are stable within a window and valid.
+func TestFor(t *testing.T) {
+	key := []byte("key")
+	payload := []byte(`{"order":1}`)
+	start := time.Unix(1700000100, 0)
+	id := forTime(payload, 5*time.Minute, key, start)
+	if err := Validate(id); err != nil || len(id) != 43 {
+		t.Fatalf("Unexpected ID %q: %v", id, err)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		key     string
+		now     time.Time
+		same    bool
+	}{
+		{"Same Window", `{"order":1}`, "key", start.Add(time.Minute), true},
+		{"Next Window", `{"order":1}`, "key", start.Add(5 * time.Minute), false},
+		{"Other Payload", `{"order":2}`, "key", start, false},
+		{"Other Key", `{"order":1}`, "other", start, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := forTime([]byte(tc.payload), 5*time.Minute, []byte(tc.key), tc.now)
+			if (got == id) != tc.same {
+				t.Errorf("Expected same %v, got %q vs %q", tc.same, got, id)
+			}
+		})
+	}
+	if forTime(payload, 0, key, start) != forTime(payload, 0, key, start.Add(time.Hour)) {
+		t.Errorf("Expected a window of 0 to ignore time")
+	}
+}
+
+// TestNewAndValidate checks random IDs and validation.
+func TestNewAndValidate(t *testing.T) {
+	id, err := New()
+	if err != nil || Validate(id) != nil || len(id) != 32 {
+		t.Errorf("Unexpected ID %q: %v", id, err)
+	}
+	for _, bad := range []string{"", "a b", "é", strings.Repeat("a", MaxLength+1)} {
+		if err := Validate(bad); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Expected ErrInvalid for %q, got %v", bad, err)
+		}
+	}
+	if err := Validate(`!"#$%&'()*+,-./:;<=>?@[\]^_{|}~` + "`"); err != nil {
+		t.Errorf("Expected punctuation to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package uriuniq
+
+import (
+	"strings"
+)
+
+// ErrNoDelimiter is returned when every URI-safe delimiter is in the charset.
+var ErrNoDelimiter = newError(CodeNoDelimiter, "uriuniq: no safe delimiter")
+
+// delimiters lists the URI-safe delimiter candidates in order of preference.
+const delimiters = "-_.~!*'()"
+
+// SafeDelimiter returns the first URI-safe punctuation char out of
+// "-_.~!*'()" that does not appear in charset.
+func SafeDelimiter(charset Charset) (byte, error) {
+	for i := 0; i < len(delimiters); i++ {
+		if strings.IndexByte(string(charset), delimiters[i]) < 0 {
+			return delimiters[i], nil
+		}
+	}
+	return 0, ErrNoDelimiter
+}
+
+// Join joins IDs drawn from charset with its SafeDelimiter. Every part is
+// checked against charset, so the result always splits back unambiguously.
+func Join(charset Charset, parts ...string) (string, error) {
+	delim, err := SafeDelimiter(charset)
+	if err != nil {
+		return "", err
+	}
+	for _, part := range parts {
+		if err := checkCharset(part, charset); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(parts, string(delim)), nil
+}
+
+// Split is the inverse of Join.
+func Split(charset Charset, s string) ([]string, error) {
+	delim, err := SafeDelimiter(charset)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(s, string(delim))
+	offset := 0
+	for _, part := range parts {
+		if err := checkCharset(part, charset); err != nil {
+			err.(*ParseError).Index += offset
+			return nil, err
+		}
+		offset += len(part) + 1
+	}
+	return parts, nil
+}
@@ -0,0 +1,59 @@
+package uriuniq
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestSafeDelimiter checks delimiter selection for various charsets.
+func TestSafeDelimiter(t *testing.T) {
+	tests := []struct {
+		name     string
+		charset  Charset
+		expected byte
+		err      error
+	}{
+		{"Alphanumeric", Alphanumeric, '-', nil},
+		{"With Hyphen", Alphanumeric + "-", '_', nil},
+		{"With Hyphen Underscore", Alphanumeric + "-_", '.', nil},
+		{"All Taken", "-_.~!*'()", 0, ErrNoDelimiter},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SafeDelimiter(tc.charset)
+			if !errors.Is(err, tc.err) || got != tc.expected {
+				t.Errorf("%s: expected %q/%v, got %q/%v", tc.name, tc.expected, tc.err, got, err)
+			}
+		})
+	}
+}
+
+// TestJoinSplit verifies that Join and Split round-trip composite IDs.
+func TestJoinSplit(t *testing.T) {
+	charset := Alphanumeric + "-"
+	parts := []string{"ord", "a-b", "X9"}
+	joined, err := Join(charset, parts...)
+	if err != nil {
+		t.Fatalf("Join failed: %s", err)
+	}
+	if joined != "ord_a-b_X9" {
+		t.Errorf("Unexpected join %q", joined)
+	}
+	split, err := Split(charset, joined)
+	if err != nil {
+		t.Fatalf("Split failed: %s", err)
+	}
+	if !reflect.DeepEqual(split, parts) {
+		t.Errorf("Expected %v, got %v", parts, split)
+	}
+
+	if _, err := Join(Alphanumeric, "a_b"); err == nil {
+		t.Errorf("Expected error for part outside charset")
+	}
+	var perr *ParseError
+	if _, err := Split(Alphanumeric, "ab-c!d"); !errors.As(err, &perr) || perr.Index != 4 {
+		t.Errorf("Expected ParseError at index 4, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package uriuniq
+
+import "sync/atomic"
+
+// Deprecated paths reported to the deprecation hook.
+const (
+	// DeprecatedGenerate is a call to the one-shot Generate, which checks
+	// Options and builds the charset on every call. Use a Generator.
+	DeprecatedGenerate = "Generate"
+	// DeprecatedDefaultLength is a call relying on a Length of 0 or less
+	// falling back to DefaultLength. Set Length, or start from NewOpts.
+	DeprecatedDefaultLength = "DefaultLength fallback"
+)
+
+// deprecationHook is the hook set with SetDeprecationHook, or nil.
+var deprecationHook atomic.Pointer[func(api string)]
+
+// SetDeprecationHook sets f to be called with the name of the deprecated
+// path, such as DeprecatedGenerate, on every call into one, so large
+// codebases can count the calls left to migrate. Only calls made by the
+// caller are counted: the other APIs of this module and its subpackages
+// do not go through the deprecated paths, so the count reaches zero once
+// the caller has migrated. A nil f removes the hook. Without a hook,
+// deprecated paths cost one atomic load.
+func SetDeprecationHook(f func(api string)) {
+	if f == nil {
+		deprecationHook.Store(nil)
+		return
+	}
+	deprecationHook.Store(&f)
+}
+
+// deprecated reports a call into the deprecated path api.
+func deprecated(api string) {
+	if f := deprecationHook.Load(); f != nil {
+		(*f)(api)
+	}
+}
@@ -0,0 +1,38 @@
+package uriuniq
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestDeprecationHook checks that deprecated paths are reported.
+func TestDeprecationHook(t *testing.T) {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	SetDeprecationHook(func(api string) {
+		mu.Lock()
+		counts[api]++
+		mu.Unlock()
+	})
+	defer SetDeprecationHook(nil)
+
+	Generate(NewOpts())
+	Generate(Options{})
+	g, _ := NewGenerator(NewOpts())
+	g.Next()
+
+	// The other APIs must not count as calls of the shim.
+	var record struct {
+		ID string `uriuniq:"len=8"`
+	}
+	Fill(&record)
+	GenerateWith(WithLength(8))
+	GenerateToken(NewOpts())
+	FormatRevision{Tag: "dep", Options: NewOpts()}.Generate()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counts[DeprecatedGenerate] != 2 || counts[DeprecatedDefaultLength] != 1 || len(counts) != 2 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+}
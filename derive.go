@@ -0,0 +1,107 @@
+package uriuniq
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+)
+
+// DeriveGenerator creates a deterministic Generator from a master seed and a
+// label. The same seed, info and Options always yield the same sequence,
+// and different labels yield independent sequences. It is meant for
+// reproducible simulations and test fixtures.
+//
+// IDs from a derived Generator are only as secret as the master seed and must
+// never be used as tokens. Any EntropySource, EntropyBufferSize and
+// Sensitive setting in opts is ignored.
+//
+// The sequence is defined as follows, so it can be reproduced in other
+// languages (see TestVectors). The stream is the AES-256-CTR keystream
+// (zero IV) under the key HKDF-SHA256(masterSeed, info) with an empty salt.
+// Chars are taken from the stream one byte at a time: with n chars in the
+// charset, a byte b <= 255 - (256 % n) yields charset[b % n] and any other
+// byte is skipped. Each ID continues the stream where the previous ID
+// stopped. With SamplerBits and a charset of 2^b chars, each char instead
+// takes the next b bits of the stream, most significant first, and each
+// ID starts at a byte boundary. With SamplerArithmetic, each ID instead
+// reads the next ceil(bits/8) bytes, and more while its draw needs them,
+// as described there.
+func DeriveGenerator(masterSeed []byte, info string, opts Options) (*Generator, error) {
+	if len(masterSeed) == 0 {
+		return nil, newError(CodeInvalidArgument, "uriuniq: empty master seed")
+	}
+	opts.EntropySource = deriveStream(masterSeed, info)
+	opts.EntropyBufferSize = 0
+	opts.Sensitive = false
+	return NewGenerator(opts)
+}
+
+// NewSeededGenerator creates a deterministic Generator for golden-file
+// tests and shared test fixtures: the same seed and Options always yield
+// the same sequence, in every version of this module. It is
+// DeriveGenerator with an empty info label.
+//
+// NOT FOR PRODUCTION: anyone who knows the seed can predict every ID.
+func NewSeededGenerator(seed []byte, opts Options) (*Generator, error) {
+	return DeriveGenerator(seed, "", opts)
+}
+
+// DeriveID returns the ID of data under key: the same key, data and
+// Options always give the same ID, in the charset, length and Prefix of
+// the random IDs those Options create, so ingested records can be
+// deduplicated by ID. It is the first ID of
+// DeriveGenerator(HMAC-SHA256(key, data), "uriuniq/derive-id", opts). The
+// ID reveals nothing about data to those without key, but collides like a
+// random ID of the same entropy.
+func DeriveID(key, data []byte, opts Options) (string, error) {
+	if len(key) == 0 {
+		return "", newError(CodeInvalidArgument, "uriuniq: empty key")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	gen, err := DeriveGenerator(mac.Sum(nil), "uriuniq/derive-id", opts)
+	if err != nil {
+		return "", err
+	}
+	return gen.Next()
+}
+
+// deriveStream returns the keystream described in DeriveGenerator.
+func deriveStream(seed []byte, info string) cipher.StreamReader {
+	key := hkdf(sha256.New, seed, nil, []byte(info), 32)
+	block, _ := aes.NewCipher(key) // 32-byte key never fails
+	stream := cipher.NewCTR(block, make([]byte, aes.BlockSize))
+	return cipher.StreamReader{S: stream, R: zeroReader{}}
+}
+
+// zeroReader reads an endless stream of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	wipe(p)
+	return len(p), nil
+}
+
+// hkdf implements HKDF (RFC 5869) extract and expand.
+func hkdf(h func() hash.Hash, secret, salt, info []byte, length int) []byte {
+	if salt == nil {
+		salt = make([]byte, h().Size())
+	}
+	extract := hmac.New(h, salt)
+	extract.Write(secret)
+	prk := extract.Sum(nil)
+
+	var out, prev []byte
+	expand := hmac.New(h, prk)
+	for counter := byte(1); len(out) < length; counter++ {
+		expand.Reset()
+		expand.Write(prev)
+		expand.Write(info)
+		expand.Write([]byte{counter})
+		prev = expand.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:length]
+}
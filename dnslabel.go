@@ -0,0 +1,30 @@
+package uriuniq
+
+import "fmt"
+
+// dnsLabelCharset is the charset of Options.DNSLabel: the lowercase
+// letters, digits and hyphen of RFC 1035 labels.
+const dnsLabelCharset Charset = "0123456789abcdefghijklmnopqrstuvwxyz-"
+
+// checkDNSLabel rejects the Options that conflict with DNSLabel, as they
+// would put other chars into the label, and implies NoLeadingDigit.
+func checkDNSLabel(opts Options) (Options, error) {
+	if !opts.DNSLabel {
+		return opts, nil
+	}
+	if opts.CustomCharset != "" || opts.ExcludeNumeric || opts.ExcludeLowercase || opts.ExcludeUppercase ||
+		opts.Transform != TransformNone || opts.Prefix != "" || opts.GroupSize != 0 || opts.CheckChar {
+		return opts, newError(CodeInvalidArgument, "uriuniq: DNSLabel sets the charset and cannot have a custom charset, exclusions, transform, prefix, groups or check char")
+	}
+	opts.NoLeadingDigit = true
+	return opts, nil
+}
+
+// checkDNSLength checks that strings of prepared opts with DNSLabel fit
+// MaxDNSLabelLength.
+func checkDNSLength(opts Options) error {
+	if _, max := lengthRange(opts); opts.DNSLabel && max > MaxDNSLabelLength {
+		return fmt.Errorf("%w: label has up to %d octets", ErrDNSTooLong, max)
+	}
+	return nil
+}
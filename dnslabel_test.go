@@ -0,0 +1,59 @@
+package uriuniq
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+// rfc1035Label matches the labels of RFC 1035, in lowercase.
+var rfc1035Label = regexp.MustCompile(`^[a-z]([a-z0-9-]*[a-z0-9])?$`)
+
+// TestDNSLabel checks that every string is an RFC 1035 label and that
+// conflicting Options are rejected.
+func TestDNSLabel(t *testing.T) {
+	for _, opts := range []Options{
+		{Length: 2, DNSLabel: true},
+		{Length: 63, DNSLabel: true},
+		{MinLength: 1, MaxLength: 20, DNSLabel: true, ExcludeAmbiguous: true},
+		{Length: 12, DNSLabel: true, MinDigits: 2},
+	} {
+		g, err := NewGenerator(opts)
+		if err != nil {
+			t.Fatalf("NewGenerator(%+v) failed: %s", opts, err)
+		}
+		for i := 0; i < 500; i++ {
+			id, err := g.Next()
+			if err != nil {
+				t.Fatalf("Next failed: %s", err)
+			}
+			if !rfc1035Label.MatchString(id) {
+				t.Fatalf("%q is not a DNS label", id)
+			}
+			if err := Validate(id, opts); err != nil {
+				t.Fatalf("Validate(%q) failed: %s", id, err)
+			}
+		}
+	}
+
+	var perr *ParseError
+	opts := Options{Length: 4, DNSLabel: true}
+	for _, s := range []string{"1abc", "-abc", "abc-"} {
+		if err := Validate(s, opts); !errors.As(err, &perr) {
+			t.Errorf("Expected ParseError for %q, got %v", s, err)
+		}
+	}
+	if _, err := NewGenerator(Options{Length: 64, DNSLabel: true}); !errors.Is(err, ErrDNSTooLong) {
+		t.Errorf("Expected ErrDNSTooLong, got %v", err)
+	}
+	for _, opts := range []Options{
+		{Length: 8, DNSLabel: true, CustomCharset: Hex},
+		{Length: 8, DNSLabel: true, Prefix: "pod"},
+		{Length: 8, DNSLabel: true, CheckChar: true},
+		{Length: 8, DNSLabel: true, NodeChars: 1},
+	} {
+		if _, err := NewGenerator(opts); CodeOf(err) != CodeInvalidArgument {
+			t.Errorf("Expected CodeInvalidArgument for %+v, got %v", opts, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package uriuniq
+
+import (
+	"bytes"
+	"strings"
+)
+
+// DisplayFormat describes the display form created by GenerateDual.
+type DisplayFormat struct {
+	GroupSize int  // Chars per group, 0 for no grouping
+	Separator byte // Between groups, defaults to '-'
+	CheckChar bool // Append a Luhn mod N check char, as Options.CheckChar does
+}
+
+// DualID holds the two forms of one ID.
+type DualID struct {
+	Display   string // For people: grouped, as in "K7QX-9M2P-4"
+	Canonical string // For storage and lookups: ungrouped, as in "K7QX9M2P4"
+}
+
+// GenerateDual creates a random string using Options and returns its
+// display and canonical forms. The Options apply as for Generate, with the
+// prefix leading both forms, except that the grouping is that of format:
+// GroupSize and EscapePercent are rejected. The check char of format is
+// that of Options.CheckChar and part of both forms. The display form is
+// built from the canonical one, and ParseDisplay turns it back, so the two
+// always correspond.
+func GenerateDual(opts Options, format DisplayFormat) (DualID, error) {
+	opts, charset, err := dualOptions(opts, format)
+	if err != nil {
+		return DualID{}, err
+	}
+	sep := displaySeparator(format)
+	if format.GroupSize > 0 && bytes.IndexByte(charset, sep) >= 0 {
+		return DualID{}, errorf(CodeInvalidArgument, "uriuniq: separator %q in charset", sep)
+	}
+	output, err := generate(opts)
+	if err != nil {
+		return DualID{}, err
+	}
+	body := string(output)
+	if opts.Sensitive {
+		wipe(output)
+	}
+	prefix := idPrefix(opts)
+	return DualID{Display: prefix + groupChars(body, format.GroupSize, sep), Canonical: prefix + body}, nil
+}
+
+// ParseDisplay returns the canonical form of an ID in display form, such
+// as one typed in by a person. Separators, spaces and hyphens not in the
+// charset are removed, case is folded for single-case charsets and the
+// result is checked by Validate.
+func ParseDisplay(display string, opts Options, format DisplayFormat) (string, error) {
+	opts, charset, err := dualOptions(opts, format)
+	if err != nil {
+		return "", err
+	}
+	id := canonicalOptions(display, opts)
+	prefix := idPrefix(opts)
+	if sep := displaySeparator(format); strings.HasPrefix(id, prefix) && bytes.IndexByte(charset, sep) < 0 {
+		id = prefix + stripSeparators(id[len(prefix):], string(sep))
+	}
+	if err := Validate(id, opts); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// dualOptions returns opts with the check char of format and the charset,
+// rejecting the Options GenerateDual cannot honor.
+func dualOptions(opts Options, format DisplayFormat) (Options, []byte, error) {
+	if opts.GroupSize > 0 {
+		return opts, nil, newError(CodeInvalidArgument, "uriuniq: dual IDs are grouped by DisplayFormat, not GroupSize")
+	}
+	if opts.Escape == EscapePercent {
+		return opts, nil, newError(CodeInvalidArgument, "uriuniq: dual IDs cannot be percent-encoded")
+	}
+	opts.CheckChar = opts.CheckChar || format.CheckChar
+	_, charset, err := prepare(opts)
+	return opts, charset, err
+}
+
+// CheckChar returns the Luhn mod N check char of code over charset, as
+// appended by GenerateDual with DisplayFormat.CheckChar and by ShortCodes.
+// Every char of code must be in charset. Only for charsets of even size
+// does it catch every single-char error.
+func CheckChar(code string, charset Charset) byte {
+	return luhnMod(code, charset)
+}
+
+// displaySeparator returns the group separator of format.
+func displaySeparator(format DisplayFormat) byte {
+	if format.Separator == 0 {
+		return '-'
+	}
+	return format.Separator
+}
+
+// groupChars inserts sep between groups of size chars of s.
+func groupChars(s string, size int, sep byte) string {
+	if size <= 0 || len(s) <= size {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/size)
+	for i := 0; i < len(s); i += size {
+		if i > 0 {
+			b.WriteByte(sep)
+		}
+		end := i + size
+		if end > len(s) {
+			end = len(s)
+		}
+		b.WriteString(s[i:end])
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package uriuniq
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateDual checks that both forms correspond.
+func TestGenerateDual(t *testing.T) {
+	opts := Options{Length: 8, CustomCharset: ShortCodeCharset}
+	format := DisplayFormat{GroupSize: 4, CheckChar: true}
+	for i := 0; i < 50; i++ {
+		id, err := GenerateDual(opts, format)
+		if err != nil {
+			t.Fatalf("GenerateDual failed: %s", err)
+		}
+		if len(id.Canonical) != 9 || len(id.Display) != 11 || strings.ReplaceAll(id.Display, "-", "") != id.Canonical {
+			t.Fatalf("Unexpected forms %+v", id)
+		}
+		if got, err := ParseDisplay(strings.ToLower(id.Display), opts, format); err != nil || got != id.Canonical {
+			t.Fatalf("ParseDisplay(%q): expected %q, got %q, %v", id.Display, id.Canonical, got, err)
+		}
+	}
+
+	if id, _ := GenerateDual(Options{Length: 6}, DisplayFormat{}); id.Display != id.Canonical || len(id.Canonical) != 6 {
+		t.Errorf("Expected equal forms without grouping, got %+v", id)
+	}
+	if _, err := GenerateDual(Options{Length: 6, CustomCharset: "ab-"}, DisplayFormat{GroupSize: 2}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected separator error, got %v", err)
+	}
+}
+
+// TestParseDisplay checks validation of display forms.
+func TestParseDisplay(t *testing.T) {
+	opts := Options{Length: 4, CustomCharset: Numeric}
+	format := DisplayFormat{GroupSize: 2, Separator: '.', CheckChar: true}
+	check := string(luhnMod("1234", Numeric))
+	tests := []struct {
+		name    string
+		display string
+		want    string
+		code    Code
+	}{
+		{"Valid", "12.34." + check, "1234" + check, ""},
+		{"Spaces", " 12 34 " + check, "1234" + check, ""},
+		{"Typo", "12.35." + check, "", CodeBadCheckChar},
+		{"Short", "12.3", "", CodeInvalidLength},
+		{"Bad Char", "12.3x." + check, "", CodeInvalidChar},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseDisplay(tc.display, opts, format)
+			if got != tc.want || tc.code != "" && CodeOf(err) != tc.code {
+				t.Errorf("Expected %q (%s), got %q, %v", tc.want, tc.code, got, err)
+			}
+		})
+	}
+}
+
+// TestCheckChar checks that CheckChar matches the check char appended by
+// GenerateDual.
+func TestCheckChar(t *testing.T) {
+	opts := Options{Length: 8, CustomCharset: ShortCodeCharset}
+	for i := 0; i < 20; i++ {
+		id, err := GenerateDual(opts, DisplayFormat{CheckChar: true})
+		if err != nil {
+			t.Fatalf("GenerateDual failed: %s", err)
+		}
+		code := id.Canonical
+		if c := CheckChar(code[:len(code)-1], ShortCodeCharset); c != code[len(code)-1] {
+			t.Errorf("Expected check char %q for %s, got %q", code[len(code)-1], code, c)
+		}
+	}
+}
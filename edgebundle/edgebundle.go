@@ -0,0 +1,249 @@
+// Package edgebundle exports what is needed to validate IDs without calling
+// the ID service, for CDN workers and other edge runtimes that should
+// reject junk IDs before they reach the origin. A Bundle holds the ID
+// formats as regexps with their check-char parameters, the version map and
+// the public HMAC parameters; it is signed with Ed25519, so the edge only
+// needs the public key to trust it.
+//
+// Example:
+//
+//	// In the ID service:
+//	b, err := edgebundle.FromVersions(versions)
+//	token, err := b.Sign(privateKey)
+//	// At the edge:
+//	v, err := edgebundle.Load(token, publicKey)
+//	if _, err := v.Validate(id); err != nil {
+//	    http.Error(w, "invalid id", http.StatusNotFound)
+//	}
+package edgebundle
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+var (
+	// ErrInvalidBundle is returned for a malformed bundle or a bad
+	// signature.
+	ErrInvalidBundle = &uriuniq.Error{Code: uriuniq.CodeInvalidToken, Err: errors.New("edgebundle: invalid bundle")}
+	// ErrNoMatch is returned for an ID matching no rule of the bundle.
+	ErrNoMatch = &uriuniq.Error{Code: uriuniq.CodeNoFormatMatch, Err: errors.New("edgebundle: no rule matches")}
+	// ErrBadCheckChar is returned for an ID with a wrong check char.
+	ErrBadCheckChar = &uriuniq.Error{Code: uriuniq.CodeBadCheckChar, Err: errors.New("edgebundle: bad check char")}
+)
+
+// Rule is an ID format of a Bundle.
+type Rule struct {
+	Name string `json:"n"`
+	// Marker is the version marker IDs of the rule start with, empty for
+	// none.
+	Marker string `json:"m,omitempty"`
+	// Pattern is the anchored regexp matching the whole ID.
+	Pattern string `json:"p"`
+	// Charset and CheckChar are the check-char parameters: if CheckChar is
+	// set, the last char of an ID is its uriuniq.CheckChar over Charset,
+	// computed over the random part after Prefix with the GroupSeparator
+	// removed, as uriuniq.Verify does.
+	Charset        uriuniq.Charset `json:"cs,omitempty"`
+	CheckChar      bool            `json:"cc,omitempty"`
+	Prefix         string          `json:"px,omitempty"` // Prefix with its separator
+	GroupSeparator string          `json:"gs,omitempty"`
+}
+
+// NewRule creates a Rule matching the IDs generated with opts, including
+// the check char of Options.CheckChar.
+func NewRule(name string, opts uriuniq.Options) (Rule, error) {
+	return newRule(name, "", opts)
+}
+
+// newRule creates a Rule for IDs starting with marker.
+func newRule(name, marker string, opts uriuniq.Options) (Rule, error) {
+	re, err := uriuniq.Pattern(opts)
+	if err != nil {
+		return Rule{}, err
+	}
+	charset, err := uriuniq.CharsetOf(opts)
+	if err != nil {
+		return Rule{}, err
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(re.String(), "^"), "$")
+	r := Rule{
+		Name:      name,
+		Marker:    marker,
+		Pattern:   "^" + regexp.QuoteMeta(marker) + body + "$",
+		Charset:   charset,
+		CheckChar: opts.CheckChar,
+	}
+	if opts.CheckChar {
+		if opts.Prefix != "" {
+			sep := opts.PrefixSeparator
+			if sep == "" {
+				sep = uriuniq.DefaultPrefixSeparator
+			}
+			r.Prefix = opts.Prefix + sep
+		}
+		if opts.GroupSize > 0 {
+			r.GroupSeparator = opts.GroupSeparator
+			if r.GroupSeparator == "" {
+				r.GroupSeparator = uriuniq.DefaultGroupSeparator
+			}
+		}
+	}
+	return r, nil
+}
+
+// HMACParams are the public parameters of the HMAC signing IDs or URLs,
+// such as the algorithm and the ID of the current key. The key itself
+// never goes into a Bundle.
+type HMACParams struct {
+	Alg   string `json:"alg"`
+	KeyID string `json:"kid,omitempty"`
+}
+
+// Bundle is everything needed to validate IDs offline.
+type Bundle struct {
+	Issued time.Time `json:"iat"`
+	Rules  []Rule    `json:"r"`
+	// Versions maps version markers to the names of their rules.
+	Versions map[string]string `json:"v,omitempty"`
+	HMAC     *HMACParams       `json:"h,omitempty"`
+}
+
+// FromVersions creates a Bundle with a rule for each version of v, named
+// after its marker, matching the IDs of v.Generate: the marker followed by
+// the prefix, random part and check char of the version.
+func FromVersions(v *uriuniq.Versions) (*Bundle, error) {
+	b := &Bundle{Issued: time.Now().UTC(), Versions: make(map[string]string)}
+	for _, m := range v.Markers() {
+		opts, _ := v.Options(m)
+		r, err := newRule("v"+string(m), string(m), opts)
+		if err != nil {
+			return nil, err
+		}
+		b.Rules = append(b.Rules, r)
+		b.Versions[string(m)] = r.Name
+	}
+	return b, nil
+}
+
+// Sign serializes b and signs it with priv, returning a compact token.
+func (b *Bundle) Sign(priv ed25519.PrivateKey) (string, error) {
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return "", err
+	}
+	sig := ed25519.Sign(priv, payload)
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(sig), nil
+}
+
+// Validator validates IDs against a loaded Bundle. It is safe for
+// concurrent use.
+type Validator struct {
+	Bundle *Bundle
+
+	rules    []compiled
+	versions map[byte]int // Version marker to index into rules
+}
+
+// compiled is a Rule with its compiled pattern.
+type compiled struct {
+	Rule
+	re *regexp.Regexp
+}
+
+// Load verifies token with pub and compiles its bundle.
+func Load(token string, pub ed25519.PublicKey) (*Validator, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidBundle
+	}
+	enc := base64.RawURLEncoding
+	p, err := enc.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidBundle
+	}
+	s, err := enc.DecodeString(sig)
+	if err != nil || len(pub) != ed25519.PublicKeySize || !ed25519.Verify(pub, p, s) {
+		return nil, ErrInvalidBundle
+	}
+	var b Bundle
+	if err := json.Unmarshal(p, &b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+
+	v := &Validator{Bundle: &b, versions: make(map[byte]int)}
+	index := make(map[string]int)
+	for _, r := range b.Rules {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %q: %v", ErrInvalidBundle, r.Name, err)
+		}
+		index[r.Name] = len(v.rules)
+		v.rules = append(v.rules, compiled{Rule: r, re: re})
+	}
+	for m, name := range b.Versions {
+		i, ok := index[name]
+		if len(m) != 1 || !ok {
+			return nil, fmt.Errorf("%w: version %q", ErrInvalidBundle, m)
+		}
+		v.versions[m[0]] = i
+	}
+	return v, nil
+}
+
+// Validate checks id and returns the name of the rule it matches. An ID
+// starting with a version marker is checked against the rule of that
+// version first; then the rules without marker are tried in order. If no
+// rule accepts id, the error of the first rule whose pattern matched is
+// returned, or ErrNoMatch.
+func (v *Validator) Validate(id string) (rule string, err error) {
+	if id == "" {
+		return "", uriuniq.ErrEmptyInput
+	}
+	rule, err = "", ErrNoMatch
+	if i, ok := v.versions[id[0]]; ok {
+		if rule, err = v.rules[i].Name, v.rules[i].check(id); err == nil {
+			return rule, nil
+		}
+	}
+	for _, r := range v.rules {
+		if r.Marker == "" && r.re.MatchString(id) {
+			cerr := r.check(id)
+			if cerr == nil {
+				return r.Name, nil
+			}
+			if err == ErrNoMatch {
+				rule, err = r.Name, cerr
+			}
+		}
+	}
+	return rule, err
+}
+
+// check checks id against the pattern and check char of r.
+func (r compiled) check(id string) error {
+	if !r.re.MatchString(id) {
+		return ErrNoMatch
+	}
+	if r.CheckChar {
+		// The pattern matched, so the marker and prefix are in place and
+		// the separators are not in the charset.
+		code := strings.TrimPrefix(id[len(r.Marker):], r.Prefix)
+		if r.GroupSeparator != "" {
+			code = strings.ReplaceAll(code, r.GroupSeparator, "")
+		}
+		if uriuniq.CheckChar(code[:len(code)-1], r.Charset) != code[len(code)-1] {
+			return ErrBadCheckChar
+		}
+	}
+	return nil
+}
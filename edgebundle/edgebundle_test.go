@@ -0,0 +1,180 @@
+package edgebundle
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestLoadValidate checks signing, loading and validating IDs against
+// version and check-char rules.
+func TestLoadValidate(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %s", err)
+	}
+	versions := uriuniq.NewVersions()
+	if err := versions.Register('1', uriuniq.Options{Length: 6, CustomCharset: uriuniq.Numeric}); err != nil {
+		t.Fatalf("Register failed: %s", err)
+	}
+	b, err := FromVersions(versions)
+	if err != nil {
+		t.Fatalf("FromVersions failed: %s", err)
+	}
+	codeOpts := uriuniq.Options{Length: 8, CustomCharset: uriuniq.ShortCodeCharset, CheckChar: true}
+	rule, err := NewRule("code", codeOpts)
+	if err != nil {
+		t.Fatalf("NewRule failed: %s", err)
+	}
+	b.Rules = append(b.Rules, rule)
+	typedOpts := uriuniq.Options{Length: 8, Prefix: "inv", CheckChar: true, GroupSize: 3}
+	typed, err := NewRule("typed", typedOpts)
+	if err != nil {
+		t.Fatalf("NewRule failed: %s", err)
+	}
+	b.Rules = append(b.Rules, typed)
+	plain, err := NewRule("plain", uriuniq.Options{Length: 10, CustomCharset: uriuniq.Hex})
+	if err != nil {
+		t.Fatalf("NewRule failed: %s", err)
+	}
+	b.Rules = append(b.Rules, plain)
+	b.HMAC = &HMACParams{Alg: "HS256", KeyID: "k1"}
+
+	token, err := b.Sign(priv)
+	if err != nil {
+		t.Fatalf("Sign failed: %s", err)
+	}
+	v, err := Load(token, pub)
+	if err != nil {
+		t.Fatalf("Load failed: %s", err)
+	}
+	if v.Bundle.HMAC == nil || v.Bundle.HMAC.KeyID != "k1" {
+		t.Errorf("Unexpected HMAC params %+v", v.Bundle.HMAC)
+	}
+
+	code, err := uriuniq.GenerateWith(uriuniq.WithOptions(codeOpts))
+	if err != nil {
+		t.Fatalf("GenerateWith failed: %s", err)
+	}
+	typedID, err := uriuniq.GenerateWith(uriuniq.WithOptions(typedOpts))
+	if err != nil || !uriuniq.Verify(typedID, typedOpts) {
+		t.Fatalf("Unexpected typed ID %q, %v", typedID, err)
+	}
+	typedBad := []byte(typedID)
+	for _, c := range typed.Charset {
+		if byte(c) != typedBad[len(typedBad)-1] {
+			typedBad[len(typedBad)-1] = byte(c)
+			break
+		}
+	}
+	var bad string
+	for _, c := range rule.Charset {
+		if byte(c) != code[len(code)-1] {
+			bad = code[:len(code)-1] + string(c)
+			break
+		}
+	}
+
+	marked := "1" + code[1:len(code)-1]
+	marked += string(uriuniq.CheckChar(marked, rule.Charset))
+
+	tests := []struct {
+		id   string
+		rule string
+		err  error
+	}{
+		{"1123456", "v1", nil},
+		{"112345", "v1", ErrNoMatch},
+		{"112345a", "v1", ErrNoMatch},
+		{code, "code", nil},
+		{bad, "code", ErrBadCheckChar},
+		{"junk!", "", ErrNoMatch},
+		{typedID, "typed", nil},
+		{string(typedBad), "typed", ErrBadCheckChar},
+		// Without Options.CheckChar no check char is expected.
+		{"0123456789", "plain", nil},
+		// Codes starting with a version marker still match their rule.
+		{marked, "code", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			rule, err := v.Validate(tt.id)
+			if !errors.Is(err, tt.err) || rule != tt.rule {
+				t.Errorf("Expected %q, %v, got %q, %v", tt.rule, tt.err, rule, err)
+			}
+		})
+	}
+	if _, err := v.Validate(""); uriuniq.CodeOf(err) != uriuniq.CodeEmptyInput {
+		t.Errorf("Expected empty input error, got %v", err)
+	}
+}
+
+// TestFromVersionsOptions checks that the rules of FromVersions accept the
+// IDs Versions.Generate creates with a prefix, check char and grouping.
+func TestFromVersionsOptions(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	versions := uriuniq.NewVersions()
+	var ids []string
+	for marker, opts := range map[byte]uriuniq.Options{
+		'a': {Length: 8, Prefix: "cus"},
+		'b': {Length: 9, Prefix: "cus", CheckChar: true, GroupSize: 4},
+	} {
+		if err := versions.Register(marker, opts); err != nil {
+			t.Fatalf("Register failed: %s", err)
+		}
+		for i := 0; i < 20; i++ {
+			id, err := versions.Generate()
+			if err != nil {
+				t.Fatalf("Generate failed: %s", err)
+			}
+			ids = append(ids, id)
+		}
+	}
+	b, err := FromVersions(versions)
+	if err != nil {
+		t.Fatalf("FromVersions failed: %s", err)
+	}
+	token, err := b.Sign(priv)
+	if err != nil {
+		t.Fatalf("Sign failed: %s", err)
+	}
+	v, err := Load(token, pub)
+	if err != nil {
+		t.Fatalf("Load failed: %s", err)
+	}
+	for _, id := range ids {
+		if rule, err := v.Validate(id); err != nil || rule != "v"+id[:1] {
+			t.Errorf("Validate(%q) = %q, %v", id, rule, err)
+		}
+	}
+}
+
+// TestLoadRejects checks that altered or wrongly signed bundles are
+// rejected.
+func TestLoadRejects(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	other, _, _ := ed25519.GenerateKey(nil)
+	token, err := (&Bundle{}).Sign(priv)
+	if err != nil {
+		t.Fatalf("Sign failed: %s", err)
+	}
+	tests := []struct {
+		name  string
+		token string
+		key   ed25519.PublicKey
+	}{
+		{"other key", token, other},
+		{"altered", "e30" + token[3:], pub},
+		{"no signature", token[:len(token)-87], pub},
+		{"malformed", "!.!", pub},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.token, tt.key); !errors.Is(err, ErrInvalidBundle) {
+				t.Errorf("Expected ErrInvalidBundle, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package uriuniq
+
+import (
+	"math"
+	"math/big"
+)
+
+// GenerateEncoded draws exactly n random bytes and encodes them with
+// EncodeBytes, for specs that mandate a byte-aligned amount of entropy such
+// as "128 random bits, base62-encoded". Unlike Generate, the length of the
+// result follows from n and the charset of opts; opts.Length is ignored.
+// DecodeBytes recovers the bytes.
+func GenerateEncoded(n int, opts Options) (string, error) {
+	if n <= 0 {
+		return "", newError(CodeInvalidLength, "uriuniq: byte count must be positive")
+	}
+	src := newEntropyReader(opts)
+	b := make([]byte, n)
+	if opts.Sensitive {
+		defer wipe(b)
+	}
+	if err := src.ReadFull(b); err != nil {
+		return "", err
+	}
+	return EncodeBytes(b, opts)
+}
+
+// EncodeBytes encodes b as a big-endian number in the charset of opts,
+// left-padded with its first char to the width needed by any len(b) bytes,
+// so all encodings of the same byte count have the same length.
+func EncodeBytes(b []byte, opts Options) (string, error) {
+	_, charset, err := prepareEncoding(opts)
+	if err != nil {
+		return "", err
+	}
+	base := big.NewInt(int64(len(charset)))
+	width := encodedWidth(len(b), base)
+	out := make([]byte, width)
+	v := new(big.Int).SetBytes(b)
+	digit := new(big.Int)
+	for i := width - 1; i >= 0; i-- {
+		v.DivMod(v, base, digit)
+		out[i] = charset[digit.Int64()]
+	}
+	return string(out), nil
+}
+
+// DecodeBytes decodes a string created by EncodeBytes from n bytes with the
+// same opts. If n is 0, it is inferred from the length of s: every byte
+// count has its own width, as a char holds less than a byte.
+func DecodeBytes(s string, n int, opts Options) ([]byte, error) {
+	_, charset, err := prepareEncoding(opts)
+	if err != nil {
+		return nil, err
+	}
+	base := big.NewInt(int64(len(charset)))
+	if n == 0 {
+		if err := checkInputLength(s, DefaultMaxInputLength); err != nil {
+			return nil, err
+		}
+		n = int(float64(len(s)) * math.Log2(float64(len(charset))) / 8)
+		if n > 1 {
+			n--
+		} else {
+			n = 1
+		}
+		for encodedWidth(n, base) < len(s) {
+			n++
+		}
+	}
+	if width := encodedWidth(n, base); len(s) != width {
+		return nil, errorf(CodeInvalidLength, "uriuniq: encoded length %d, expected %d", len(s), width)
+	}
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i, c := range charset {
+		index[c] = i
+	}
+	v := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		d := index[s[i]]
+		if d < 0 {
+			return nil, &ParseError{Index: i, Char: rune(s[i]), Charset: Charset(charset)}
+		}
+		v.Mul(v, base)
+		v.Add(v, big.NewInt(int64(d)))
+	}
+	if v.BitLen() > 8*n {
+		return nil, newError(CodeInvalidArgument, "uriuniq: encoded value overflows byte count")
+	}
+	return v.FillBytes(make([]byte, n)), nil
+}
+
+// Encode encodes data in charset as EncodeBytes does, so binary IDs such
+// as UUIDs or hashes look like generated ones; Decode reverses it. It fails
+// if charset has fewer than 2 or more than 256 chars or duplicates.
+func Encode(data []byte, charset Charset) (string, error) {
+	return EncodeBytes(data, charsetOptions(charset))
+}
+
+// Decode decodes a string created by Encode with the same charset,
+// inferring the byte count from its length. Strings longer than
+// DefaultMaxInputLength are rejected with ErrInputTooLong.
+func Decode(s string, charset Charset) ([]byte, error) {
+	if s == "" {
+		if _, _, err := prepareEncoding(charsetOptions(charset)); err != nil {
+			return nil, err
+		}
+		return []byte{}, nil
+	}
+	return DecodeBytes(s, 0, charsetOptions(charset))
+}
+
+// charsetOptions returns Options drawing from charset alone.
+func charsetOptions(charset Charset) Options {
+	return Options{Length: 1, CustomCharset: charset}
+}
+
+// DecodeEntropy returns the random bytes of an id created by
+// GenerateEncoded with default Options, for example to compute an HMAC over
+// them or to store the binary form. IDs sampled char by char with Generate
+// cannot be decoded this way. Like Decode, it rejects ids longer than
+// DefaultMaxInputLength.
+func DecodeEntropy(id string) ([]byte, error) {
+	return DecodeBytes(id, 0, NewOpts())
+}
+
+// prepareEncoding prepares opts for a positional encoding, which needs a
+// charset without duplicates.
+func prepareEncoding(opts Options) (Options, []byte, error) {
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return opts, nil, err
+	}
+	if len(charset) < 2 || len(charset) > 256 {
+		return opts, nil, newError(CodeCharsetSize, "uriuniq: charset size 2-256")
+	}
+	var seen [256]bool
+	for _, c := range charset {
+		if seen[c] {
+			return opts, nil, errorf(CodeDuplicateChar, "uriuniq: duplicate char %q in charset", c)
+		}
+		seen[c] = true
+	}
+	return opts, charset, nil
+}
+
+// encodedWidth returns the smallest w with base^w >= 256^n.
+func encodedWidth(n int, base *big.Int) int {
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+	pow := func(w int) *big.Int { return new(big.Int).Exp(base, big.NewInt(int64(w)), nil) }
+	// Start from the floating-point estimate and correct its rounding.
+	w := int(math.Ceil(float64(8*n) / math.Log2(float64(base.Int64()))))
+	for w > 0 && pow(w-1).Cmp(limit) >= 0 {
+		w--
+	}
+	for pow(w).Cmp(limit) < 0 {
+		w++
+	}
+	return w
+}
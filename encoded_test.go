@@ -0,0 +1,114 @@
+package uriuniq
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestGenerateEncoded checks the fixed width and round trip of encodings.
+func TestGenerateEncoded(t *testing.T) {
+	tests := []struct {
+		name    string
+		charset Charset
+		n       int
+		width   int
+	}{
+		{"128 Bits Base62", "", 16, 22},
+		{"128 Bits Hex", "0123456789abcdef", 16, 32},
+		{"1 Byte Numeric", Numeric, 1, 3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := NewOpts()
+			opts.CustomCharset = tc.charset
+			for i := 0; i < 20; i++ {
+				s, err := GenerateEncoded(tc.n, opts)
+				if err != nil {
+					t.Fatalf("GenerateEncoded failed: %s", err)
+				}
+				if len(s) != tc.width {
+					t.Fatalf("Expected width %d, got %q", tc.width, s)
+				}
+				if _, err := DecodeBytes(s, tc.n, opts); err != nil {
+					t.Fatalf("DecodeBytes(%q) failed: %s", s, err)
+				}
+			}
+		})
+	}
+
+	opts := NewOpts()
+	for _, b := range [][]byte{{0, 0}, {0xff, 0xff}, {0x01, 0x00}} {
+		s, err := EncodeBytes(b, opts)
+		if err != nil {
+			t.Fatalf("EncodeBytes failed: %s", err)
+		}
+		got, err := DecodeBytes(s, len(b), opts)
+		if err != nil || !bytes.Equal(got, b) {
+			t.Errorf("Round trip of %x gave %x, %v", b, got, err)
+		}
+	}
+	if s, _ := EncodeBytes([]byte{0xff}, opts); s != "47" {
+		t.Errorf("Expected 255 as \"47\", got %q", s)
+	}
+	if _, err := DecodeBytes("zz", 1, opts); err == nil {
+		t.Errorf("Expected overflow error")
+	}
+	if _, err := DecodeBytes("4!", 1, opts); err == nil {
+		t.Errorf("Expected ParseError")
+	}
+	opts.CustomCharset = "aab"
+	if _, err := EncodeBytes([]byte{1}, opts); err == nil {
+		t.Errorf("Expected error for duplicate chars")
+	}
+}
+
+// TestDecodeEntropy checks that the byte count is inferred from the width.
+func TestDecodeEntropy(t *testing.T) {
+	for n := 1; n <= 40; n++ {
+		b := bytes.Repeat([]byte{0xa5}, n)
+		s, err := EncodeBytes(b, NewOpts())
+		if err != nil {
+			t.Fatalf("EncodeBytes failed: %s", err)
+		}
+		got, err := DecodeEntropy(s)
+		if err != nil || !bytes.Equal(got, b) {
+			t.Fatalf("DecodeEntropy(%q) = %x, %v, expected %x", s, got, err, b)
+		}
+	}
+	// 4 base62 chars never encode a whole number of bytes.
+	if _, err := DecodeEntropy("abcd"); err == nil {
+		t.Errorf("Expected error for width without byte count")
+	}
+	if _, err := DecodeEntropy(""); err == nil {
+		t.Errorf("Expected error for empty input")
+	}
+}
+
+// TestEncode checks that Encode and Decode round-trip binary IDs in each
+// preset charset, keeping leading zero bytes.
+func TestEncode(t *testing.T) {
+	uuid := []byte{0, 0, 0x4a, 0x1b, 0x2c, 0x3d, 0x4e, 0x5f, 0x60, 0x71, 0x82, 0x93, 0xa4, 0xb5, 0xc6, 0xd7}
+	for _, charset := range []Charset{Alphanumeric, Hex, Base58, Base32Crockford, Base64URL} {
+		t.Run(string(charset[:4]), func(t *testing.T) {
+			for _, data := range [][]byte{uuid, {0}, {0xff}, {}} {
+				s, err := Encode(data, charset)
+				if err != nil || checkCharset(s, charset) != nil {
+					t.Errorf("Encode(%x) = %q, outside charset", data, s)
+				}
+				got, err := Decode(s, charset)
+				if err != nil || !bytes.Equal(got, data) {
+					t.Errorf("Decode(%q) = %x, %v, expected %x", s, got, err, data)
+				}
+			}
+		})
+	}
+	if got, err := Encode([]byte{0xde, 0xad}, Hex); err != nil || got != "dead" {
+		t.Errorf("Expected dead, got %q, %v", got, err)
+	}
+	if _, err := Decode("xyz", Hex); CodeOf(err) != CodeInvalidChar && CodeOf(err) != CodeInvalidLength {
+		t.Errorf("Expected invalid input error, got %v", err)
+	}
+	if _, err := Encode([]byte{1}, "aab"); err == nil {
+		t.Errorf("Expected error for duplicate chars")
+	}
+}
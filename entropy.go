@@ -0,0 +1,154 @@
+package uriuniq
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Source is a named entropy source, see FailoverSource.
+type Source struct {
+	Name   string
+	Reader io.Reader
+}
+
+// CryptoRand is the default crypto/rand source. On Linux it reads from
+// the getrandom system call directly.
+var CryptoRand = Source{Name: "crypto/rand", Reader: rand.Reader}
+
+// DeviceSource reads entropy from a device file such as /dev/hwrng.
+// The file is opened on each read, so a missing device fails over cleanly.
+func DeviceSource(path string) Source {
+	return Source{Name: path, Reader: deviceReader(path)}
+}
+
+type deviceReader string
+
+func (d deviceReader) Read(p []byte) (int, error) {
+	f, err := os.Open(string(d))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.ReadFull(f, p)
+}
+
+// FailoverSource reads from an ordered list of sources, moving on to the
+// next one when a source fails. Every read fills p completely from a single
+// source. Set it as Options.EntropySource.
+type FailoverSource struct {
+	Sources []Source
+
+	// OnServe, if set, is called with the name of the source that served
+	// each read.
+	OnServe func(name string)
+	// OnError, if set, is called for each source that failed a read.
+	OnError func(name string, err error)
+}
+
+// Read fills p from the first source that succeeds. If all sources fail,
+// the returned error wraps each of their errors.
+func (f *FailoverSource) Read(p []byte) (int, error) {
+	var errs []error
+	for _, src := range f.Sources {
+		_, err := io.ReadFull(src.Reader, p)
+		if err == nil {
+			if f.OnServe != nil {
+				f.OnServe(src.Name)
+			}
+			return len(p), nil
+		}
+		if f.OnError != nil {
+			f.OnError(src.Name, err)
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", src.Name, err))
+	}
+	if len(errs) == 0 {
+		return 0, newError(CodeEntropySource, "uriuniq: no entropy sources")
+	}
+	return 0, errorf(CodeEntropySource, "uriuniq: all entropy sources failed: %w", errors.Join(errs...))
+}
+
+// MixSources returns a reader whose output is the XOR of equal-length reads
+// from every source. As long as one source is uniformly random and
+// independent of the others, the output is uniformly random, so a
+// compromised or biased source cannot weaken the result. A read fails if
+// any source fails.
+func MixSources(sources ...io.Reader) io.Reader {
+	return &mixReader{sources: sources}
+}
+
+type mixReader struct {
+	sources []io.Reader
+}
+
+func (m *mixReader) Read(p []byte) (int, error) {
+	if len(m.sources) == 0 {
+		return 0, newError(CodeEntropySource, "uriuniq: no entropy sources")
+	}
+	if _, err := io.ReadFull(m.sources[0], p); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, len(p))
+	defer wipe(buf)
+	for _, src := range m.sources[1:] {
+		if _, err := io.ReadFull(src, buf); err != nil {
+			wipe(p)
+			return 0, err
+		}
+		for i := range p {
+			p[i] ^= buf[i]
+		}
+	}
+	return len(p), nil
+}
+
+// ErrReplayExhausted is returned by a ReplaySource that ran out of bytes.
+var ErrReplayExhausted = newError(CodeReplayExhausted, "uriuniq: replay exhausted")
+
+// RecordSource wraps src and writes every byte read from it to w, so a
+// generation sequence can be reproduced with ReplaySource. The recording
+// is the raw entropy behind the IDs: treat it as secret and only record in
+// environments where that is acceptable.
+func RecordSource(src io.Reader, w io.Writer) io.Reader {
+	return &recordReader{src: src, w: w}
+}
+
+type recordReader struct {
+	src io.Reader
+	w   io.Writer
+}
+
+func (r *recordReader) Read(p []byte) (int, error) {
+	if _, err := io.ReadFull(r.src, p); err != nil {
+		return 0, err
+	}
+	if _, err := r.w.Write(p); err != nil {
+		return 0, errorf(CodeEntropySource, "uriuniq: recording entropy: %w", err)
+	}
+	return len(p), nil
+}
+
+// ReplaySource returns a source that serves the bytes captured by
+// RecordSource from r. Used with the same Options, it reproduces the
+// recorded sequence exactly. Once r is drained, reads fail with
+// ErrReplayExhausted.
+func ReplaySource(r io.Reader) io.Reader {
+	return replayReader{r: r}
+}
+
+type replayReader struct {
+	r io.Reader
+}
+
+func (r replayReader) Read(p []byte) (int, error) {
+	if _, err := io.ReadFull(r.r, p); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return 0, ErrReplayExhausted
+		}
+		return 0, err
+	}
+	return len(p), nil
+}
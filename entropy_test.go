@@ -0,0 +1,112 @@
+package uriuniq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// errReader always fails with err.
+type errReader struct{ err error }
+
+func (r errReader) Read(p []byte) (int, error) { return 0, r.err }
+
+// TestFailoverSource verifies sources are tried in order and reported.
+func TestFailoverSource(t *testing.T) {
+	errBroken := errors.New("broken")
+	var served, failed []string
+	src := &FailoverSource{
+		Sources: []Source{
+			{Name: "broken", Reader: errReader{errBroken}},
+			DeviceSource("/nonexistent/hwrng"),
+			CryptoRand,
+		},
+		OnServe: func(name string) { served = append(served, name) },
+		OnError: func(name string, err error) { failed = append(failed, name) },
+	}
+
+	opts := NewOpts()
+	opts.EntropySource = src
+	if _, err := Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if len(served) == 0 || served[0] != CryptoRand.Name {
+		t.Errorf("Expected %s to serve, got %v", CryptoRand.Name, served)
+	}
+	if len(failed) < 2 || failed[0] != "broken" || failed[1] != "/nonexistent/hwrng" {
+		t.Errorf("Unexpected failures %v", failed)
+	}
+}
+
+// TestFailoverSourceExhausted checks the error when every source fails.
+func TestFailoverSourceExhausted(t *testing.T) {
+	errBroken := errors.New("broken")
+	src := &FailoverSource{Sources: []Source{
+		{Name: "broken", Reader: errReader{errBroken}},
+		{Name: "short", Reader: bytes.NewReader([]byte{1, 2})},
+	}}
+	_, err := src.Read(make([]byte, 8))
+	if !errors.Is(err, errBroken) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected wrapped source errors, got %v", err)
+	}
+	if _, err := (&FailoverSource{}).Read(make([]byte, 8)); err == nil {
+		t.Errorf("Expected error with no sources")
+	}
+}
+
+// TestMixSources checks the XOR mixing construction.
+func TestMixSources(t *testing.T) {
+	a := bytes.NewReader([]byte{0x00, 0xff, 0x0f, 0xaa})
+	b := bytes.NewReader([]byte{0xff, 0xff, 0xf0, 0x55})
+	out := make([]byte, 4)
+	if _, err := MixSources(a, b).Read(out); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if want := []byte{0xff, 0x00, 0xff, 0xff}; !bytes.Equal(out, want) {
+		t.Errorf("Expected %x, got %x", want, out)
+	}
+
+	errBroken := errors.New("broken")
+	if _, err := MixSources(CryptoRand.Reader, errReader{errBroken}).Read(out); !errors.Is(err, errBroken) {
+		t.Errorf("Expected source error, got %v", err)
+	}
+	if _, err := MixSources().Read(out); err == nil {
+		t.Errorf("Expected error with no sources")
+	}
+
+	opts := NewOpts()
+	opts.EntropySource = MixSources(CryptoRand.Reader, CryptoRand.Reader)
+	if _, err := Generate(opts); err != nil {
+		t.Errorf("Generate failed: %s", err)
+	}
+}
+
+// TestRecordReplay verifies a recorded sequence is reproduced exactly.
+func TestRecordReplay(t *testing.T) {
+	var recording bytes.Buffer
+	opts := NewOpts()
+	opts.EntropySource = RecordSource(CryptoRand.Reader, &recording)
+	var recorded []string
+	for i := 0; i < 3; i++ {
+		id, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate failed: %s", err)
+		}
+		recorded = append(recorded, id)
+	}
+
+	opts.EntropySource = ReplaySource(&recording)
+	for i, want := range recorded {
+		got, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Replay failed: %s", err)
+		}
+		if got != want {
+			t.Errorf("Replay %d: expected %s, got %s", i, want, got)
+		}
+	}
+	if _, err := Generate(opts); !errors.Is(err, ErrReplayExhausted) {
+		t.Errorf("Expected ErrReplayExhausted, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package uriuniq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Code is a stable, machine-readable error code, for mapping errors to API
+// responses or translated messages without parsing their text. Use CodeOf
+// to get the code of an error.
+type Code string
+
+// Error codes. Their values will not change.
+const (
+	CodeUnknown         Code = "E_UNKNOWN"
+	CodeInvalidArgument Code = "E_INVALID_ARGUMENT"
+	CodeInvalidLength   Code = "E_INVALID_LENGTH"
+	CodeNoValidChars    Code = "E_NO_VALID_CHARS"
+	CodeCharsetSize     Code = "E_CHARSET_SIZE"
+	CodeDuplicateChar   Code = "E_DUPLICATE_CHAR"
+	CodeInvalidChar     Code = "E_INVALID_CHAR"
+	CodeEmptyInput      Code = "E_EMPTY_INPUT"
+	CodeInputTooLong    Code = "E_INPUT_TOO_LONG"
+	CodeTooManyBadReads Code = "E_TOO_MANY_BAD_READS"
+	CodeEntropySource   Code = "E_ENTROPY_SOURCE"
+	CodeReplayExhausted Code = "E_REPLAY_EXHAUSTED"
+	CodeTransformMerge  Code = "E_TRANSFORM_MERGE"
+	CodePadInCharset    Code = "E_PAD_IN_CHARSET"
+	CodeNoDelimiter     Code = "E_NO_DELIMITER"
+	CodeDNSTooLong      Code = "E_DNS_TOO_LONG"
+	CodeNoFormatMatch   Code = "E_NO_FORMAT_MATCH"
+	CodeUnknownVersion  Code = "E_UNKNOWN_VERSION"
+	CodeBadCheckChar    Code = "E_BAD_CHECK_CHAR"
+	CodeMalformedHash   Code = "E_MALFORMED_HASH"
+	CodeInvalidField    Code = "E_INVALID_FIELD"
+	CodeInvalidToken    Code = "E_INVALID_TOKEN"
+	CodeTokenExpired    Code = "E_TOKEN_EXPIRED"
+	CodeInternal        Code = "E_INTERNAL"
+	CodeFrozen          Code = "E_FROZEN"
+	CodeDeadline        Code = "E_DEADLINE_EXCEEDED"
+	CodeClosed          Code = "E_CLOSED"
+	CodeRetriesExceeded Code = "E_RETRIES_EXCEEDED"
+	CodeMissingPrefix   Code = "E_MISSING_PREFIX"
+	CodeUnsafeCharset   Code = "E_UNSAFE_CHARSET"
+	CodeOverflow        Code = "E_OVERFLOW"
+	CodeLowEntropy      Code = "E_LOW_ENTROPY"
+)
+
+// CodeInfo describes an error code.
+type CodeInfo struct {
+	Code        Code
+	Description string // English, for documentation and as a fallback
+}
+
+// catalog lists all error codes in the order of their declaration.
+var catalog = []CodeInfo{
+	{CodeUnknown, "An error not raised by this module."},
+	{CodeInvalidArgument, "An argument or option is invalid."},
+	{CodeInvalidLength, "A length, length range or width is invalid or does not match."},
+	{CodeNoValidChars, "The options leave no chars to draw from."},
+	{CodeCharsetSize, "The charset has too few or too many chars."},
+	{CodeDuplicateChar, "The charset contains a char more than once."},
+	{CodeInvalidChar, "The input contains a char outside the expected charset."},
+	{CodeEmptyInput, "The input is empty."},
+	{CodeInputTooLong, "The input exceeds the length limit."},
+	{CodeTooManyBadReads, "Too many entropy reads were rejected."},
+	{CodeEntropySource, "The entropy source failed."},
+	{CodeReplayExhausted, "A replayed entropy recording ran out."},
+	{CodeTransformMerge, "A case transform merges distinct chars."},
+	{CodePadInCharset, "The pad char is part of the charset."},
+	{CodeNoDelimiter, "No delimiter is left outside the charset."},
+	{CodeDNSTooLong, "The name exceeds the DNS length limits."},
+	{CodeNoFormatMatch, "The ID matches no accepted format."},
+	{CodeUnknownVersion, "The ID has an unknown version marker."},
+	{CodeBadCheckChar, "The check char of the code is wrong."},
+	{CodeMalformedHash, "The token hash is malformed."},
+	{CodeInvalidField, "A field of a composite ID is invalid."},
+	{CodeInvalidToken, "The token is unknown or was already used."},
+	{CodeTokenExpired, "The token has expired."},
+	{CodeInternal, "An unexpected panic was caught and turned into an error."},
+	{CodeFrozen, "The configuration is frozen and cannot be changed."},
+	{CodeDeadline, "The latency budget ran out before an ID was generated."},
+	{CodeClosed, "The pool or stream was closed."},
+	{CodeRetriesExceeded, "No unique ID was found within the allowed attempts."},
+	{CodeMissingPrefix, "The ID does not start with the expected prefix."},
+	{CodeUnsafeCharset, "The charset contains chars that are not URI-safe."},
+	{CodeOverflow, "A monotonic ID cannot be incremented within its millisecond."},
+	{CodeLowEntropy, "The options give strings with less entropy than required."},
+}
+
+// Catalog returns all error codes with their descriptions.
+func Catalog() []CodeInfo {
+	return append([]CodeInfo(nil), catalog...)
+}
+
+// Error is an error with a Code. Errors returned by this module and its
+// subpackages carry a code, possibly wrapped.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// ErrorCode returns e.Code.
+func (e *Error) ErrorCode() Code { return e.Code }
+
+// CodeOf returns the code of the outermost error in the chain of err that
+// has one, CodeUnknown if none has, or "" if err is nil.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var coded interface{ ErrorCode() Code }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return CodeUnknown
+}
+
+var (
+	// ErrFrozen is returned when changing a configuration after it was
+	// frozen.
+	ErrFrozen = newError(CodeFrozen, "uriuniq: configuration is frozen")
+	// ErrEmptyCharset is returned when the Options leave no chars.
+	ErrEmptyCharset = newError(CodeNoValidChars, "uriuniq: no valid chars")
+	// ErrInvalidLength is returned in Strict mode for a Length that is not
+	// positive.
+	ErrInvalidLength = newError(CodeInvalidLength, "uriuniq: invalid length")
+	// ErrUnsafeCharset is returned in Strict mode for a CustomCharset with
+	// chars that are not URI-safe.
+	ErrUnsafeCharset = newError(CodeUnsafeCharset, "uriuniq: charset is not URI-safe")
+	// ErrInsufficientEntropy is returned for Options whose strings carry
+	// less entropy than Options.RequireEntropyBits.
+	ErrInsufficientEntropy = newError(CodeLowEntropy, "uriuniq: insufficient entropy")
+)
+
+// newError returns an *Error with code and text.
+func newError(code Code, text string) error {
+	return &Error{Code: code, Err: errors.New(text)}
+}
+
+// errorf returns an *Error with code and a message formatted as by
+// fmt.Errorf.
+func errorf(code Code, format string, a ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, a...)}
+}
+
+// ParseError reports the first character of an input that is not part of the
+// expected charset. Use errors.As to inspect it.
+type ParseError struct {
+	Index   int     // Byte offset of the invalid char
+	Char    rune    // The invalid char
+	Charset Charset // Charset the input was checked against, empty if rejected by Limits
+}
+
+func (e *ParseError) Error() string {
+	if e.Charset == "" {
+		return fmt.Sprintf("uriuniq: invalid char %q at index %d", e.Char, e.Index)
+	}
+	return fmt.Sprintf("uriuniq: invalid char %q at index %d, expected one of %q", e.Char, e.Index, string(e.Charset))
+}
+
+// ErrorCode returns CodeInvalidChar.
+func (e *ParseError) ErrorCode() Code { return CodeInvalidChar }
+
+// checkCharset returns a *ParseError for the first char of s not in charset.
+func checkCharset(s string, charset Charset) error {
+	for i, c := range s {
+		if !strings.ContainsRune(string(charset), c) {
+			return &ParseError{Index: i, Char: c, Charset: charset}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package uriuniq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestCheckCharset verifies the position and char reported by ParseError.
+func TestCheckCharset(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		index     int
+		char      rune
+	}{
+		{"Valid", "abc123", false, 0, 0},
+		{"Empty", "", false, 0, 0},
+		{"Invalid First", "#abc", true, 0, '#'},
+		{"Invalid Middle", "ab<c", true, 2, '<'},
+		{"Multi-byte", "abé", true, 2, 'é'},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkCharset(tc.input, Alphanumeric)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("%s: expected error %v, got %v", tc.name, tc.wantError, err)
+			}
+			if err == nil {
+				return
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("%s: expected *ParseError, got %T", tc.name, err)
+			}
+			if perr.Index != tc.index || perr.Char != tc.char || perr.Charset != Alphanumeric {
+				t.Errorf("%s: got index %d char %q charset %q", tc.name, perr.Index, perr.Char, perr.Charset)
+			}
+		})
+	}
+}
+
+// TestCodeOf checks the codes of errors, wrapped or not.
+func TestCodeOf(t *testing.T) {
+	opts := NewOpts()
+	opts.CustomCharset = "a"
+	_, charsetErr := Generate(opts)
+	_, parseErr := ParseAny("ab/")
+	tests := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{"Nil", nil, ""},
+		{"Foreign", errors.New("other"), CodeUnknown},
+		{"Charset Size", charsetErr, CodeCharsetSize},
+		{"Parse Error", parseErr, CodeInvalidChar},
+		{"Sentinel", ErrEmptyInput, CodeEmptyInput},
+		{"Wrapped Sentinel", fmt.Errorf("lookup: %w", ErrInputTooLong), CodeInputTooLong},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CodeOf(tc.err); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+
+	seen := make(map[Code]bool)
+	for _, info := range Catalog() {
+		if seen[info.Code] || info.Description == "" || !strings.HasPrefix(string(info.Code), "E_") {
+			t.Errorf("Bad catalog entry %+v", info)
+		}
+		seen[info.Code] = true
+	}
+	if !seen[CodeCharsetSize] || !seen[CodeTokenExpired] {
+		t.Errorf("Catalog is missing codes")
+	}
+}
@@ -0,0 +1,119 @@
+package uriuniq
+
+import "fmt"
+
+// EscapeMode selects how Generate and Generator.Next emit chars of the
+// charset that are not URI unreserved chars, such as the sub-delims
+// "!*'()". Encoders disagree on whether to escape those, so an ID stored
+// raw may come back percent-encoded.
+type EscapeMode int
+
+const (
+	EscapeNone    EscapeMode = iota // Emit every char raw
+	EscapePercent                   // Percent-encode chars that are not unreserved
+	EscapeReject                    // Reject charsets with chars that are not unreserved
+)
+
+func (m EscapeMode) String() string {
+	switch m {
+	case EscapeNone:
+		return "EscapeNone"
+	case EscapePercent:
+		return "EscapePercent"
+	case EscapeReject:
+		return "EscapeReject"
+	}
+	return fmt.Sprintf("EscapeMode(%d)", int(m))
+}
+
+// unreserved lists the URI unreserved chars of RFC 3986, which no encoder
+// escapes.
+const unreserved Charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-._~"
+
+// Escaped holds the raw and percent-encoded forms of an ID.
+type Escaped struct {
+	Raw     string
+	Encoded string // Canonical form, with uppercase hex digits
+}
+
+// Escape returns both forms of id. Every byte that is not an unreserved
+// char is percent-encoded, so the encoded form is the same whichever
+// encoder produced it.
+func Escape(id string) Escaped {
+	return Escaped{Raw: id, Encoded: string(percentEncode([]byte(id)))}
+}
+
+// percentEncode returns b with every byte that is not an unreserved char
+// percent-encoded. It returns b itself if nothing needs encoding.
+func percentEncode(b []byte) []byte {
+	if percentEncodedLength(string(b)) == len(b) {
+		return b
+	}
+	const hex = "0123456789ABCDEF"
+	out := make([]byte, 0, percentEncodedLength(string(b)))
+	for _, c := range b {
+		if IndexOf(unreserved, c) >= 0 {
+			out = append(out, c)
+		} else {
+			out = append(out, '%', hex[c>>4], hex[c&15])
+		}
+	}
+	return out
+}
+
+// checkEscape rejects a charset needing escapes under EscapeReject.
+func checkEscape(opts Options, charset []byte) error {
+	if opts.Escape != EscapeReject {
+		return nil
+	}
+	if err := checkCharset(string(charset), unreserved); err != nil {
+		return fmt.Errorf("uriuniq: charset needs escaping: %w", err)
+	}
+	return nil
+}
+
+// escapeOutput groups output, applies the EscapeMode of opts to it and
+// adds the prefix of opts. The buffer is sized up front, so no partial
+// copies are left behind by growing it, and zeroed with Sensitive, leaving
+// the string the only copy.
+func escapeOutput(opts Options, output []byte) string {
+	buf := outputBytes(opts, output)
+	s := string(buf)
+	if opts.Sensitive {
+		wipe(buf)
+	}
+	return s
+}
+
+// outputBytes is escapeOutput returning a new byte slice, for callers
+// that wipe the ID later.
+func outputBytes(opts Options, output []byte) []byte {
+	prefix := idPrefix(opts)
+	size := groupedLength(opts, len(output))
+	if opts.Escape == EscapePercent {
+		size *= 3
+	}
+	return appendOutput(append(make([]byte, 0, len(prefix)+size), prefix...), opts, output)
+}
+
+// appendOutput appends output to dst, grouped and escaped as opts say.
+func appendOutput(dst []byte, opts Options, output []byte) []byte {
+	if opts.GroupSize > 0 {
+		if opts.Escape != EscapePercent {
+			return appendGrouped(dst, opts, output)
+		}
+		output = appendGrouped(nil, opts, output)
+		if opts.Sensitive {
+			defer wipe(output)
+		}
+	}
+	if opts.Escape == EscapePercent {
+		encoded := percentEncode(output)
+		dst = append(dst, encoded...)
+		if opts.Sensitive && len(encoded) != len(output) {
+			wipe(encoded) // A copy, not output itself
+		}
+		return dst
+	}
+	return append(dst, output...)
+}
@@ -0,0 +1,85 @@
+package uriuniq
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestEscape checks both forms of IDs with reserved chars.
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		id      string
+		encoded string
+	}{
+		{"abc-._~", "abc-._~"},
+		{"a!b*c'(d)", "a%21b%2Ac%27%28d%29"},
+	}
+	for _, tc := range tests {
+		got := Escape(tc.id)
+		if got.Raw != tc.id || got.Encoded != tc.encoded {
+			t.Errorf("Escape(%q): expected %q, got %+v", tc.id, tc.encoded, got)
+		}
+		if raw, _ := url.PathUnescape(got.Encoded); raw != tc.id {
+			t.Errorf("Escape(%q): encoded form decodes to %q", tc.id, raw)
+		}
+	}
+}
+
+// TestEscapeModes checks the Escape option of Generate and Generator.
+func TestEscapeModes(t *testing.T) {
+	opts := Options{Length: 64, CustomCharset: "ab!*"}
+	raw, err := Generate(opts)
+	if err != nil || len(raw) != 64 {
+		t.Fatalf("Unexpected raw output %q, %v", raw, err)
+	}
+
+	opts.Escape = EscapePercent
+	encoded, err := Generate(opts)
+	if err != nil || strings.ContainsAny(encoded, "!*") || len(encoded) <= 64 {
+		t.Errorf("Unexpected encoded output %q, %v", encoded, err)
+	}
+	g, _ := NewGenerator(opts)
+	next, _ := g.Next()
+	if decoded, err := url.PathUnescape(next); err != nil || len(decoded) != 64 || checkCharset(decoded, "ab!*") != nil {
+		t.Errorf("Unexpected Generator output %q, %v", next, err)
+	}
+
+	opts.Escape = EscapeReject
+	if _, err := Generate(opts); CodeOf(err) != CodeInvalidChar {
+		t.Errorf("Expected charset to be rejected, got %v", err)
+	}
+	if _, err := Generate(Options{Length: 8, Escape: EscapeReject}); err != nil {
+		t.Errorf("Expected default charset to pass, got %v", err)
+	}
+	if EscapePercent.String() != "EscapePercent" {
+		t.Errorf("Unexpected name %s", EscapePercent)
+	}
+}
+
+// TestEscapeOutputCopies checks that escapeOutput sizes its buffer once
+// for every combination of grouping and escaping.
+func TestEscapeOutputCopies(t *testing.T) {
+	output := []byte("ab/cdef/")
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"Prefix", Options{Prefix: "tok", Sensitive: true}, "tok_ab/cdef/"},
+		{"Grouped", Options{Prefix: "tok", GroupSize: 3, Sensitive: true}, "tok_ab/-cde-f/"},
+		{"Escaped", Options{Prefix: "tok", GroupSize: 3, Escape: EscapePercent, Sensitive: true}, "tok_ab%2F-cde-f%2F"},
+	}
+	for _, tc := range tests {
+		if got := escapeOutput(tc.opts, output); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+	// At most one buffer and the string.
+	if allocs := testing.AllocsPerRun(100, func() { escapeOutput(tests[1].opts, output) }); allocs > 2 {
+		t.Errorf("Expected at most 2 allocations, got %.1f", allocs)
+	}
+	if string(output) != "ab/cdef/" {
+		t.Errorf("Output changed to %q", output)
+	}
+}
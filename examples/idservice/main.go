@@ -0,0 +1,131 @@
+// Command idservice is a reference ID service built from the pieces of
+// uriuniq: a Pool keeps serving from its reserve while the entropy source
+// fails, a UniquenessChecker rejects collisions with backoff on retry,
+// idempotency.Middleware makes retried POSTs return the same ID, request
+// IDs tag every response, and expvar counters expose what happened and
+// how much entropy was consumed.
+//
+// Usage:
+//
+//	idservice -addr :8080 -length 12
+//	curl -X POST localhost:8080/ids
+//	curl localhost:8080/debug/vars
+//
+// Drive it with the loadgen package to check that it never returns an ID
+// twice.
+package main
+
+import (
+	"context"
+	"expvar"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/laofun/uriuniq"
+	"github.com/laofun/uriuniq/idempotency"
+	"github.com/laofun/uriuniq/uriuniqhttp"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	length := flag.Int("length", 12, "ID length")
+	reserve := flag.Int("reserve", 1000, "IDs kept in reserve for entropy outages")
+	flag.Parse()
+
+	s, err := newServer(uriuniq.Options{Length: *length}, *reserve)
+	if err != nil {
+		log.Fatal(err)
+	}
+	expvar.Publish("idservice", s.metrics)
+	expvar.Publish("idservice_entropy", s.entropy)
+	mux := http.NewServeMux()
+	mux.Handle("/", s.handler())
+	mux.Handle("/debug/vars", expvar.Handler())
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+	log.Printf("listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	// The reserve is dropped unserved, which keeps IDs unique.
+	s.pool.Close()
+}
+
+// server issues IDs.
+type server struct {
+	pool    *uriuniq.Pool
+	checker uriuniq.UniquenessChecker
+	retry   uriuniq.UniqueRetry
+	metrics *expvar.Map
+	entropy *uriuniq.Counters
+}
+
+// newServer creates a server for IDs generated with opts.
+func newServer(opts uriuniq.Options, reserve int) (*server, error) {
+	entropy := &uriuniq.Counters{}
+	opts.Metrics = entropy
+	pool, err := uriuniq.NewPool(opts, reserve)
+	if err != nil {
+		return nil, err
+	}
+	s := &server{pool: pool, checker: uriuniq.NewMemoryChecker(), metrics: new(expvar.Map).Init(), entropy: entropy}
+	pool.MaxDegraded = time.Minute
+	pool.OnDegraded = func(err error) { s.metrics.Add("degraded", 1) }
+	s.retry = uriuniq.UniqueRetry{
+		Backoff:    time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+		OnEvent: func(ev uriuniq.UniqueEvent) {
+			s.metrics.Add("attempts", 1)
+			s.metrics.Add(ev.Outcome.String(), 1)
+		},
+	}
+	return s, nil
+}
+
+// handler returns the HTTP handler serving POST /ids.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ids", s.issue)
+	idem := &idempotency.Middleware{Store: idempotency.NewMemoryStore()}
+	rid := &uriuniqhttp.RequestID{TrustInbound: true}
+	return rid.Handler(idem.Handler(mux))
+}
+
+// issue writes a new unique ID.
+func (s *server) issue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	var id string
+	err := uriuniq.Recover(func() error {
+		var err error
+		id, err = s.retry.GenerateFrom(r.Context(), s.pool.Next, s.checker)
+		return err
+	}, func(p *uriuniq.PanicError) {
+		log.Printf("panic issuing ID: %v\n%s", p.Value, p.Stack)
+	})
+	if err != nil {
+		s.metrics.Add("failures", 1)
+		rid, _ := uriuniqhttp.FromContext(r.Context())
+		log.Printf("request %s: %s (%s)", rid, err, uriuniq.CodeOf(err))
+		http.Error(w, string(uriuniq.CodeOf(err)), http.StatusServiceUnavailable)
+		return
+	}
+	s.metrics.Add("issued", 1)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, id)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/laofun/uriuniq"
+	"github.com/laofun/uriuniq/idempotency"
+	"github.com/laofun/uriuniq/loadgen"
+	"github.com/laofun/uriuniq/requestid"
+)
+
+// TestService drives the service with loadgen and checks that it never
+// returns an ID twice.
+func TestService(t *testing.T) {
+	s, err := newServer(uriuniq.Options{Length: 12}, 100)
+	if err != nil {
+		t.Fatalf("newServer failed: %s", err)
+	}
+	srv := httptest.NewServer(s.handler())
+	defer srv.Close()
+
+	report, err := loadgen.Run(context.Background(), loadgen.Config{URL: srv.URL + "/ids", QPS: 2000, Requests: 200})
+	if err != nil {
+		t.Fatalf("Run failed: %s, %s", err, report)
+	}
+	if report.Errors != 0 {
+		t.Errorf("Unexpected report %s", report)
+	}
+	if got := s.metrics.Get("issued").String(); got != "200" {
+		t.Errorf("Expected 200 issued, got %s", got)
+	}
+}
+
+// TestServiceIdempotent checks that a retried request gets the same ID
+// and that responses carry a request ID.
+func TestServiceIdempotent(t *testing.T) {
+	s, err := newServer(uriuniq.Options{Length: 12}, 10)
+	if err != nil {
+		t.Fatalf("newServer failed: %s", err)
+	}
+	h := s.handler()
+	var ids []string
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/ids", nil)
+		req.Header.Set(idempotency.Header, "retry-1")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Header().Get(requestid.DefaultHeader) == "" {
+			t.Fatalf("Unexpected response %d, %v", rec.Code, rec.Header())
+		}
+		ids = append(ids, strings.TrimSpace(rec.Body.String()))
+	}
+	if ids[0] != ids[1] {
+		t.Errorf("Expected the same ID, got %v", ids)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ids", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", rec.Code)
+	}
+}
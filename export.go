@@ -0,0 +1,118 @@
+package uriuniq
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// DefaultExportChunk is the number of records Export writes between
+// flushes.
+const DefaultExportChunk = 10000
+
+// ExportRecord is one record written by Export.
+type ExportRecord struct {
+	ID         string
+	CreatedAt  time.Time
+	Format     string
+	CheckValid bool // Result of ExportOptions.Check, true without one
+}
+
+// RecordWriter receives the records of Export. WriteRecord may buffer;
+// Flush is called after every chunk and once at the end. CSVWriter writes
+// CSV; a Parquet file is written by implementing RecordWriter over a
+// Parquet library, which this module does not depend on.
+type RecordWriter interface {
+	WriteRecord(ExportRecord) error
+	Flush() error
+}
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	Format    string                 // Recorded in every record
+	Next      func() (string, error) // Creates the IDs, defaults to a Generator
+	Check     func(id string) error  // Verifies check chars, if any
+	ChunkSize int                    // Defaults to DefaultExportChunk
+	Now       func() time.Time       // Defaults to time.Now
+}
+
+// Export writes n new strings created with opts, or with eopts.Next if
+// set, to w. Records are flushed every eopts.ChunkSize records, so very
+// large batches are never held in memory. On error, records of earlier
+// chunks have already been flushed.
+func Export(w RecordWriter, n int, opts Options, eopts ExportOptions) error {
+	if n < 0 {
+		return newError(CodeInvalidArgument, "uriuniq: negative count")
+	}
+	next := eopts.Next
+	if next == nil {
+		g, err := NewGenerator(opts)
+		if err != nil {
+			return err
+		}
+		next = g.Next
+	}
+	chunk := eopts.ChunkSize
+	if chunk <= 0 {
+		chunk = DefaultExportChunk
+	}
+	now := eopts.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	for i := 0; i < n; i++ {
+		id, err := next()
+		if err != nil {
+			return err
+		}
+		rec := ExportRecord{ID: id, CreatedAt: now(), Format: eopts.Format, CheckValid: true}
+		if eopts.Check != nil {
+			rec.CheckValid = eopts.Check(id) == nil
+		}
+		if err := w.WriteRecord(rec); err != nil {
+			return err
+		}
+		if (i+1)%chunk == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// CSVWriter is a RecordWriter writing CSV with the header row
+// id,created_at,format,check_valid. Times are in RFC 3339 format, UTC.
+type CSVWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVWriter creates a CSVWriter writing to w.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// WriteRecord implements RecordWriter.
+func (c *CSVWriter) WriteRecord(rec ExportRecord) error {
+	if !c.header {
+		if err := c.w.Write([]string{"id", "created_at", "format", "check_valid"}); err != nil {
+			return err
+		}
+		c.header = true
+	}
+	return c.w.Write([]string{
+		rec.ID,
+		rec.CreatedAt.UTC().Format(time.RFC3339Nano),
+		rec.Format,
+		strconv.FormatBool(rec.CheckValid),
+	})
+}
+
+// Flush implements RecordWriter.
+func (c *CSVWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
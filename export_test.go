@@ -0,0 +1,83 @@
+package uriuniq
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flushCounter is a RecordWriter that counts flushes.
+type flushCounter struct {
+	records []ExportRecord
+	flushes int
+}
+
+func (f *flushCounter) WriteRecord(rec ExportRecord) error {
+	f.records = append(f.records, rec)
+	return nil
+}
+
+func (f *flushCounter) Flush() error {
+	f.flushes++
+	return nil
+}
+
+// TestExport checks chunked flushing and record metadata.
+func TestExport(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	w := &flushCounter{}
+	err := Export(w, 25, NewOpts(), ExportOptions{Format: "default", ChunkSize: 10, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("Export failed: %s", err)
+	}
+	if len(w.records) != 25 || w.flushes != 3 {
+		t.Errorf("Expected 25 records in 3 flushes, got %d in %d", len(w.records), w.flushes)
+	}
+	rec := w.records[0]
+	if len(rec.ID) != DefaultLength || !rec.CreatedAt.Equal(now) || rec.Format != "default" || !rec.CheckValid {
+		t.Errorf("Unexpected record %+v", rec)
+	}
+
+	codes := &ShortCodes{Length: 8}
+	w = &flushCounter{}
+	calls := 0
+	err = Export(w, 2, Options{}, ExportOptions{
+		Next: func() (string, error) {
+			calls++
+			if calls == 2 {
+				return "AAAAAAAAA", nil // Wrong check char
+			}
+			return codes.Issue("")
+		},
+		Check: func(id string) error { _, err := codes.Check("", id); return err },
+	})
+	if err != nil || !w.records[0].CheckValid || w.records[1].CheckValid {
+		t.Errorf("Unexpected check results %+v, %v", w.records, err)
+	}
+
+	fail := errors.New("source down")
+	if err := Export(&flushCounter{}, 1, Options{}, ExportOptions{Next: func() (string, error) { return "", fail }}); !errors.Is(err, fail) {
+		t.Errorf("Expected source error, got %v", err)
+	}
+	if err := Export(&flushCounter{}, -1, NewOpts(), ExportOptions{}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected invalid argument, got %v", err)
+	}
+}
+
+// TestCSVWriter checks the CSV layout.
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := Export(NewCSVWriter(&buf), 2, NewOpts(), ExportOptions{Format: "f", Now: func() time.Time { return now }}); err != nil {
+		t.Fatalf("Export failed: %s", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %v, %v", rows, err)
+	}
+	if rows[0][0] != "id" || rows[1][1] != "2024-01-02T03:04:05Z" || rows[1][2] != "f" || rows[1][3] != "true" {
+		t.Errorf("Unexpected rows %v", rows)
+	}
+}
@@ -0,0 +1,98 @@
+package uriuniq
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FileCounterStore is a CounterStore keeping one file per counter name in
+// Dir, guarded by an advisory file lock, so processes on one host, such as
+// cron jobs or CGI-style workers, share counters without external
+// infrastructure. Reserve hands out disjoint ranges of a counter for
+// sequence numbers. Locking is supported on Unix only; elsewhere every
+// method returns an error.
+type FileCounterStore struct {
+	Dir string
+}
+
+// LoadCounter implements CounterStore.
+func (s *FileCounterStore) LoadCounter(name string) (uint64, error) {
+	var n uint64
+	err := s.locked(name, func(f *os.File) (err error) {
+		n, err = readCounter(f)
+		return err
+	})
+	return n, err
+}
+
+// SaveCounter implements CounterStore.
+func (s *FileCounterStore) SaveCounter(name string, n uint64) error {
+	return s.locked(name, func(f *os.File) error {
+		return writeCounter(f, n)
+	})
+}
+
+// Reserve atomically adds n to the counter name and returns the first
+// value of the reserved range [first, first+n). Two processes never get
+// overlapping ranges.
+func (s *FileCounterStore) Reserve(name string, n uint64) (first uint64, err error) {
+	err = s.locked(name, func(f *os.File) error {
+		if first, err = readCounter(f); err != nil {
+			return err
+		}
+		if first+n < first {
+			return newError(CodeInvalidArgument, "uriuniq: counter overflow")
+		}
+		return writeCounter(f, first+n)
+	})
+	return first, err
+}
+
+// locked runs fn on the counter file of name while holding its lock.
+func (s *FileCounterStore) locked(name string, fn func(*os.File) error) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return errorf(CodeInvalidArgument, "uriuniq: invalid counter name %q", name)
+	}
+	f, err := os.OpenFile(filepath.Join(s.Dir, name+".counter"), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return err
+	}
+	defer unlockFile(f)
+	return fn(f)
+}
+
+// readCounter reads the decimal counter in f; an empty file holds 0.
+func readCounter(f *os.File) (uint64, error) {
+	b, err := io.ReadAll(io.NewSectionReader(f, 0, 32))
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(b))
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("uriuniq: corrupt counter file %s: %w", f.Name(), err)
+	}
+	return n, nil
+}
+
+// writeCounter replaces the counter in f with n.
+func writeCounter(f *os.File, n uint64) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt([]byte(strconv.FormatUint(n, 10)+"\n"), 0); err != nil {
+		return err
+	}
+	return f.Sync()
+}
@@ -0,0 +1,59 @@
+//go:build unix
+
+package uriuniq
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestFileCounterStore checks persistence and disjoint reservations.
+func TestFileCounterStore(t *testing.T) {
+	dir := t.TempDir()
+	store := &FileCounterStore{Dir: dir}
+	if n, err := store.LoadCounter("codes"); err != nil || n != 0 {
+		t.Fatalf("Expected empty counter, got %d, %v", n, err)
+	}
+	if err := store.SaveCounter("codes", 41); err != nil {
+		t.Fatalf("SaveCounter failed: %s", err)
+	}
+	if n, _ := (&FileCounterStore{Dir: dir}).LoadCounter("codes"); n != 41 {
+		t.Errorf("Expected 41, got %d", n)
+	}
+
+	// Each goroutine opens its own file, so they contend on the lock as
+	// separate processes would.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[uint64]bool)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := &FileCounterStore{Dir: dir}
+			for j := 0; j < 25; j++ {
+				first, err := s.Reserve("seq", 10)
+				if err != nil {
+					t.Errorf("Reserve failed: %s", err)
+					return
+				}
+				mu.Lock()
+				if seen[first] {
+					t.Errorf("Range at %d reserved twice", first)
+				}
+				seen[first] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if n, _ := store.LoadCounter("seq"); n != 8*25*10 {
+		t.Errorf("Expected counter %d, got %d", 8*25*10, n)
+	}
+
+	for _, name := range []string{"", "../x", ".."} {
+		if _, err := store.LoadCounter(name); CodeOf(err) != CodeInvalidArgument {
+			t.Errorf("Expected invalid name error for %q, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package uriuniq
+
+import "os"
+
+// errNoFileLock is returned where file locking is not supported.
+var errNoFileLock = newError(CodeInternal, "uriuniq: file locking not supported on this platform")
+
+func lockFile(*os.File) error   { return errNoFileLock }
+func unlockFile(*os.File) error { return errNoFileLock }
@@ -0,0 +1,131 @@
+package uriuniq
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Fill walks the struct pointed to by v and sets every empty string field
+// tagged with `uriuniq` to a fresh ID. Nested structs, pointers to structs
+// and slices of either are walked too. Fields that already hold a value are
+// left alone. The tag is a comma-separated list of:
+//
+//	len=N       length of the random part, defaults to DefaultLength
+//	preset=NAME a charset registered with RegisterCharset, such as base62
+//	prefix=P    prepended to the ID, followed by "_"
+//
+// For example:
+//
+//	type Order struct {
+//	    ID string `uriuniq:"len=20,preset=base62,prefix=ord"`
+//	}
+func Fill(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return newError(CodeInvalidArgument, "uriuniq: Fill needs a non-nil pointer")
+	}
+	return fillValue(rv.Elem())
+}
+
+// fillValue fills v, which may be a struct, pointer or slice.
+func fillValue(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return fillValue(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := fillValue(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			tag, ok := field.Tag.Lookup("uriuniq")
+			if !ok {
+				if err := fillValue(v.Field(i)); err != nil {
+					return err
+				}
+				continue
+			}
+			if tag == "-" {
+				continue
+			}
+			if err := fillTagged(v.Field(i), tag); err != nil {
+				return fmt.Errorf("uriuniq: field %s: %w", field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// fillTagged fills a tagged string, or each string of a tagged slice.
+func fillTagged(v reflect.Value, tag string) error {
+	switch {
+	case v.Kind() == reflect.String:
+		if v.String() != "" {
+			return nil
+		}
+		opts, prefix, err := parseFillTag(tag)
+		if err != nil {
+			return err
+		}
+		id, err := generateID(opts)
+		if err != nil {
+			return err
+		}
+		v.SetString(prefix + id)
+		return nil
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if err := fillTagged(v.Index(i), tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errorf(CodeInvalidArgument, "tag on non-string type %s", v.Type())
+	}
+}
+
+// parseFillTag parses a `uriuniq` struct tag, see Fill.
+func parseFillTag(tag string) (Options, string, error) {
+	opts := NewOpts()
+	var prefix string
+	for _, item := range strings.Split(tag, ",") {
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return opts, "", errorf(CodeInvalidArgument, "invalid tag item %q", item)
+		}
+		switch key {
+		case "len":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return opts, "", errorf(CodeInvalidArgument, "invalid len %q", value)
+			}
+			opts.Length = n
+		case "preset":
+			charset, ok := LookupCharset(value)
+			if !ok {
+				return opts, "", errorf(CodeInvalidArgument, "unknown preset %q", value)
+			}
+			opts.CustomCharset = charset
+		case "prefix":
+			prefix = value + "_"
+		default:
+			return opts, "", errorf(CodeInvalidArgument, "unknown tag key %q", key)
+		}
+	}
+	return opts, prefix, nil
+}
@@ -0,0 +1,85 @@
+package uriuniq
+
+import (
+	"strings"
+	"testing"
+)
+
+type fillItem struct {
+	SKU string `uriuniq:"len=8,preset=numeric"`
+}
+
+type fillOrder struct {
+	ID       string `uriuniq:"len=20,preset=base62,prefix=ord"`
+	Kept     string `uriuniq:"len=8"`
+	Skipped  string `uriuniq:"-"`
+	Plain    string
+	Tags     []string `uriuniq:"len=4,preset=lowercase"`
+	Items    []fillItem
+	Parent   *fillItem
+	Missing  *fillItem
+	internal string `uriuniq:"len=8"`
+}
+
+// TestFill verifies that tagged fields are populated recursively.
+func TestFill(t *testing.T) {
+	order := fillOrder{
+		Kept:   "keep",
+		Tags:   make([]string, 2),
+		Items:  make([]fillItem, 2),
+		Parent: &fillItem{},
+	}
+	if err := Fill(&order); err != nil {
+		t.Fatalf("Fill failed: %s", err)
+	}
+
+	if !strings.HasPrefix(order.ID, "ord_") || len(order.ID) != 24 || checkCharset(order.ID[4:], Alphanumeric) != nil {
+		t.Errorf("Unexpected ID %q", order.ID)
+	}
+	if order.Kept != "keep" || order.Skipped != "" || order.Plain != "" || order.internal != "" || order.Missing != nil {
+		t.Errorf("Untagged or set fields changed: %+v", order)
+	}
+	for _, tag := range order.Tags {
+		if len(tag) != 4 || checkCharset(tag, Lowercase) != nil {
+			t.Errorf("Unexpected tag %q", tag)
+		}
+	}
+	for _, item := range append(order.Items, *order.Parent) {
+		if len(item.SKU) != 8 || checkCharset(item.SKU, Numeric) != nil {
+			t.Errorf("Unexpected SKU %q", item.SKU)
+		}
+	}
+}
+
+// TestFillErrors checks invalid targets and tags.
+func TestFillErrors(t *testing.T) {
+	var order fillOrder
+	if err := Fill(order); err == nil {
+		t.Errorf("Expected error for non-pointer")
+	}
+
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"Bad Len", &struct {
+			ID string `uriuniq:"len=x"`
+		}{}},
+		{"Bad Preset", &struct {
+			ID string `uriuniq:"preset=klingon"`
+		}{}},
+		{"Bad Key", &struct {
+			ID string `uriuniq:"size=3"`
+		}{}},
+		{"Non-string", &struct {
+			ID int `uriuniq:"len=3"`
+		}{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := Fill(tc.v); err == nil {
+				t.Errorf("%s: expected error", tc.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package uriuniq
+
+import (
+	"crypto/sha256"
+)
+
+// InstanceFingerprint returns the fingerprint chars of label in strings
+// generated with opts, see Options.InstanceLabel.
+func InstanceFingerprint(label string, opts Options) (string, error) {
+	opts.InstanceLabel = label
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return "", err
+	}
+	return string(fingerprint(label, charset, opts.FingerprintChars)), nil
+}
+
+// ExtractFingerprint returns the fingerprint chars of an id generated with
+// opts, which must set InstanceLabel. Compare the result with the
+// InstanceFingerprint of each instance to find the one that minted id.
+func ExtractFingerprint(id string, opts Options) (string, error) {
+	if opts.InstanceLabel == "" {
+		return "", newError(CodeInvalidArgument, "uriuniq: no instance label")
+	}
+	opts, _, err := prepare(opts)
+	if err != nil {
+		return "", err
+	}
+	if len(id) < opts.FingerprintChars {
+		return "", newError(CodeInvalidLength, "uriuniq: ID too short for fingerprint")
+	}
+	return id[:opts.FingerprintChars], nil
+}
+
+// fingerprintChars returns the number of fingerprint chars of prepared opts.
+func fingerprintChars(opts Options) int {
+	if opts.InstanceLabel == "" {
+		return 0
+	}
+	return opts.FingerprintChars
+}
+
+// stampFingerprint overwrites the first chars of output with the
+// fingerprint of the instance label of prepared opts, if any.
+func stampFingerprint(opts Options, charset, output []byte) {
+	if n := fingerprintChars(opts); n > 0 {
+		copy(output, fingerprint(opts.InstanceLabel, charset, n))
+	}
+}
+
+// fingerprint derives n chars from charset for label. The hash is reduced
+// with a plain modulo: the bias does not matter for tracing.
+func fingerprint(label string, charset []byte, n int) []byte {
+	sum := sha256.Sum256([]byte(label))
+	out := make([]byte, n)
+	for i := range out {
+		v := int(sum[2*i])<<8 | int(sum[2*i+1])
+		out[i] = charset[v%len(charset)]
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package uriuniq
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFingerprint checks that IDs are traced to the instance minting them.
+func TestFingerprint(t *testing.T) {
+	for _, chars := range []int{1, 2} {
+		opts := NewOpts()
+		opts.InstanceLabel = "pod-7"
+		opts.FingerprintChars = chars
+		fp, err := InstanceFingerprint("pod-7", opts)
+		if err != nil || len(fp) != chars {
+			t.Fatalf("InstanceFingerprint = %q, %v", fp, err)
+		}
+
+		g, err := NewGenerator(opts)
+		if err != nil {
+			t.Fatalf("NewGenerator failed: %s", err)
+		}
+		for _, gen := range []func() (string, error){func() (string, error) { return Generate(opts) }, g.Next} {
+			id, err := gen()
+			if err != nil {
+				t.Fatalf("Generate failed: %s", err)
+			}
+			if len(id) != DefaultLength || !strings.HasPrefix(id, fp) {
+				t.Errorf("ID %q does not start with fingerprint %q", id, fp)
+			}
+			if got, err := ExtractFingerprint(id, opts); err != nil || got != fp {
+				t.Errorf("ExtractFingerprint = %q, %v, expected %q", got, err, fp)
+			}
+		}
+
+		plain := NewOpts()
+		withFP := opts
+		if got, want := Entropy(withFP), float64(DefaultLength-chars)*AlphanumericBits; got < want-1e-9 || got > want+1e-9 {
+			t.Errorf("Expected %f bits, got %f", want, got)
+		}
+		if Entropy(plain) <= Entropy(withFP) {
+			t.Errorf("Fingerprint should cost entropy")
+		}
+	}
+
+	opts := NewOpts()
+	opts.InstanceLabel = "pod-7"
+	opts.FingerprintChars = 3
+	if _, err := Generate(opts); err == nil {
+		t.Errorf("Expected error for 3 fingerprint chars")
+	}
+	if _, err := ExtractFingerprint("abc", NewOpts()); err == nil {
+		t.Errorf("Expected error without instance label")
+	}
+}
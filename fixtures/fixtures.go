@@ -0,0 +1,68 @@
+// Package fixtures provides ID helpers for tests: readable sequences,
+// reproducible streams, and random IDs unique across a test run.
+package fixtures
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/laofun/uriuniq"
+)
+
+// Sequence returns a function that yields prefix_000001, prefix_000002 and
+// so on. It is safe for concurrent use.
+func Sequence(prefix string) func() string {
+	var n atomic.Uint64
+	return func() string {
+		return fmt.Sprintf("%s_%06d", prefix, n.Add(1))
+	}
+}
+
+// Deterministic returns a function that yields the same sequence of random
+// looking IDs for the same seed, using uriuniq.DeriveGenerator. It panics if
+// seed is empty.
+func Deterministic(seed string) func() string {
+	gen, err := uriuniq.DeriveGenerator([]byte(seed), "fixtures", uriuniq.NewOpts())
+	if err != nil {
+		panic(err)
+	}
+	return func() string {
+		id, err := gen.Next()
+		if err != nil {
+			panic(err)
+		}
+		return id
+	}
+}
+
+var (
+	ids, _   = uriuniq.NewGenerator(uriuniq.NewOpts())
+	issuedMu sync.Mutex
+	issued   = make(map[string]bool)
+)
+
+// Unique returns a random ID that no other live test in the process holds.
+// The ID is released when t and its subtests complete.
+func Unique(t testing.TB) string {
+	t.Helper()
+	issuedMu.Lock()
+	defer issuedMu.Unlock()
+	for {
+		id, err := ids.Next()
+		if err != nil {
+			t.Fatalf("fixtures: %s", err)
+		}
+		if issued[id] {
+			continue
+		}
+		issued[id] = true
+		t.Cleanup(func() {
+			issuedMu.Lock()
+			delete(issued, id)
+			issuedMu.Unlock()
+		})
+		return id
+	}
+}
@@ -0,0 +1,63 @@
+package fixtures
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestSequence checks formatting and concurrent uniqueness.
+func TestSequence(t *testing.T) {
+	next := Sequence("ord")
+	if got := next(); got != "ord_000001" {
+		t.Errorf("Expected ord_000001, got %s", got)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := next()
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("Duplicate %s", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestDeterministic verifies the same seed yields the same sequence.
+func TestDeterministic(t *testing.T) {
+	a, b, c := Deterministic("golden"), Deterministic("golden"), Deterministic("other")
+	for i := 0; i < 3; i++ {
+		x, y, z := a(), b(), c()
+		if x != y || x == z {
+			t.Errorf("Step %d: got %s %s %s", i, x, y, z)
+		}
+	}
+}
+
+// TestUnique checks registration and release of unique IDs.
+func TestUnique(t *testing.T) {
+	var id string
+	t.Run("Sub", func(t *testing.T) {
+		id = Unique(t)
+		issuedMu.Lock()
+		defer issuedMu.Unlock()
+		if !issued[id] {
+			t.Errorf("Expected %s to be registered", id)
+		}
+	})
+	issuedMu.Lock()
+	defer issuedMu.Unlock()
+	if issued[id] {
+		t.Errorf("Expected %s to be released", id)
+	}
+}
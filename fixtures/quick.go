@@ -0,0 +1,74 @@
+package fixtures
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"reflect"
+
+	"github.com/laofun/uriuniq"
+)
+
+// ID is a string generated with uriuniq.NewOpts. It implements
+// testing/quick.Generator, so property functions can take IDs directly:
+//
+//	quick.Check(func(id fixtures.ID) bool { ... }, nil)
+type ID string
+
+// Generate implements testing/quick.Generator.
+func (ID) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(ID(Draw(uriuniq.NewOpts(), r)))
+}
+
+// Values returns a testing/quick Config.Values function that sets every
+// argument to an ID generated with opts. All arguments must be strings.
+func Values(opts uriuniq.Options) func([]reflect.Value, *rand.Rand) {
+	return func(values []reflect.Value, r *rand.Rand) {
+		for i := range values {
+			values[i] = reflect.ValueOf(Draw(opts, r))
+		}
+	}
+}
+
+// Draw generates an ID with opts, drawing its randomness from r, so the
+// same seed always yields the same ID. It panics if opts are invalid.
+func Draw(opts uriuniq.Options, r *rand.Rand) string {
+	return Drawer(opts)(r.Uint64)
+}
+
+// Drawer returns a function that generates IDs with opts, drawing
+// randomness from next. It adapts to property-based testing libraries that
+// only hand out numbers, e.g. with pgregory.net/rapid:
+//
+//	draw := fixtures.Drawer(opts)
+//	ids := rapid.Custom(func(t *rapid.T) string {
+//	    return draw(func() uint64 { return rapid.Uint64().Draw(t, "bits") })
+//	})
+//
+// The returned function panics if opts are invalid.
+func Drawer(opts uriuniq.Options) func(next func() uint64) string {
+	return func(next func() uint64) string {
+		opts.EntropySource = uint64Reader(next)
+		opts.EntropyBufferSize = -1
+		g, err := uriuniq.NewGenerator(opts)
+		if err != nil {
+			panic(err)
+		}
+		id, err := g.Next()
+		if err != nil {
+			panic(err)
+		}
+		return id
+	}
+}
+
+// uint64Reader reads bytes from a stream of uint64 values.
+type uint64Reader func() uint64
+
+func (next uint64Reader) Read(p []byte) (int, error) {
+	var buf [8]byte
+	for i := 0; i < len(p); i += 8 {
+		binary.LittleEndian.PutUint64(buf[:], next())
+		copy(p[i:], buf[:])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,45 @@
+package fixtures
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestQuickID checks that quick.Check can draw valid IDs.
+func TestQuickID(t *testing.T) {
+	valid := func(id ID) bool {
+		_, err := uriuniq.ParseAny(string(id))
+		return len(id) == uriuniq.DefaultLength && err == nil
+	}
+	if err := quick.Check(valid, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestValues verifies Config.Values uses the given options.
+func TestValues(t *testing.T) {
+	opts := uriuniq.NewOpts()
+	opts.Length = 5
+	opts.CustomCharset = "xyz"
+	config := &quick.Config{Values: Values(opts)}
+	valid := func(a, b string) bool {
+		return len(a) == 5 && strings.Trim(a+b, "xyz") == ""
+	}
+	if err := quick.Check(valid, config); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestDraw checks that the same seed yields the same ID.
+func TestDraw(t *testing.T) {
+	opts := uriuniq.NewOpts()
+	a := Draw(opts, rand.New(rand.NewSource(42)))
+	b := Draw(opts, rand.New(rand.NewSource(42)))
+	if a != b {
+		t.Errorf("Expected %s, got %s", a, b)
+	}
+}
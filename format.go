@@ -0,0 +1,52 @@
+package uriuniq
+
+// Format describes the shape of an ID, for schema export and validation.
+type Format struct {
+	Name      string
+	Pattern   string // Anchored RE2 pattern
+	MinLength int
+	MaxLength int
+	Options   *Options // Set for formats made with OptionsFormat
+}
+
+// Well-known ID formats.
+var (
+	// FormatULID is a ULID in canonical uppercase Crockford base32.
+	FormatULID = Format{
+		Name:      "ulid",
+		Pattern:   "^[0-7][0-9A-HJKMNP-TV-Z]{25}$",
+		MinLength: 26,
+		MaxLength: 26,
+	}
+	// FormatUUIDv7 is a UUID version 7 in canonical lowercase hex form.
+	FormatUUIDv7 = Format{
+		Name:      "uuidv7",
+		Pattern:   "^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$",
+		MinLength: 36,
+		MaxLength: 36,
+	}
+	// FormatTypeID is a TypeID: an optional type prefix of up to 63 chars
+	// followed by a lowercase base32 UUIDv7 suffix.
+	FormatTypeID = Format{
+		Name:      "typeid",
+		Pattern:   "^([a-z]([a-z_]{0,61}[a-z])?_)?[0-7][0-9a-hjkmnp-tv-z]{25}$",
+		MinLength: 26,
+		MaxLength: 90,
+	}
+)
+
+// OptionsFormat returns the Format of the strings generated with opts.
+func OptionsFormat(name string, opts Options) (Format, error) {
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return Format{}, err
+	}
+	min, max := idLengthRange(opts)
+	return Format{
+		Name:      name,
+		Pattern:   idPattern(opts, charset),
+		MinLength: min,
+		MaxLength: max,
+		Options:   &opts,
+	}, nil
+}
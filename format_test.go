@@ -0,0 +1,53 @@
+package uriuniq
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestFormats checks the well-known formats against sample IDs.
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		valid  []string
+		inval  []string
+	}{
+		{"ULID", FormatULID,
+			[]string{"01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+			[]string{"81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAI", "01arz3ndektsv4rrffq69g5fav"}},
+		{"UUIDv7", FormatUUIDv7,
+			[]string{"01890a5d-ac96-774b-bcce-b302099a8057"},
+			[]string{"01890a5d-ac96-474b-bcce-b302099a8057", "01890a5dac96774bbcceb302099a8057"}},
+		{"TypeID", FormatTypeID,
+			[]string{"user_2x4y6z8a0b1c2d3e4f5g6h7j8k", "01h455vb4pex5vsknk084sn02q", "my_type_01h455vb4pex5vsknk084sn02q"},
+			[]string{"User_01h455vb4pex5vsknk084sn02q", "_01h455vb4pex5vsknk084sn02q", "user__01h455vb4pex5vsknk084sn02q"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			re := regexp.MustCompile(tc.format.Pattern)
+			for _, id := range tc.valid {
+				if !re.MatchString(id) || len(id) < tc.format.MinLength || len(id) > tc.format.MaxLength {
+					t.Errorf("%s: %q should be valid", tc.name, id)
+				}
+			}
+			for _, id := range tc.inval {
+				if re.MatchString(id) {
+					t.Errorf("%s: %q should be invalid", tc.name, id)
+				}
+			}
+		})
+	}
+}
+
+// TestOptionsFormat checks the Format built from Options.
+func TestOptionsFormat(t *testing.T) {
+	format, err := OptionsFormat("session", NewOpts())
+	if err != nil {
+		t.Fatalf("OptionsFormat failed: %s", err)
+	}
+	if format.Name != "session" || format.Pattern != "^[0-9A-Za-z]{16}$" || format.MinLength != 16 || format.MaxLength != 16 {
+		t.Errorf("Unexpected format %+v", format)
+	}
+}
@@ -0,0 +1,216 @@
+package uriuniq
+
+import (
+	"encoding/binary"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatRevision is one revision of an ID format, tagged with a prefix so
+// DetectFormat can tell its IDs from those of other revisions while a
+// migration routes old and new IDs differently.
+type FormatRevision struct {
+	Tag      string  // Put before every ID as its Prefix, such as "v2"
+	Options  Options // Their Prefix must be empty or Tag
+	Sortable bool    // IDs start with a timestamp, as made by GenerateSortable
+}
+
+// Generate creates an ID of revision r, starting with its tag.
+func (r FormatRevision) Generate() (string, error) {
+	opts, err := r.options()
+	if err != nil {
+		return "", err
+	}
+	if r.Sortable {
+		return GenerateSortable(opts)
+	}
+	return generateID(opts)
+}
+
+// options returns the Options of r with the tag as Prefix.
+func (r FormatRevision) options() (Options, error) {
+	if r.Tag == "" {
+		return Options{}, newError(CodeInvalidArgument, "uriuniq: empty format tag")
+	}
+	if r.Options.Prefix != "" && r.Options.Prefix != r.Tag {
+		return Options{}, errorf(CodeInvalidArgument, "uriuniq: prefix %q differs from format tag %q", r.Options.Prefix, r.Tag)
+	}
+	opts := r.Options
+	opts.Prefix = r.Tag
+	return opts, nil
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]FormatRevision{}
+
+	ulidPattern   = regexp.MustCompile(FormatULID.Pattern)
+	uuidv7Pattern = regexp.MustCompile(FormatUUIDv7.Pattern)
+)
+
+// RegisterFormat makes r known to DetectFormat. Registering a tag again
+// replaces its revision.
+func RegisterFormat(r FormatRevision) error {
+	opts, err := r.options()
+	if err != nil {
+		return err
+	}
+	if r.Sortable {
+		_, err = newSortableLayout(opts)
+	} else {
+		_, _, err = prepare(opts)
+	}
+	if err != nil {
+		return err
+	}
+	r.Options = opts
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[r.Tag] = r
+	return nil
+}
+
+// FormatInfo describes an ID as found by DetectFormat.
+type FormatInfo struct {
+	Tag       string    // Tag of the registered revision, "" for untagged IDs
+	Name      string    // Well-known format of untagged IDs, such as "ulid"
+	Length    int       // Length of the whole ID
+	Charset   string    // Registered name of the charset, "" if none matches
+	Sortable  bool      // The ID starts with its creation time
+	Time      time.Time // Creation time of sortable IDs
+	CheckChar bool      // The ID ends in a check char
+}
+
+// DetectFormat reports the format of id. An ID starting with the tag of a
+// revision registered with RegisterFormat is validated against it and
+// described by its Options. Untagged IDs, such as those issued before
+// tags were introduced, are recognized as ULIDs or UUIDv7s with their
+// time, or else get the smallest registered charset holding all their
+// chars; their check chars cannot be told apart from random ones. It
+// returns ErrNoFormatMatch if no charset holds the chars of id, and
+// ErrInputTooLong if id is longer than DefaultMaxInputLength.
+func DetectFormat(id string) (FormatInfo, error) {
+	if id == "" {
+		return FormatInfo{}, ErrEmptyInput
+	}
+	if err := checkInputLength(id, DefaultMaxInputLength); err != nil {
+		return FormatInfo{}, err
+	}
+	if r, ok := taggedFormat(id); ok {
+		return detectTagged(id, r)
+	}
+
+	info := FormatInfo{Length: len(id)}
+	switch {
+	case ulidPattern.MatchString(id):
+		info.Name, info.Charset, info.Sortable = FormatULID.Name, "base32", true
+		var ms int64
+		for i := 0; i < 10; i++ {
+			ms = ms<<5 | int64(IndexOf(Base32Crockford, id[i]))
+		}
+		info.Time = time.UnixMilli(ms)
+	case uuidv7Pattern.MatchString(id):
+		u, err := ParseUUID(id)
+		if err != nil {
+			return FormatInfo{}, err
+		}
+		var b [8]byte
+		copy(b[2:], u[:6])
+		info.Name, info.Charset, info.Sortable = FormatUUIDv7.Name, "hex", true
+		info.Time = time.UnixMilli(int64(binary.BigEndian.Uint64(b[:])))
+	default:
+		if info.Charset = charsetName([]byte(id), false); info.Charset == "" {
+			return FormatInfo{}, ErrNoFormatMatch
+		}
+	}
+	return info, nil
+}
+
+// taggedFormat returns the registered revision whose tag starts id,
+// preferring the longest tag.
+func taggedFormat(id string) (FormatRevision, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	var found FormatRevision
+	ok := false
+	for _, r := range formats {
+		prefix := idPrefix(r.Options)
+		if strings.HasPrefix(id, prefix) && (!ok || len(prefix) > len(idPrefix(found.Options))) {
+			found, ok = r, true
+		}
+	}
+	return found, ok
+}
+
+// detectTagged describes id, which starts with the tag of r.
+func detectTagged(id string, r FormatRevision) (FormatInfo, error) {
+	if err := Validate(id, r.Options); err != nil {
+		return FormatInfo{}, err
+	}
+	_, charset, err := prepare(r.Options)
+	if err != nil {
+		return FormatInfo{}, err
+	}
+	info := FormatInfo{
+		Tag:       r.Tag,
+		Length:    len(id),
+		Charset:   charsetName(charset, true),
+		Sortable:  r.Sortable,
+		CheckChar: r.Options.CheckChar,
+	}
+	if r.Sortable {
+		if info.Time, err = SortableTime(id, r.Options); err != nil {
+			return FormatInfo{}, err
+		}
+	}
+	return info, nil
+}
+
+// charsetName returns the name of the smallest registered charset holding
+// all of chars, with exact only one of the same size, or "" if none does.
+// Ties go to the first name in sort order.
+func charsetName(chars []byte, exact bool) string {
+	var seen [256]bool
+	distinct := 0
+	for _, c := range chars {
+		if !seen[c] {
+			seen[c] = true
+			distinct++
+		}
+	}
+	charsetsMu.RLock()
+	defer charsetsMu.RUnlock()
+	names := make([]string, 0, len(charsets))
+	for name := range charsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	best := ""
+	for _, name := range names {
+		cs := charsets[name]
+		if exact && len(cs) != distinct || best != "" && len(cs) >= len(charsets[best]) {
+			continue
+		}
+		if holdsAll(cs, &seen) {
+			best = name
+		}
+	}
+	return best
+}
+
+// holdsAll reports whether cs has every char marked in seen.
+func holdsAll(cs Charset, seen *[256]bool) bool {
+	var in [256]bool
+	for i := 0; i < len(cs); i++ {
+		in[cs[i]] = true
+	}
+	for c, ok := range seen {
+		if ok && !in[c] {
+			return false
+		}
+	}
+	return true
+}
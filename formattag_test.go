@@ -0,0 +1,86 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDetectFormat checks that tagged IDs are described by their
+// registered revision and untagged ones by their shape.
+func TestDetectFormat(t *testing.T) {
+	v2 := FormatRevision{Tag: "fmtv2", Options: Options{Length: 24, CheckChar: true}, Sortable: true}
+	if err := RegisterFormat(v2); err != nil {
+		t.Fatalf("RegisterFormat failed: %s", err)
+	}
+	v3 := FormatRevision{Tag: "fmtv3", Options: Options{Length: 32, CustomCharset: Hex}}
+	if err := RegisterFormat(v3); err != nil {
+		t.Fatalf("RegisterFormat failed: %s", err)
+	}
+
+	before := time.Now().Add(-time.Millisecond)
+	id, err := v2.Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	info, err := DetectFormat(id)
+	if err != nil {
+		t.Fatalf("DetectFormat(%q) failed: %s", id, err)
+	}
+	if info.Tag != "fmtv2" || info.Charset != "alphanumeric" || !info.Sortable || !info.CheckChar || info.Length != len(id) {
+		t.Errorf("Unexpected info for %q: %+v", id, info)
+	}
+	if info.Time.Before(before) || info.Time.After(time.Now()) {
+		t.Errorf("Expected the time of generation, got %s", info.Time)
+	}
+	if id, _ := v3.Generate(); mustDetect(t, id).Charset != "hex" {
+		t.Errorf("Expected hex for %q", id)
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		format  string
+		charset string
+		time    int64
+	}{
+		{"Legacy", "k3J9xQ2mPw", "", "base58", 0},
+		{"Digits", "123456", "", "numeric", 0},
+		{"ULID", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "ulid", "base32", 1469922850259},
+		{"UUIDv7", "017f22e2-79b0-7cc3-98c4-dc0c0c07398f", "uuidv7", "hex", 1645557742000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			info := mustDetect(t, tc.id)
+			if info.Tag != "" || info.Name != tc.format || info.Charset != tc.charset || info.Sortable != (tc.time != 0) {
+				t.Errorf("Unexpected info: %+v", info)
+			}
+			if tc.time != 0 && info.Time.UnixMilli() != tc.time {
+				t.Errorf("Expected time %d, got %d", tc.time, info.Time.UnixMilli())
+			}
+		})
+	}
+
+	if _, err := DetectFormat("fmtv2_short"); err == nil {
+		t.Errorf("Expected error for a malformed tagged ID")
+	}
+	if _, err := DetectFormat("a b"); !errors.Is(err, ErrNoFormatMatch) {
+		t.Errorf("Expected ErrNoFormatMatch, got %v", err)
+	}
+	if _, err := DetectFormat(""); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Expected ErrEmptyInput, got %v", err)
+	}
+	if err := RegisterFormat(FormatRevision{Tag: "a", Options: Options{Prefix: "b"}}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+}
+
+// mustDetect returns the FormatInfo of id, failing t on error.
+func mustDetect(t *testing.T, id string) FormatInfo {
+	t.Helper()
+	info, err := DetectFormat(id)
+	if err != nil {
+		t.Fatalf("DetectFormat(%q) failed: %s", id, err)
+	}
+	return info
+}
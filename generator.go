@@ -0,0 +1,267 @@
+package uriuniq
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Generator creates random strings from a fixed Options. The Options are
+// checked and the charset, its rejection threshold and the table mapping
+// entropy bytes to chars are built once, in NewGenerator, where Generate
+// repeats that work on every call. A Generator is safe for concurrent use.
+//
+// Unless Options.Sensitive is set, a Generator reads entropy ahead into a
+// buffer of Options.EntropyBufferSize bytes and serves each call from it,
+// so many small calls share one read from the EntropySource. Entropy a
+// call did not need stays in the buffer for the next one.
+type Generator struct {
+	opts     Options
+	charset  []byte
+	table    *charTable // Nil for the arithmetic sampler or unusual charset sizes
+	readSize int        // Entropy requested per call
+	block    blocklist
+	runes    []rune // Set for a CustomCharset of multi-byte runes
+
+	issued  atomic.Uint64
+	alertAt uint64 // Issued count firing OnKeyspaceAlert, 0 for never
+	alerted atomic.Bool
+
+	mu       sync.Mutex // Guards the fields below
+	src      io.Reader  // Custom EntropySource or buffered, nil for crypto/rand
+	buffered *bufferedSource
+	scratch  []byte
+}
+
+// NewGenerator creates a Generator using Options.
+func NewGenerator(opts Options) (*Generator, error) {
+	var charset []byte
+	var runes []rune
+	var err error
+	if isRuneCharset(opts.CustomCharset) {
+		opts, runes, err = prepareRunes(opts)
+	} else {
+		opts, charset, err = prepare(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	g := &Generator{opts: opts, charset: charset, runes: runes, src: opts.EntropySource}
+	g.alertAt = alertThreshold(opts)
+	g.block = newBlocklist(opts.Blocklist)
+	if runes != nil {
+		g.readSize = 4 * (opts.Length + opts.Length/4 + 2)
+	} else if len(charset) >= 2 && len(charset) <= 256 {
+		g.readSize = readSize(opts.Length, len(charset))
+		if opts.Sampler != SamplerArithmetic {
+			g.table = newCharTable(charset, rejectThreshold(len(charset)))
+		}
+	} else {
+		g.readSize = MaxBuffLength
+	}
+
+	size := opts.EntropyBufferSize
+	if size == 0 {
+		size = 32 * g.readSize
+		if size < minEntropyBufferSize {
+			size = minEntropyBufferSize
+		}
+		if size > maxEntropyBufferSize {
+			size = maxEntropyBufferSize
+		}
+	}
+	if size > 0 && !opts.Sensitive {
+		src := opts.EntropySource
+		if src == nil {
+			src = rand.Reader
+		}
+		g.buffered = newBufferedSource(src, size)
+		g.src = g.buffered
+	}
+	if g.src != nil {
+		g.scratch = make([]byte, g.readSize)
+	}
+	return g, nil
+}
+
+// Discard drops any entropy the Generator has read ahead, zeroing it.
+// The next call reads fresh entropy from the EntropySource.
+func (g *Generator) Discard() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.buffered != nil {
+		g.buffered.Discard()
+	}
+}
+
+// readEntropy fills p from the entropy source of g, through its buffer.
+func (g *Generator) readEntropy(p []byte) error {
+	if g.src == nil {
+		_, err := rand.Read(p)
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, err := io.ReadFull(g.src, p)
+	return err
+}
+
+// Next creates a random string.
+func (g *Generator) Next() (string, error) {
+	output, err := g.next(nil)
+	if err != nil {
+		return "", err
+	}
+	g.count()
+	result := escapeOutput(g.opts, output)
+	if g.opts.Sensitive {
+		wipe(output)
+	}
+	return result, nil
+}
+
+// AppendTo appends an ID, as returned by Next, to dst and returns the
+// extended buffer. Reusing dst avoids the allocation of a string per ID:
+// unless the Options need escaping or grouping, a buffered Generator does
+// not allocate once dst has room.
+func (g *Generator) AppendTo(dst []byte) ([]byte, error) {
+	start := len(dst)
+	dst = append(dst, idPrefix(g.opts)...)
+	var err error
+	if g.opts.Escape == EscapePercent || g.opts.GroupSize > 0 {
+		var output []byte
+		if output, err = g.next(nil); err == nil {
+			dst = appendOutput(dst, g.opts, output)
+			if g.opts.Sensitive {
+				wipe(output)
+			}
+		}
+	} else {
+		// next returns nil on failure, so dst is kept for the truncation.
+		var output []byte
+		if output, err = g.next(dst); err == nil {
+			dst = output
+		}
+	}
+	if err != nil {
+		return dst[:start], err
+	}
+	g.count()
+	return dst, nil
+}
+
+// FillBytes fills all of dst with random chars of the charset, ignoring
+// Length, as the FillBytes function does; it fails for the same Options.
+// It does not allocate for a buffered Generator.
+func (g *Generator) FillBytes(dst []byte) error {
+	if g.runes != nil {
+		return newError(CodeInvalidArgument, "uriuniq: FillBytes needs a single-byte charset")
+	}
+	if err := checkFill(g.opts); err != nil {
+		return err
+	}
+	opts := g.opts
+	var scratch []byte
+	if g.src != nil {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		opts.EntropySource = g.src
+		scratch = g.scratch
+		if opts.Sensitive {
+			defer wipe(scratch)
+		}
+	} else {
+		pooled := getBuffer(g.readSize)
+		defer putBuffer(pooled)
+		scratch = *pooled
+	}
+	unused, err := randFillTable(opts, g.charset, g.table, dst, scratch)
+	if err != nil {
+		return err
+	}
+	if g.buffered != nil {
+		g.buffered.unread(unused)
+	}
+	return nil
+}
+
+// next appends the random chars for Next to dst.
+func (g *Generator) next(dst []byte) ([]byte, error) {
+	if g.opts.LatencyQuantum > 0 {
+		defer padLatency(time.Now(), g.opts.LatencyQuantum)
+	}
+	opts := g.opts
+	if g.runes != nil {
+		if g.src != nil {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			opts.EntropySource = g.src
+		}
+		return appendRunes(dst, opts, g.runes)
+	}
+	var scratch []byte
+	if g.src != nil {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		opts.EntropySource = g.src
+		scratch = g.scratch
+		if opts.Sensitive {
+			defer wipe(scratch)
+		}
+	} else {
+		pooled := getBuffer(g.readSize)
+		defer putBuffer(pooled)
+		scratch = *pooled
+	}
+
+	_, max := lengthRange(opts)
+	buf := grow(dst, max+checkChars(opts))
+	var output, unused []byte
+	var err error
+	profiled(opts.ProfileLabels, "generate", func() {
+		output, err = drawUnblocked(opts, g.block, func() ([]byte, error) {
+			var chars []byte
+			var err error
+			if opts.Length, err = drawLength(opts); err == nil {
+				chars = buf[len(dst) : len(dst)+opts.Length]
+				unused, err = randFillTable(opts, g.charset, g.table, chars, scratch)
+			}
+			if err == nil {
+				stampFingerprint(opts, g.charset, chars)
+				stampNode(opts, g.charset, chars)
+			}
+			if err == nil && (opts.NoLeadingDigit || opts.DNSLabel) {
+				err = drawEdges(opts, g.charset, chars)
+			}
+			if err == nil && composed(opts) {
+				err = compose(opts, g.charset, chars)
+			}
+			return chars, err
+		})
+		if err == nil {
+			// buf has room for the check char, so it is appended in place.
+			n := len(appendCheckChar(opts, g.charset, output))
+			output = buf[:len(dst)+n]
+		}
+	})
+	if g.buffered != nil && err == nil {
+		g.buffered.unread(unused)
+	}
+	if err != nil {
+		if opts.Sensitive && output != nil {
+			wipe(output)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// grow returns b with room for n more bytes.
+func grow(b []byte, n int) []byte {
+	if cap(b)-len(b) >= n {
+		return b
+	}
+	return append(make([]byte, 0, len(b)+n), b...)
+}
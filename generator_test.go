@@ -0,0 +1,51 @@
+package uriuniq
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestGenerator validates Next with default and custom options.
+func TestGenerator(t *testing.T) {
+	opts := NewOpts()
+	opts.CustomCharset = "abc123"
+	gen, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	for i := 0; i < 10; i++ {
+		result, err := gen.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+		if len(result) != DefaultLength || strings.Trim(result, "abc123") != "" {
+			t.Errorf("Unexpected result %q", result)
+		}
+	}
+}
+
+// TestGeneratorMatchesGenerate checks that the precomputed table maps
+// entropy exactly as Generate does.
+func TestGeneratorMatchesGenerate(t *testing.T) {
+	entropy := make([]byte, 4096)
+	for i := range entropy {
+		entropy[i] = byte(i*131 + i/7)
+	}
+	for _, charset := range []Charset{Alphanumeric, Numeric, "ab", "abc-_~"} {
+		opts := Options{Length: 40, CustomCharset: charset, EntropyBufferSize: -1}
+		opts.EntropySource = bytes.NewReader(entropy)
+		want, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate failed: %s", err)
+		}
+		opts.EntropySource = bytes.NewReader(entropy)
+		gen, err := NewGenerator(opts)
+		if err != nil {
+			t.Fatalf("NewGenerator failed: %s", err)
+		}
+		if got, err := gen.Next(); err != nil || got != want {
+			t.Errorf("%q: expected %q, got %q, %v", charset, want, got, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package uriuniq
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultGroupSeparator separates the groups of Options.GroupSize.
+const DefaultGroupSeparator = "-"
+
+// checkGroups applies the default group separator and checks it against
+// charset.
+func checkGroups(opts Options, charset []byte) (Options, error) {
+	if opts.GroupSize == 0 {
+		if opts.GroupSeparator != "" {
+			return opts, newError(CodeInvalidArgument, "uriuniq: GroupSeparator without GroupSize")
+		}
+		return opts, nil
+	}
+	if opts.GroupSize < 0 {
+		return opts, newError(CodeInvalidArgument, "uriuniq: negative group size")
+	}
+	if opts.GroupSeparator == "" {
+		opts.GroupSeparator = DefaultGroupSeparator
+	}
+	if !isURISafe(opts.GroupSeparator) {
+		return opts, errorf(CodeInvalidChar, "uriuniq: group separator %q is not URI-safe", opts.GroupSeparator)
+	}
+	if strings.ContainsAny(opts.GroupSeparator, string(charset)) {
+		return opts, errorf(CodeInvalidArgument, "uriuniq: group separator %q is part of the charset", opts.GroupSeparator)
+	}
+	return opts, nil
+}
+
+// groupSeparator returns the group separator of opts.
+func groupSeparator(opts Options) string {
+	if opts.GroupSeparator == "" {
+		return DefaultGroupSeparator
+	}
+	return opts.GroupSeparator
+}
+
+// appendGrouped appends output to dst with the group separator of opts
+// after every GroupSize chars but the last.
+func appendGrouped(dst []byte, opts Options, output []byte) []byte {
+	sep := groupSeparator(opts)
+	for i := 0; i < len(output); i += opts.GroupSize {
+		if i > 0 {
+			dst = append(dst, sep...)
+		}
+		end := i + opts.GroupSize
+		if end > len(output) {
+			end = len(output)
+		}
+		dst = append(dst, output[i:end]...)
+	}
+	return dst
+}
+
+// groupedLength returns the length of n chars grouped with opts.
+func groupedLength(opts Options, n int) int {
+	if opts.GroupSize <= 0 || n == 0 {
+		return n
+	}
+	return n + (n-1)/opts.GroupSize*len(groupSeparator(opts))
+}
+
+// ungroup removes the group separators from s, which must be exactly at
+// the positions appendGrouped puts them. A misplaced separator is
+// reported as a *ParseError, indexed into s.
+func ungroup(opts Options, s string) (string, error) {
+	if opts.GroupSize <= 0 {
+		return s, nil
+	}
+	sep := groupSeparator(opts)
+	b := make([]byte, 0, len(s))
+	for i := 0; ; {
+		end := i + opts.GroupSize
+		if end >= len(s) {
+			return string(append(b, s[i:]...)), nil
+		}
+		b = append(b, s[i:end]...)
+		if !strings.HasPrefix(s[end:], sep) {
+			return "", &ParseError{Index: end, Char: rune(s[end]), Charset: Charset(sep)}
+		}
+		if i = end + len(sep); i == len(s) {
+			return "", errorf(CodeInvalidLength, "uriuniq: trailing group separator")
+		}
+	}
+}
+
+// groupedIndex maps the index i of a char in the ungrouped string to its
+// index in the grouped one.
+func groupedIndex(opts Options, i int) int {
+	if opts.GroupSize <= 0 {
+		return i
+	}
+	return i + i/opts.GroupSize*len(groupSeparator(opts))
+}
+
+// groupedPattern returns a regexp matching min to max chars of class
+// grouped with opts.
+func groupedPattern(opts Options, class string, min, max int) string {
+	sep := regexp.QuoteMeta(groupSeparator(opts))
+	alts := make([]string, 0, max-min+1)
+	for n := min; n <= max; n++ {
+		full := (n - 1) / opts.GroupSize
+		alts = append(alts, fmt.Sprintf("(?:%s{%d}%s){%d}%s{%d}", class, opts.GroupSize, sep, full, class, n-full*opts.GroupSize))
+	}
+	if len(alts) == 1 {
+		return alts[0]
+	}
+	return "(?:" + strings.Join(alts, "|") + ")"
+}
@@ -0,0 +1,76 @@
+package uriuniq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestGroups checks grouped output, its validation and its pattern.
+func TestGroups(t *testing.T) {
+	opts := Options{Length: 11, CheckChar: true, GroupSize: 4, Prefix: "cpn", ExcludeUppercase: true}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	re, err := Pattern(opts)
+	if err != nil {
+		t.Fatalf("Pattern failed: %s", err)
+	}
+	for i := 0; i < 50; i++ {
+		id, err := g.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+		groups := strings.Split(strings.TrimPrefix(id, "cpn_"), "-")
+		if len(groups) != 3 || len(groups[0]) != 4 || len(groups[1]) != 4 || len(groups[2]) != 4 {
+			t.Fatalf("Unexpected groups in %q", id)
+		}
+		if err := Validate(id, opts); err != nil {
+			t.Errorf("Validate(%q) failed: %s", id, err)
+		}
+		if !re.MatchString(id) {
+			t.Errorf("Pattern %s does not match %q", re, id)
+		}
+	}
+
+	id, _ := g.Next()
+	bare := "cpn_" + strings.ReplaceAll(id[4:], "-", "")
+	var perr *ParseError
+	if err := Validate(bare, opts); !errors.As(err, &perr) || perr.Index != 8 {
+		t.Errorf("Expected ParseError at 8 for %q, got %v", bare, err)
+	}
+	bad := id[:9] + "A" + id[10:]
+	if err := Validate(bad, opts); !errors.As(err, &perr) || perr.Index != 9 {
+		t.Errorf("Expected ParseError at 9 for %q, got %v", bad, err)
+	}
+	if err := Validate(id+"-", opts); err == nil {
+		t.Errorf("Expected error for trailing separator")
+	}
+
+	dst, err := g.AppendTo(nil)
+	if err != nil || Validate(string(dst), opts) != nil {
+		t.Errorf("AppendTo returned %q, %v", dst, err)
+	}
+	if s, err := Generate(Options{Length: 5, GroupSize: 2, GroupSeparator: "."}); err != nil || len(s) != 7 || s[2] != '.' || s[5] != '.' {
+		t.Errorf("Expected 2.2.1 groups, got %q, %v", s, err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		code Code
+	}{
+		{"Separator In Charset", Options{Length: 8, GroupSize: 2, CustomCharset: "ab-"}, CodeInvalidArgument},
+		{"Unsafe Separator", Options{Length: 8, GroupSize: 2, GroupSeparator: " "}, CodeInvalidChar},
+		{"Separator Alone", Options{Length: 8, GroupSeparator: "-"}, CodeInvalidArgument},
+		{"Negative", Options{Length: 8, GroupSize: -1}, CodeInvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.opts); CodeOf(err) != tc.code {
+				t.Errorf("Expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
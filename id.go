@@ -0,0 +1,123 @@
+package uriuniq
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrInvalidID is returned when a value scanned into an ID does not
+// validate. It wraps the error of Validate.
+var ErrInvalidID = newError(CodeInvalidArgument, "uriuniq: invalid ID")
+
+// ID is an ID for struct fields mapped to database columns through
+// database/sql, as by GORM or sqlx, and for API payloads. Scan and the
+// unmarshal methods validate values against the Options set with
+// SetIDOptions, so garbage in a column or request is caught when read. An
+// empty ID is stored as NULL and NULL scans to an empty ID; in text and
+// JSON, it is the empty string.
+type ID string
+
+// idOptions holds the Options set with SetIDOptions, or nil for NewOpts.
+var idOptions atomic.Pointer[Options]
+
+// SetIDOptions sets the Options IDs are validated against, NewOpts by
+// default. Call it once at startup with the Options of the Generator that
+// creates the IDs.
+func SetIDOptions(opts Options) error {
+	if _, _, err := prepare(opts); err != nil {
+		return err
+	}
+	idOptions.Store(&opts)
+	return nil
+}
+
+// IDOptions returns the Options set with SetIDOptions.
+func IDOptions() Options {
+	if opts := idOptions.Load(); opts != nil {
+		return *opts
+	}
+	return NewOpts()
+}
+
+// NewID creates an ID, as Next does.
+func (g *Generator) NewID() (ID, error) {
+	id, err := g.Next()
+	return ID(id), err
+}
+
+// Validate checks id against IDOptions and returns ErrInvalidID, wrapping
+// the error of Validate, if it fails.
+func (id ID) Validate() error {
+	if err := Validate(string(id), IDOptions()); err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidID, string(id), err)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (id ID) Value() (driver.Value, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner. It accepts strings, byte slices and NULL.
+func (id *ID) Scan(src any) error {
+	var s ID
+	switch v := src.(type) {
+	case nil:
+		*id = ""
+		return nil
+	case string:
+		s = ID(v)
+	case []byte:
+		s = ID(v)
+	default:
+		return errorf(CodeInvalidArgument, "uriuniq: cannot scan %T into ID", src)
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	*id = s
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text yields
+// the empty ID; other text must pass Validate.
+func (id *ID) UnmarshalText(text []byte) error {
+	s := ID(text)
+	if s != "" {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+	}
+	*id = s
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (id ID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(id))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. A JSON null leaves id alone,
+// as for other types; a string is unmarshaled as by UnmarshalText.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errorf(CodeInvalidArgument, "uriuniq: ID is not a JSON string: %w", err)
+	}
+	return id.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,135 @@
+package uriuniq
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = ID("")
+	_ sql.Scanner   = (*ID)(nil)
+)
+
+// TestIDScan checks that IDs round-trip through Value and Scan, and that
+// Scan rejects values not matching IDOptions.
+func TestIDScan(t *testing.T) {
+	opts := Options{Length: 10, Prefix: "usr", CheckChar: true}
+	if err := SetIDOptions(opts); err != nil {
+		t.Fatalf("SetIDOptions failed: %s", err)
+	}
+	t.Cleanup(func() { idOptions.Store(nil) })
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	id, err := g.NewID()
+	if err != nil {
+		t.Fatalf("NewID failed: %s", err)
+	}
+	v, err := id.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %s", err)
+	}
+	for _, src := range []any{v, []byte(v.(string))} {
+		var got ID
+		if err := got.Scan(src); err != nil || got != id {
+			t.Errorf("Scan(%v) = %q, %v, expected %q", src, got, err, id)
+		}
+	}
+
+	got := ID("stale")
+	if err := got.Scan(nil); err != nil || got != "" {
+		t.Errorf("Expected NULL to scan to an empty ID, got %q, %v", got, err)
+	}
+	if v, err := ID("").Value(); v != nil || err != nil {
+		t.Errorf("Expected an empty ID to be NULL, got %v, %v", v, err)
+	}
+
+	tests := []struct {
+		name string
+		src  any
+		want error
+	}{
+		{"Garbage", "usr_not an id!", ErrInvalidID},
+		{"No Prefix", string(id[4:]), ErrMissingPrefix},
+		{"Wrong Type", 42, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ID("kept")
+			err := got.Scan(tc.src)
+			if err == nil || tc.want != nil && !errors.Is(err, tc.want) || CodeOf(err) != CodeInvalidArgument {
+				t.Errorf("Expected %v, got %v", tc.want, err)
+			}
+			if got != "kept" {
+				t.Errorf("Expected the ID to be left alone, got %q", got)
+			}
+		})
+	}
+	if err := SetIDOptions(Options{Length: 8, ExcludeNumeric: true, MinDigits: 1}); err == nil {
+		t.Errorf("Expected error for invalid Options")
+	}
+}
+
+// TestIDJSON checks that IDs in payloads are validated on unmarshal.
+func TestIDJSON(t *testing.T) {
+	opts := Options{Length: 10, Prefix: "usr"}
+	if err := SetIDOptions(opts); err != nil {
+		t.Fatalf("SetIDOptions failed: %s", err)
+	}
+	t.Cleanup(func() { idOptions.Store(nil) })
+	g, _ := NewGenerator(opts)
+	id, _ := g.NewID()
+
+	type payload struct {
+		User  ID  `json:"user"`
+		Owner *ID `json:"owner,omitempty"`
+	}
+	data, err := json.Marshal(payload{User: id})
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+	if want := `{"user":"` + string(id) + `"}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil || p.User != id || p.Owner != nil {
+		t.Errorf("Unmarshal(%s) = %+v, %v", data, p, err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		ok   bool
+	}{
+		{"Null", `{"user":null}`, true},
+		{"Empty", `{"user":""}`, true},
+		{"Wrong Length", `{"user":"usr_abc"}`, false},
+		{"Missing Prefix", `{"user":"` + string(id[4:]) + `"}`, false},
+		{"Not A String", `{"user":42}`, false},
+		{"Pointer", `{"user":"` + string(id) + `","owner":"usr_bad!"}`, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var p payload
+			err := json.Unmarshal([]byte(tc.data), &p)
+			if tc.ok != (err == nil) {
+				t.Errorf("Expected ok %t, got %v", tc.ok, err)
+			}
+			if !tc.ok && CodeOf(err) != CodeInvalidArgument {
+				t.Errorf("Expected CodeInvalidArgument, got %v", err)
+			}
+		})
+	}
+
+	var text ID
+	if err := text.UnmarshalText([]byte(id)); err != nil || text != id {
+		t.Errorf("UnmarshalText = %q, %v", text, err)
+	}
+	if b, _ := text.MarshalText(); string(b) != string(id) {
+		t.Errorf("MarshalText = %q", b)
+	}
+}
@@ -0,0 +1,276 @@
+// Package idempotency makes retried HTTP requests safe. Clients send an
+// Idempotency-Key header; the server runs the first request with a key and
+// replays its stored response to every later request with the same key
+// within the deduplication window.
+//
+// Example:
+//
+//	mw := &idempotency.Middleware{Store: idempotency.NewMemoryStore(), Window: 24 * time.Hour}
+//	http.Handle("/payments", mw.Handler(paymentsHandler))
+//	// In the client, before the first attempt:
+//	key, err := idempotency.SetKey(req)
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// Header is the request header carrying the key.
+const Header = "Idempotency-Key"
+
+// ReplayedHeader is set to "true" on responses replayed from the Store.
+const ReplayedHeader = "Idempotent-Replayed"
+
+// MaxKeyLength is the longest key accepted.
+const MaxKeyLength = 255
+
+// DefaultWindow is the deduplication window of a Middleware.
+const DefaultWindow = 24 * time.Hour
+
+// ErrInvalidKey is returned by ValidateKey.
+var ErrInvalidKey = &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: errors.New("idempotency: invalid key")}
+
+// NewKey creates a key of 32 alphanumeric chars.
+func NewKey() (string, error) {
+	return keys.Next()
+}
+
+// keys generates the keys of NewKey.
+var keys, _ = uriuniq.NewGenerator(uriuniq.Options{Length: 32})
+
+// SetKey sets the Header of req to a new key unless it has one already,
+// and returns the key. Call it once before the first attempt, so every
+// retry of req carries the same key.
+func SetKey(req *http.Request) (string, error) {
+	if key := req.Header.Get(Header); key != "" {
+		return key, nil
+	}
+	key, err := NewKey()
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(Header, key)
+	return key, nil
+}
+
+// ValidateKey checks that key has 1 to MaxKeyLength printable ASCII chars,
+// which admits both NewKey output and client UUIDs.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > MaxKeyLength {
+		return ErrInvalidKey
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] > '~' {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// Response is a stored response.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Store persists responses by key. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Begin reserves key for window. If key is already reserved it returns
+	// ok false and the stored response, or a nil response while the first
+	// request is still running.
+	Begin(ctx context.Context, key string, window time.Duration) (resp *Response, ok bool, err error)
+	// Finish stores the response of the request that reserved key.
+	Finish(ctx context.Context, key string, resp *Response) error
+	// Abort releases key, so the request can be retried.
+	Abort(ctx context.Context, key string) error
+}
+
+// Middleware deduplicates requests by their Header. Requests with safe
+// methods (GET, HEAD, OPTIONS, TRACE) pass through unchanged. A request
+// without a key gets a new one, echoed in the response Header, unless
+// Required is set, in which case it is rejected with 400. Responses with
+// a 5xx status are not stored, so the request can be retried.
+type Middleware struct {
+	Store    Store
+	Window   time.Duration // Defaults to DefaultWindow
+	Required bool
+}
+
+// Handler wraps next.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := r.Header.Get(Header)
+		if key == "" && !m.Required {
+			var err error
+			if key, err = NewKey(); err != nil {
+				http.Error(w, "cannot create idempotency key", http.StatusInternalServerError)
+				return
+			}
+			r.Header.Set(Header, key)
+		}
+		if err := ValidateKey(key); err != nil {
+			http.Error(w, "missing or invalid "+Header, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set(Header, key)
+
+		window := m.Window
+		if window <= 0 {
+			window = DefaultWindow
+		}
+		ctx := r.Context()
+		stored, ok, err := m.Store.Begin(ctx, key, window)
+		switch {
+		case err != nil:
+			http.Error(w, "idempotency store unavailable", http.StatusServiceUnavailable)
+			return
+		case !ok && stored == nil:
+			http.Error(w, "request with this "+Header+" is in progress", http.StatusConflict)
+			return
+		case !ok:
+			replay(w, stored)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			if !completed || rec.status >= 500 {
+				m.Store.Abort(ctx, key)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+		completed = true
+		if rec.status < 500 {
+			m.Store.Finish(ctx, key, &Response{Status: rec.status, Header: w.Header().Clone(), Body: rec.body.Bytes()})
+		}
+	})
+}
+
+// replay writes a stored response.
+func replay(w http.ResponseWriter, resp *Response) {
+	for k, vs := range resp.Header {
+		w.Header()[k] = append([]string(nil), vs...)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// recorder passes a response through while keeping a copy.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// minSweep is the number of keys a MemoryStore holds before Begin first
+// sweeps out expired ones.
+const minSweep = 64
+
+// MemoryStore is a Store in memory, for tests and single-process use.
+// Keys past their window are dropped by Begin in sweeps, run whenever the
+// store has doubled in size since the last one, so memory stays
+// proportional to the keys still in their window.
+type MemoryStore struct {
+	Now func() time.Time // Defaults to time.Now
+
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	sweepSize int // Size triggering the next sweep
+}
+
+type memoryEntry struct {
+	expires time.Time
+	resp    *Response
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry), sweepSize: minSweep}
+}
+
+// Begin implements Store.
+func (s *MemoryStore) Begin(_ context.Context, key string, window time.Duration) (*Response, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		return e.resp, false, nil
+	}
+	s.entries[key] = &memoryEntry{expires: now.Add(window)}
+	if len(s.entries) >= s.sweepSize {
+		s.sweep(now)
+	}
+	return nil, true, nil
+}
+
+// sweep drops the keys past their window at now and sets the size
+// triggering the next sweep, so sweeps cost amortized constant time per
+// Begin.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.sweepSize = 2 * len(s.entries)
+	if s.sweepSize < minSweep {
+		s.sweepSize = minSweep
+	}
+}
+
+// Finish implements Store.
+func (s *MemoryStore) Finish(_ context.Context, key string, resp *Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: errors.New("idempotency: key not reserved")}
+	}
+	e.resp = resp
+	return nil
+}
+
+// Abort implements Store.
+func (s *MemoryStore) Abort(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryStore) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
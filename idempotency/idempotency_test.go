@@ -0,0 +1,151 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestKeys checks key creation and validation.
+func TestKeys(t *testing.T) {
+	key, err := NewKey()
+	if err != nil || len(key) != 32 {
+		t.Fatalf("Unexpected key %q, %v", key, err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	set, err := SetKey(req)
+	if err != nil || req.Header.Get(Header) != set {
+		t.Fatalf("SetKey did not set the header: %q, %v", set, err)
+	}
+	if again, _ := SetKey(req); again != set {
+		t.Errorf("SetKey replaced key %q with %q", set, again)
+	}
+
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{key, true},
+		{"123e4567-e89b-12d3-a456-426614174000", true},
+		{"", false},
+		{"has space", false},
+		{"café", false},
+		{strings.Repeat("a", MaxKeyLength+1), false},
+	}
+	for _, tc := range tests {
+		if err := ValidateKey(tc.key); (err == nil) != tc.valid || err != nil && !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("ValidateKey(%q): expected valid %v, got %v", tc.key, tc.valid, err)
+		}
+	}
+}
+
+// TestMiddleware checks that requests with the same key run once.
+func TestMiddleware(t *testing.T) {
+	var calls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if r.URL.Path == "/fail" {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("X-Call", fmt.Sprint(n))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "call %d", n)
+	})
+	mw := &Middleware{Store: NewMemoryStore()}
+	h := mw.Handler(handler)
+	do := func(method, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set(Header, key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	first := do(http.MethodPost, "/pay", "key-1")
+	second := do(http.MethodPost, "/pay", "key-1")
+	if first.Code != http.StatusCreated || second.Code != http.StatusCreated || second.Body.String() != "call 1" {
+		t.Errorf("Expected replay of call 1, got %d %q", second.Code, second.Body.String())
+	}
+	if second.Header().Get(ReplayedHeader) != "true" || second.Header().Get("X-Call") != "1" || first.Header().Get(ReplayedHeader) != "" {
+		t.Errorf("Unexpected replay headers %v", second.Header())
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 call, got %d", calls.Load())
+	}
+
+	if w := do(http.MethodPost, "/pay", ""); w.Code != http.StatusCreated || ValidateKey(w.Header().Get(Header)) != nil {
+		t.Errorf("Expected generated key, got %d %v", w.Code, w.Header())
+	}
+	if w := do(http.MethodPost, "/pay", "bad key"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid key, got %d", w.Code)
+	}
+	do(http.MethodGet, "/pay", "key-1")
+	do(http.MethodPost, "/fail", "key-2")
+	do(http.MethodPost, "/fail", "key-2")
+	if calls.Load() != 5 {
+		t.Errorf("Expected GET and failed requests to run, got %d calls", calls.Load())
+	}
+
+	mw.Required = true
+	if w := do(http.MethodPost, "/pay", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for missing required key, got %d", w.Code)
+	}
+}
+
+// TestMemoryStore checks in-flight requests and the window.
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewMemoryStore()
+	s.Now = func() time.Time { return now }
+
+	if _, ok, _ := s.Begin(context.Background(), "k", time.Hour); !ok {
+		t.Fatalf("Expected first Begin to reserve the key")
+	}
+	if resp, ok, _ := s.Begin(context.Background(), "k", time.Hour); ok || resp != nil {
+		t.Errorf("Expected in-flight key, got %v %v", resp, ok)
+	}
+	s.Finish(context.Background(), "k", &Response{Status: 200})
+	if resp, ok, _ := s.Begin(context.Background(), "k", time.Hour); ok || resp == nil || resp.Status != 200 {
+		t.Errorf("Expected stored response, got %v %v", resp, ok)
+	}
+	now = now.Add(2 * time.Hour)
+	if _, ok, _ := s.Begin(context.Background(), "k", time.Hour); !ok {
+		t.Errorf("Expected key to be free after its window")
+	}
+	if err := s.Finish(context.Background(), "other", &Response{}); err == nil {
+		t.Errorf("Expected error finishing an unreserved key")
+	}
+}
+
+// TestMemoryStoreSweep checks that keys past their window are dropped even
+// if they are never used again.
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewMemoryStore()
+	s.Now = func() time.Time { return now }
+	ctx := context.Background()
+	for i := 0; i < 1000; i++ {
+		s.Begin(ctx, fmt.Sprintf("old-%d", i), time.Minute)
+	}
+	s.Begin(ctx, "live", 10000*time.Hour)
+	now = now.Add(time.Hour)
+	for i := 0; i < 1000; i++ {
+		s.Begin(ctx, fmt.Sprintf("new-%d", i), time.Minute)
+		now = now.Add(time.Hour)
+	}
+	if n := len(s.entries); n > 2*minSweep {
+		t.Errorf("Expected expired keys to be swept, %d left", n)
+	}
+	if _, ok := s.entries["live"]; !ok {
+		t.Errorf("Expected the key still in its window to be kept")
+	}
+}
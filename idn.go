@@ -0,0 +1,162 @@
+package uriuniq
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DNS length limits in octets, per RFC 1035.
+const (
+	MaxDNSLabelLength = 63
+	MaxDNSNameLength  = 253
+)
+
+// ErrDNSTooLong is returned when a slug does not fit the DNS length limits.
+var ErrDNSTooLong = newError(CodeDNSTooLong, "uriuniq: too long for DNS")
+
+// IDNSlug is a random DNS label in its Unicode and ASCII (punycode) forms.
+type IDNSlug struct {
+	Unicode string // e.g. "bücher"
+	ASCII   string // e.g. "xn--bcher-kva", equal to Unicode if all ASCII
+}
+
+// GenerateIDNSlug creates a random label of opts.Length runes from alphabet,
+// for international link shorteners that need both forms. The runes of
+// alphabet must be lowercase letters or digits in Unicode normalization
+// form C; hyphens are not allowed. If domain is not empty, the label plus
+// "." plus domain, which must be in ASCII form, must fit MaxDNSNameLength.
+func GenerateIDNSlug(alphabet, domain string, opts Options) (IDNSlug, error) {
+	var runes []rune
+	seen := make(map[rune]bool)
+	for i, r := range alphabet {
+		if r == utf8.RuneError || unicode.IsUpper(r) || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return IDNSlug{}, &ParseError{Index: i, Char: r}
+		}
+		if !seen[r] {
+			seen[r] = true
+			runes = append(runes, r)
+		}
+	}
+	if len(runes) < 2 {
+		return IDNSlug{}, newError(CodeCharsetSize, "uriuniq: alphabet needs at least 2 runes")
+	}
+	for i := 0; i < len(domain); i++ {
+		if domain[i] >= utf8.RuneSelf {
+			return IDNSlug{}, newError(CodeInvalidArgument, "uriuniq: domain must be in ASCII form")
+		}
+	}
+
+	if opts.Length <= 0 {
+		opts.Length = DefaultLength
+	}
+	if opts.MaxBadReads <= 0 {
+		opts.MaxBadReads = DefaultMaxBadReads
+	}
+	label, err := randRunes(opts, runes)
+	if err != nil {
+		return IDNSlug{}, err
+	}
+
+	slug := IDNSlug{Unicode: string(label), ASCII: string(label)}
+	for _, r := range label {
+		if r >= utf8.RuneSelf {
+			slug.ASCII = "xn--" + punycode(label)
+			break
+		}
+	}
+	if len(slug.ASCII) > MaxDNSLabelLength {
+		return IDNSlug{}, fmt.Errorf("%w: label is %d octets", ErrDNSTooLong, len(slug.ASCII))
+	}
+	if domain != "" && len(slug.ASCII)+1+len(domain) > MaxDNSNameLength {
+		return IDNSlug{}, fmt.Errorf("%w: name is %d octets", ErrDNSTooLong, len(slug.ASCII)+1+len(domain))
+	}
+	return slug, nil
+}
+
+// punycode encodes runes as in RFC 3492, without the "xn--" prefix.
+func punycode(runes []rune) string {
+	const (
+		base        = 36
+		tmin        = 1
+		tmax        = 26
+		skew        = 38
+		damp        = 700
+		initialBias = 72
+		initialN    = 128
+	)
+	var out strings.Builder
+	basic := 0
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out.WriteRune(r)
+			basic++
+		}
+	}
+	if basic > 0 {
+		out.WriteByte('-')
+	}
+
+	digit := func(d int) byte {
+		if d < 26 {
+			return byte('a' + d)
+		}
+		return byte('0' + d - 26)
+	}
+	adapt := func(delta, points int, first bool) int {
+		if first {
+			delta /= damp
+		} else {
+			delta /= 2
+		}
+		delta += delta / points
+		k := 0
+		for delta > (base-tmin)*tmax/2 {
+			delta /= base - tmin
+			k += base
+		}
+		return k + (base-tmin+1)*delta/(delta+skew)
+	}
+
+	n, delta, bias := initialN, 0, initialBias
+	for h := basic; h < len(runes); {
+		m := int(unicode.MaxRune) + 1
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+		delta += (m - n) * (h + 1)
+		n = m
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := base; ; k += base {
+				t := k - bias
+				if t < tmin {
+					t = tmin
+				} else if t > tmax {
+					t = tmax
+				}
+				if q < t {
+					break
+				}
+				out.WriteByte(digit(t + (q-t)%(base-t)))
+				q = (q - t) / (base - t)
+			}
+			out.WriteByte(digit(q))
+			bias = adapt(delta, h+1, h == basic)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return out.String()
+}
@@ -0,0 +1,62 @@
+package uriuniq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestPunycode checks the encoder against known encodings.
+func TestPunycode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"bücher", "bcher-kva"},
+		{"münchen", "mnchen-3ya"},
+		{"ü", "tda"},
+		{"日本語", "wgv71a119e"},
+		{"пример", "e1afmkfd"},
+		{"abc", "abc-"},
+	}
+	for _, tc := range tests {
+		if got := punycode([]rune(tc.input)); got != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.input, tc.expected, got)
+		}
+	}
+}
+
+// TestGenerateIDNSlug checks both forms and the DNS limits.
+func TestGenerateIDNSlug(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 8
+	slug, err := GenerateIDNSlug("абвгдеж", "example.com", opts)
+	if err != nil {
+		t.Fatalf("GenerateIDNSlug failed: %s", err)
+	}
+	if utf8.RuneCountInString(slug.Unicode) != 8 || !strings.HasPrefix(slug.ASCII, "xn--") {
+		t.Errorf("Unexpected slug %+v", slug)
+	}
+
+	slug, err = GenerateIDNSlug("abc", "", opts)
+	if err != nil || slug.ASCII != slug.Unicode {
+		t.Errorf("Expected identical ASCII form, got %+v, %v", slug, err)
+	}
+
+	opts.Length = 60
+	if _, err := GenerateIDNSlug("日本語", "", opts); !errors.Is(err, ErrDNSTooLong) {
+		t.Errorf("Expected ErrDNSTooLong, got %v", err)
+	}
+	opts.Length = 8
+	if _, err := GenerateIDNSlug("abc", strings.Repeat("a", 250), opts); !errors.Is(err, ErrDNSTooLong) {
+		t.Errorf("Expected ErrDNSTooLong for long name, got %v", err)
+	}
+	var perr *ParseError
+	if _, err := GenerateIDNSlug("abC", "", opts); !errors.As(err, &perr) || perr.Index != 2 {
+		t.Errorf("Expected ParseError at index 2, got %v", err)
+	}
+	if _, err := GenerateIDNSlug("ab", "bücher.de", opts); err == nil {
+		t.Errorf("Expected error for Unicode domain")
+	}
+}
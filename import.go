@@ -0,0 +1,99 @@
+package uriuniq
+
+import "sync"
+
+// UniquenessChecker tracks IDs that are taken, whether generated here or
+// supplied from elsewhere. Implementations must be safe for concurrent use.
+type UniquenessChecker interface {
+	// Seen reports whether id is taken.
+	Seen(id string) (bool, error)
+	// Record marks id as taken.
+	Record(id string) error
+}
+
+// MemoryChecker is a UniquenessChecker in memory, for tests and
+// single-process use.
+type MemoryChecker struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewMemoryChecker creates an empty MemoryChecker.
+func NewMemoryChecker() *MemoryChecker {
+	return &MemoryChecker{seen: make(map[string]struct{})}
+}
+
+// Seen implements UniquenessChecker.
+func (c *MemoryChecker) Seen(id string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.seen[id]
+	return ok, nil
+}
+
+// Record implements UniquenessChecker.
+func (c *MemoryChecker) Record(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seen[id] = struct{}{}
+	return nil
+}
+
+// ImportError is a code rejected by ImportCodes.
+type ImportError struct {
+	Index int // Position in the input
+	Value string
+	Err   error
+}
+
+// ImportReport is the result of ImportCodes.
+type ImportReport struct {
+	Reserved   []string      // Codes recorded in the checker
+	Duplicates []string      // Codes already taken or repeated in the input
+	Invalid    []ImportError // Codes not matching the Options
+}
+
+// ImportCodes reserves externally supplied codes, such as codes from a
+// vendor, in checker, so they are treated as taken from then on. Each
+// code must have a length and chars allowed by opts. Codes the checker has
+// seen, whether generated or imported earlier, are reported as duplicates
+// and left alone. A checker error stops the import and is returned with
+// the report so far.
+func ImportCodes(codes []string, opts Options, checker UniquenessChecker) (ImportReport, error) {
+	var report ImportReport
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return report, err
+	}
+	min, max := lengthRange(opts)
+	inBatch := make(map[string]bool, len(codes))
+	for i, code := range codes {
+		if len(code) < min || len(code) > max {
+			report.Invalid = append(report.Invalid, ImportError{i, code,
+				errorf(CodeInvalidLength, "uriuniq: length %d, want %d-%d", len(code), min, max)})
+			continue
+		}
+		if err := checkCharset(code, Charset(charset)); err != nil {
+			report.Invalid = append(report.Invalid, ImportError{i, code, err})
+			continue
+		}
+		if inBatch[code] {
+			report.Duplicates = append(report.Duplicates, code)
+			continue
+		}
+		inBatch[code] = true
+		seen, err := checker.Seen(code)
+		if err != nil {
+			return report, err
+		}
+		if seen {
+			report.Duplicates = append(report.Duplicates, code)
+			continue
+		}
+		if err := checker.Record(code); err != nil {
+			return report, err
+		}
+		report.Reserved = append(report.Reserved, code)
+	}
+	return report, nil
+}
@@ -0,0 +1,43 @@
+package uriuniq
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// failingChecker is a UniquenessChecker whose Seen fails.
+type failingChecker struct{ err error }
+
+func (f failingChecker) Seen(string) (bool, error) { return false, f.err }
+func (f failingChecker) Record(string) error       { return f.err }
+
+// TestImportCodes checks validation, deduplication and reservation.
+func TestImportCodes(t *testing.T) {
+	opts := Options{Length: 6, CustomCharset: "ABCDEF123"}
+	checker := NewMemoryChecker()
+	checker.Record("AAA111")
+
+	report, err := ImportCodes([]string{"ABC123", "AAA111", "ABC123", "abc123", "ABC12", "FED321"}, opts, checker)
+	if err != nil {
+		t.Fatalf("ImportCodes failed: %s", err)
+	}
+	if !reflect.DeepEqual(report.Reserved, []string{"ABC123", "FED321"}) {
+		t.Errorf("Unexpected reserved codes %v", report.Reserved)
+	}
+	if !reflect.DeepEqual(report.Duplicates, []string{"AAA111", "ABC123"}) {
+		t.Errorf("Unexpected duplicates %v", report.Duplicates)
+	}
+	if len(report.Invalid) != 2 || report.Invalid[0].Index != 3 || CodeOf(report.Invalid[0].Err) != CodeInvalidChar ||
+		CodeOf(report.Invalid[1].Err) != CodeInvalidLength {
+		t.Errorf("Unexpected invalid codes %+v", report.Invalid)
+	}
+	if seen, _ := checker.Seen("FED321"); !seen {
+		t.Errorf("Expected imported code to be recorded")
+	}
+
+	fail := errors.New("backend down")
+	if _, err := ImportCodes([]string{"ABC123"}, opts, failingChecker{fail}); !errors.Is(err, fail) {
+		t.Errorf("Expected checker error, got %v", err)
+	}
+}
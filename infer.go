@@ -0,0 +1,146 @@
+package uriuniq
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Inference describes the format inferred from a sample of IDs.
+type Inference struct {
+	Options Options // Charset and lengths of the part after Prefix
+	Prefix  string  // Common prefix ending in '_' or '-', such as "usr_"
+	Format  *Format // Well-known format all samples match, or nil
+
+	// Confidence estimates, from 0 to 1, that the charset is complete:
+	// that a larger sample would not show chars outside it.
+	Confidence float64
+}
+
+// InferOptions infers the Options that generate IDs like samples, to
+// reproduce a legacy format. See Infer for the prefix and format.
+func InferOptions(samples []string) (Options, float64) {
+	inf := Infer(samples)
+	return inf.Options, inf.Confidence
+}
+
+// Infer infers the charset, length range, prefix and well-known format of
+// samples. Chars of a class seen in the samples, such as one lowercase
+// letter, make the whole class part of the charset; other chars are added
+// one by one.
+func Infer(samples []string) Inference {
+	if len(samples) == 0 {
+		return Inference{}
+	}
+	var inf Inference
+	for _, f := range []Format{FormatULID, FormatUUIDv7, FormatTypeID} {
+		if matchesAll(f, samples) {
+			f := f
+			inf.Format = &f
+			break
+		}
+	}
+	inf.Prefix = commonPrefix(samples)
+
+	var seen [256]bool
+	var digit, lower, upper bool
+	var others []byte
+	minLen, maxLen, total := math.MaxInt, 0, 0
+	for _, s := range samples {
+		s = s[len(inf.Prefix):]
+		if len(s) < minLen {
+			minLen = len(s)
+		}
+		if len(s) > maxLen {
+			maxLen = len(s)
+		}
+		total += len(s)
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			switch {
+			case '0' <= c && c <= '9':
+				digit = true
+			case 'a' <= c && c <= 'z':
+				lower = true
+			case 'A' <= c && c <= 'Z':
+				upper = true
+			default:
+				if !seen[c] {
+					others = append(others, c)
+				}
+			}
+			seen[c] = true
+		}
+	}
+
+	opts := NewOpts()
+	if len(others) > 0 {
+		sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+		opts.CustomCharset = classCharset(digit, lower, upper, false) + Charset(others)
+	} else {
+		opts.ExcludeNumeric, opts.ExcludeLowercase, opts.ExcludeUppercase = !digit, !lower, !upper
+	}
+	if minLen == maxLen {
+		opts.Length = minLen
+	} else {
+		opts.MinLength, opts.MaxLength = minLen, maxLen
+	}
+	inf.Options = opts
+
+	_, charset, err := prepare(opts)
+	if err != nil || total == 0 {
+		return inf
+	}
+	observed := 0
+	for _, c := range charset {
+		if seen[c] {
+			observed++
+		}
+	}
+	// Coverage of the charset, times the chance that n draws show each of
+	// its k chars at least once.
+	k, n := float64(len(charset)), float64(total)
+	inf.Confidence = float64(observed) / k * math.Pow(-math.Expm1(-n/k), k)
+	return inf
+}
+
+// matchesAll reports whether all samples are valid in format f.
+func matchesAll(f Format, samples []string) bool {
+	re := regexp.MustCompile(f.Pattern)
+	for _, s := range samples {
+		if len(s) < f.MinLength || len(s) > f.MaxLength || !re.MatchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// commonPrefix returns the longest prefix of all samples that ends in '_'
+// or '-' and is shorter than all of them.
+func commonPrefix(samples []string) string {
+	prefix := samples[0]
+	for _, s := range samples[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for len(prefix) > 0 {
+		last := prefix[len(prefix)-1]
+		if (last == '_' || last == '-') && !anyEqual(samples, prefix) {
+			return prefix
+		}
+		prefix = prefix[:len(prefix)-1]
+	}
+	return ""
+}
+
+// anyEqual reports whether any sample equals s.
+func anyEqual(samples []string, s string) bool {
+	for _, x := range samples {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
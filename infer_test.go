@@ -0,0 +1,57 @@
+package uriuniq
+
+import (
+	"testing"
+)
+
+// TestInferOptions checks inference from generated samples.
+func TestInferOptions(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 12
+	opts.ExcludeUppercase = true
+	var samples []string
+	for i := 0; i < 200; i++ {
+		id, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate failed: %s", err)
+		}
+		samples = append(samples, "usr_"+id)
+	}
+
+	inf := Infer(samples)
+	if inf.Prefix != "usr_" || inf.Format != nil {
+		t.Errorf("Unexpected prefix %q, format %v", inf.Prefix, inf.Format)
+	}
+	got, confidence := InferOptions(samples)
+	if got.Length != 12 || !got.ExcludeUppercase || got.ExcludeLowercase || got.ExcludeNumeric || got.CustomCharset != "" {
+		t.Errorf("Unexpected options %+v", got)
+	}
+	if confidence < 0.99 {
+		t.Errorf("Expected high confidence, got %f", confidence)
+	}
+
+	_, low := InferOptions(samples[:1])
+	if low > 0.1 {
+		t.Errorf("Expected low confidence for one sample, got %f", low)
+	}
+}
+
+// TestInfer checks length ranges, custom chars and well-known formats.
+func TestInfer(t *testing.T) {
+	inf := Infer([]string{"ab.c", "a~bcd"})
+	if inf.Options.CustomCharset != Lowercase+".~" || inf.Options.MinLength != 4 || inf.Options.MaxLength != 5 {
+		t.Errorf("Unexpected options %+v", inf.Options)
+	}
+
+	inf = Infer([]string{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01BX5ZZKBKACTAV9WEVGEMMVRZ"})
+	if inf.Format == nil || inf.Format.Name != "ulid" {
+		t.Errorf("Expected ulid format, got %v", inf.Format)
+	}
+
+	for _, samples := range [][]string{nil, {"a_", "a_"}} {
+		inf := Infer(samples)
+		if inf.Prefix != "" {
+			t.Errorf("%q: expected no prefix, got %q", samples, inf.Prefix)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package uriuniq
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over random strings generated with opts:
+//
+//	for id, err := range uriuniq.All(ctx, opts) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+//
+// It yields until the loop breaks, an error occurs, or ctx is done. Errors,
+// including ctx.Err(), are yielded once with an empty string, after which
+// the iterator stops.
+func All(ctx context.Context, opts Options) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		gen, err := NewGenerator(opts)
+		if err != nil {
+			yield("", err)
+			return
+		}
+		for {
+			if err := ctx.Err(); err != nil {
+				yield("", err)
+				return
+			}
+			id, err := gen.Next()
+			if err != nil {
+				yield("", err)
+				return
+			}
+			if !yield(id, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+//go:build go1.23
+
+package uriuniq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestAll checks iteration, early break and cancellation.
+func TestAll(t *testing.T) {
+	seen := make(map[string]bool)
+	for id, err := range All(context.Background(), NewOpts()) {
+		if err != nil {
+			t.Fatalf("All failed: %s", err)
+		}
+		seen[id] = true
+		if len(seen) == 100 {
+			break
+		}
+	}
+	if len(seen) != 100 {
+		t.Errorf("Expected 100 distinct IDs, got %d", len(seen))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := 0
+	var last error
+	for _, err := range All(ctx, NewOpts()) {
+		if err != nil {
+			last = err
+			continue
+		}
+		if n++; n == 3 {
+			cancel()
+		}
+	}
+	if n != 3 || !errors.Is(last, context.Canceled) {
+		t.Errorf("Expected 3 IDs then context.Canceled, got %d and %v", n, last)
+	}
+
+	opts := NewOpts()
+	opts.CustomCharset = "aA"
+	opts.Transform = TransformLowerStrict
+	for _, err := range All(context.Background(), opts) {
+		if !errors.Is(err, ErrTransformMerge) {
+			t.Errorf("Expected ErrTransformMerge, got %v", err)
+		}
+	}
+}
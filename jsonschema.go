@@ -0,0 +1,33 @@
+package uriuniq
+
+import (
+	"encoding/json"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect emitted by JSONSchema.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+type jsonSchema struct {
+	Schema    string `json:"$schema"`
+	Title     string `json:"title,omitempty"`
+	Type      string `json:"type"`
+	Pattern   string `json:"pattern"`
+	MinLength int    `json:"minLength"`
+	MaxLength int    `json:"maxLength"`
+}
+
+// JSONSchema returns a draft 2020-12 JSON Schema for string values of format.
+// Use OptionsFormat to describe the strings generated with an Options.
+func JSONSchema(format Format) ([]byte, error) {
+	if format.Pattern == "" {
+		return nil, newError(CodeInvalidArgument, "uriuniq: format has no pattern")
+	}
+	return json.MarshalIndent(jsonSchema{
+		Schema:    jsonSchemaDraft,
+		Title:     format.Name,
+		Type:      "string",
+		Pattern:   format.Pattern,
+		MinLength: format.MinLength,
+		MaxLength: format.MaxLength,
+	}, "", "  ")
+}
@@ -0,0 +1,35 @@
+package uriuniq
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestJSONSchema checks the emitted schema fields.
+func TestJSONSchema(t *testing.T) {
+	schema, err := JSONSchema(FormatULID)
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %s", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(schema, &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %s", err)
+	}
+	expected := map[string]any{
+		"$schema":   "https://json-schema.org/draft/2020-12/schema",
+		"title":     "ulid",
+		"type":      "string",
+		"pattern":   FormatULID.Pattern,
+		"minLength": 26.0,
+		"maxLength": 26.0,
+	}
+	for key, value := range expected {
+		if decoded[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, decoded[key])
+		}
+	}
+
+	if _, err := JSONSchema(Format{}); err == nil {
+		t.Errorf("Expected error for empty format")
+	}
+}
@@ -0,0 +1,119 @@
+package uriuniq
+
+import (
+	"fmt"
+	"math"
+)
+
+// LengthUnit is the unit in which Options count lengths.
+type LengthUnit int
+
+const (
+	// Characters counts the chars of a string.
+	Characters LengthUnit = iota
+	// EntropyBytes counts bytes of entropy: strings get the fewest chars
+	// carrying at least that much, plus any fingerprint chars.
+	EntropyBytes
+	// EncodedWidth counts bytes of the string once percent-encoded in a
+	// URI: strings get the most chars that always fit. It equals Characters
+	// for URI-safe charsets and is a third of it otherwise.
+	EncodedWidth
+)
+
+func (u LengthUnit) String() string {
+	switch u {
+	case Characters:
+		return "characters"
+	case EntropyBytes:
+		return "entropy bytes"
+	case EncodedWidth:
+		return "encoded width"
+	}
+	return fmt.Sprintf("LengthUnit(%d)", int(u))
+}
+
+// ConvertLength converts the length n from one unit to another for the
+// charset of opts. Conversions to EncodedWidth give the widest string of
+// the length, conversions to EntropyBytes the whole bytes of entropy it
+// carries; fingerprint chars are not counted.
+func ConvertLength(n int, from, to LengthUnit, opts Options) (int, error) {
+	// Only the charset of opts is needed.
+	opts.Length, opts.MinLength, opts.MaxLength = 1, 0, 0
+	opts.LengthUnit, opts.InstanceLabel = Characters, ""
+	_, charset, err := prepare(opts)
+	if err != nil {
+		return 0, err
+	}
+	chars, err := unitToChars(n, from, charset)
+	if err != nil {
+		return 0, err
+	}
+	switch to {
+	case Characters:
+		return chars, nil
+	case EntropyBytes:
+		return int(float64(chars) * BitsPerChar(Charset(charset)) / 8), nil
+	case EncodedWidth:
+		return chars * maxEncodedCharWidth(charset), nil
+	}
+	return 0, errorf(CodeInvalidArgument, "uriuniq: unknown length unit %d", int(to))
+}
+
+// toCharacters converts the lengths of opts to Characters.
+func toCharacters(opts Options, charset []byte) (Options, error) {
+	var err error
+	convert := func(n int) int {
+		if err != nil {
+			return n
+		}
+		var chars int
+		chars, err = unitToChars(n, opts.LengthUnit, charset)
+		if opts.LengthUnit == EntropyBytes && opts.InstanceLabel != "" {
+			fp := opts.FingerprintChars
+			if fp == 0 {
+				fp = 1
+			}
+			chars += fp
+		}
+		return chars
+	}
+	opts.Length = convert(opts.Length)
+	if opts.MaxLength > 0 {
+		opts.MinLength = convert(opts.MinLength)
+		opts.MaxLength = convert(opts.MaxLength)
+	}
+	opts.LengthUnit = Characters
+	return opts, err
+}
+
+// unitToChars returns the chars needed for the length n in unit.
+func unitToChars(n int, unit LengthUnit, charset []byte) (int, error) {
+	var chars int
+	switch unit {
+	case Characters:
+		chars = n
+	case EntropyBytes:
+		bits := BitsPerChar(Charset(charset))
+		if bits == 0 {
+			return 0, newError(CodeCharsetSize, "uriuniq: charset has no entropy")
+		}
+		chars = int(math.Ceil(float64(8*n) / bits))
+	case EncodedWidth:
+		chars = n / maxEncodedCharWidth(charset)
+	default:
+		return 0, errorf(CodeInvalidArgument, "uriuniq: unknown length unit %d", int(unit))
+	}
+	if chars < 1 {
+		return 0, errorf(CodeInvalidLength, "uriuniq: length %d %s is too small", n, unit)
+	}
+	return chars, nil
+}
+
+// maxEncodedCharWidth returns the largest width of a char of charset once
+// percent-encoded.
+func maxEncodedCharWidth(charset []byte) int {
+	if isURISafe(string(charset)) {
+		return 1
+	}
+	return 3
+}
@@ -0,0 +1,80 @@
+package uriuniq
+
+import "testing"
+
+// TestLengthUnit checks the length of strings for every unit.
+func TestLengthUnit(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     LengthUnit
+		length   int
+		charset  Charset
+		expected int
+	}{
+		{"Characters", Characters, 16, "", 16},
+		{"128 Bits Base62", EntropyBytes, 16, "", 22},
+		{"128 Bits Hex", EntropyBytes, 16, "0123456789abcdef", 32},
+		{"Width URI-Safe", EncodedWidth, 20, "", 20},
+		{"Width Unsafe", EncodedWidth, 20, "ab/", 6},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := NewOpts()
+			opts.Length = tc.length
+			opts.LengthUnit = tc.unit
+			opts.CustomCharset = tc.charset
+			result, err := Generate(opts)
+			if err != nil {
+				t.Fatalf("Generate failed: %s", err)
+			}
+			if len(result) != tc.expected {
+				t.Errorf("Expected %d chars, got %d", tc.expected, len(result))
+			}
+			if p, _ := Pattern(opts); !p.MatchString(result) {
+				t.Errorf("Pattern %s does not match %q", p, result)
+			}
+		})
+	}
+
+	opts := NewOpts()
+	opts.LengthUnit = EntropyBytes
+	opts.Length = 16
+	if bits := Entropy(opts); bits < 128 {
+		t.Errorf("Expected at least 128 bits, got %f", bits)
+	}
+	opts.LengthUnit = EncodedWidth
+	opts.CustomCharset = "ab/"
+	opts.Length = 2
+	if _, err := Generate(opts); err == nil {
+		t.Errorf("Expected error for width below one char")
+	}
+}
+
+// TestConvertLength checks conversions between units for presets.
+func TestConvertLength(t *testing.T) {
+	tests := []struct {
+		n        int
+		from, to LengthUnit
+		charset  Charset
+		expected int
+	}{
+		{16, EntropyBytes, Characters, "", 22},
+		{22, Characters, EntropyBytes, "", 16},
+		{16, EntropyBytes, Characters, Numeric, 39},
+		{16, EntropyBytes, Characters, Lowercase, 28},
+		{10, Characters, EncodedWidth, "ab/", 30},
+		{30, EncodedWidth, Characters, "ab/", 10},
+		{8, EncodedWidth, EntropyBytes, "", 5},
+	}
+	for _, tc := range tests {
+		opts := NewOpts()
+		opts.CustomCharset = tc.charset
+		got, err := ConvertLength(tc.n, tc.from, tc.to, opts)
+		if err != nil || got != tc.expected {
+			t.Errorf("ConvertLength(%d, %s, %s, %q) = %d, %v, expected %d", tc.n, tc.from, tc.to, tc.charset, got, err, tc.expected)
+		}
+	}
+	if _, err := ConvertLength(1, LengthUnit(9), Characters, NewOpts()); err == nil {
+		t.Errorf("Expected error for unknown unit")
+	}
+}
@@ -0,0 +1,48 @@
+package uriuniq
+
+import "context"
+
+// ErrClosed is returned by a Pool or Stream after Close or Drain.
+var ErrClosed = newError(CodeClosed, "uriuniq: closed")
+
+// Drainer is implemented by types that hold pre-generated IDs or run
+// background goroutines, such as Pool and Stream. After Close or Drain
+// returns, they read no more entropy, own no goroutines and fail every
+// further call with ErrClosed.
+type Drainer interface {
+	// Close stops at once. Pre-generated IDs are dropped unserved, so
+	// uniqueness is kept.
+	Close() error
+	// Drain stops like Close but returns the pre-generated IDs that were
+	// never served, so they can be used or persisted. It waits for
+	// background work until ctx is done.
+	Drain(ctx context.Context) ([]string, error)
+}
+
+// Close implements Drainer. It also discards the entropy the Generator of
+// p has read ahead.
+func (p *Pool) Close() error {
+	_, err := p.Drain(context.Background())
+	return err
+}
+
+// Drain implements Drainer. A Pool owns no goroutines, so Drain never
+// waits.
+func (p *Pool) Drain(ctx context.Context) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, nil
+	}
+	p.closed = true
+	ids := p.reserve
+	p.reserve = nil
+	p.g.Discard()
+	return ids, ctx.Err()
+}
+
+func (p *Pool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
@@ -0,0 +1,68 @@
+package uriuniq
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInputTooLong is returned when an input exceeds Limits.MaxInputLength.
+var ErrInputTooLong = newError(CodeInputTooLong, "uriuniq: input too long")
+
+// DefaultMaxInputLength is the default max input length accepted by parsers.
+const DefaultMaxInputLength = 1024
+
+// Limits bounds the input accepted by ParseAnyWithLimits, so hostile input
+// is rejected before any per-char work is done. The other parse and
+// validation functions, such as Validate, Sanitize and DetectFormat, take
+// no Limits: they reject inputs longer than DefaultMaxInputLength, or than
+// the longest ID their Options produce if that is longer, with
+// ErrInputTooLong.
+type Limits struct {
+	MaxInputLength int                 // Max input length in bytes
+	AllowedRanges  *unicode.RangeTable // Allowed chars, nil allows all
+}
+
+// NewLimits creates Limits with default settings.
+func NewLimits() Limits {
+	return Limits{MaxInputLength: DefaultMaxInputLength}
+}
+
+// check applies the limits to s. A non-positive MaxInputLength uses the default.
+func (l Limits) check(s string) error {
+	maxLen := l.MaxInputLength
+	if maxLen <= 0 {
+		maxLen = DefaultMaxInputLength
+	}
+	if err := checkInputLength(s, maxLen); err != nil {
+		return err
+	}
+	if l.AllowedRanges != nil {
+		for i, c := range s {
+			if !unicode.Is(l.AllowedRanges, c) {
+				return &ParseError{Index: i, Char: c}
+			}
+		}
+	}
+	return nil
+}
+
+// checkInputLength rejects s if it is longer than maxLen bytes.
+func checkInputLength(s string, maxLen int) error {
+	if len(s) > maxLen {
+		return fmt.Errorf("%w: %d bytes, max %d", ErrInputTooLong, len(s), maxLen)
+	}
+	return nil
+}
+
+// inputLimit returns the max length in bytes of input checked against
+// prepared opts: DefaultMaxInputLength, or the longest ID of opts, with
+// room for group separators and multi-byte chars, if that is longer.
+func inputLimit(opts Options) int {
+	_, max := lengthRange(opts)
+	n := len(idPrefix(opts)) + utf8.UTFMax*(max+checkChars(opts))*(1+len(groupSeparator(opts)))
+	if n < DefaultMaxInputLength {
+		return DefaultMaxInputLength
+	}
+	return n
+}
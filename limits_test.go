@@ -0,0 +1,69 @@
+package uriuniq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// TestLimits checks input length and range guards on parse paths.
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		limits Limits
+		err    error
+	}{
+		{"Default Within", strings.Repeat("a", DefaultMaxInputLength), NewLimits(), nil},
+		{"Default Too Long", strings.Repeat("a", DefaultMaxInputLength+1), NewLimits(), ErrInputTooLong},
+		{"Zero Uses Default", strings.Repeat("a", DefaultMaxInputLength+1), Limits{}, ErrInputTooLong},
+		{"Custom Too Long", "abcdef", Limits{MaxInputLength: 5}, ErrInputTooLong},
+		{"Allowed Ranges", "abc", Limits{AllowedRanges: unicode.Lower}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseAnyWithLimits(tc.input, tc.limits)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.err, err)
+			}
+		})
+	}
+
+	_, err := ParseAnyWithLimits("abC", Limits{AllowedRanges: unicode.Lower})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Index != 2 || perr.Char != 'C' {
+		t.Errorf("Expected ParseError at index 2, got %v", err)
+	}
+}
+
+// TestInputLengthGuards checks that the parse and validation functions
+// without Limits reject oversized input.
+func TestInputLengthGuards(t *testing.T) {
+	huge := strings.Repeat("a", 64<<10)
+	opts := NewOpts()
+	guards := map[string]func() error{
+		"Validate":         func() error { return Validate(huge, opts) },
+		"Validate Runes":   func() error { return Validate(huge, Options{Length: 4, CustomCharset: "äöü"}) },
+		"ParsePadded":      func() error { _, err := ParsePadded(huge, 16, ' ', opts); return err },
+		"ParseSpellOut":    func() error { _, err := ParseSpellOut(huge); return err },
+		"ParseNumericCode": func() error { _, err := ParseNumericCode(huge, 0); return err },
+		"Sanitize":         func() error { _, err := Sanitize(huge, opts); return err },
+		"Decode":           func() error { _, err := Decode(huge, Alphanumeric); return err },
+		"DecodeEntropy":    func() error { _, err := DecodeEntropy(huge); return err },
+		"DetectFormat":     func() error { _, err := DetectFormat(huge); return err },
+	}
+	for name, f := range guards {
+		if err := f(); !errors.Is(err, ErrInputTooLong) {
+			t.Errorf("%s: expected ErrInputTooLong, got %v", name, err)
+		}
+	}
+
+	// IDs longer than DefaultMaxInputLength remain valid for their Options.
+	long := Options{Length: 2 * DefaultMaxInputLength, GroupSize: 4}
+	g, _ := NewGenerator(long)
+	if id, _ := g.Next(); Validate(id, long) != nil {
+		t.Errorf("Expected a long ID to validate")
+	}
+}
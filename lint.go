@@ -0,0 +1,101 @@
+package uriuniq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks a Finding.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Finding is a problem reported by Lint.
+type Finding struct {
+	Severity Severity
+	Check    string // Stable name of the check, such as "weak-entropy"
+	Message  string
+}
+
+func (f Finding) String() string {
+	return f.Severity.String() + ": " + f.Check + ": " + f.Message
+}
+
+// MinLintEntropy is the entropy in bits below which Lint warns.
+const MinLintEntropy = 64
+
+// ambiguousPairs lists chars that are easily mistaken for each other when
+// read or typed by a person.
+var ambiguousPairs = []string{"0O", "0o", "1l", "1I", "Il", "5S", "2Z", "8B"}
+
+// Lint checks opts for weak or risky configurations, such as a charset
+// with duplicate or reserved URI chars or too little entropy, so ID
+// policies can be gated in code review or CI. Findings are ordered by
+// check, not severity.
+func Lint(opts Options) []Finding {
+	var findings []Finding
+	add := func(sev Severity, check, format string, args ...any) {
+		findings = append(findings, Finding{sev, check, fmt.Sprintf(format, args...)})
+	}
+
+	if opts.CustomCharset != "" {
+		var seen [256]bool
+		var dups, reserved, unsafe []byte
+		for i := 0; i < len(opts.CustomCharset); i++ {
+			c := opts.CustomCharset[i]
+			switch {
+			case seen[c]:
+				dups = append(dups, c)
+			case strings.IndexByte("!*'()", c) >= 0:
+				reserved = append(reserved, c)
+			case strings.IndexByte(string(uriSafe), c) < 0:
+				unsafe = append(unsafe, c)
+			}
+			seen[c] = true
+		}
+		if len(dups) > 0 {
+			add(SeverityError, "duplicate-chars", "charset repeats %q, which biases the output", dups)
+		}
+		if len(unsafe) > 0 {
+			add(SeverityError, "unsafe-chars", "charset has %q, which must be percent-encoded in URIs", unsafe)
+		}
+		if len(reserved) > 0 {
+			add(SeverityWarning, "reserved-chars", "charset has URI sub-delims %q, which some encoders escape", reserved)
+		}
+	}
+
+	_, charset, err := prepare(opts)
+	if err != nil {
+		add(SeverityError, "invalid-options", "%s", err)
+		return findings
+	}
+	if bits := Entropy(opts); bits < MinLintEntropy {
+		add(SeverityWarning, "weak-entropy", "%.1f bits of entropy, want at least %d", bits, MinLintEntropy)
+	}
+	var ambiguous []string
+	for _, pair := range ambiguousPairs {
+		if strings.IndexByte(string(charset), pair[0]) >= 0 && strings.IndexByte(string(charset), pair[1]) >= 0 {
+			ambiguous = append(ambiguous, pair)
+		}
+	}
+	if len(ambiguous) > 0 {
+		add(SeverityInfo, "ambiguous-chars", "charset has look-alike pairs %s, avoid it for IDs read by people", strings.Join(ambiguous, " "))
+	}
+	return findings
+}
@@ -0,0 +1,37 @@
+package uriuniq
+
+import "testing"
+
+// TestLint checks which checks fire for each configuration.
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		checks []string
+	}{
+		{"Default", Options{Length: 16}, []string{"ambiguous-chars"}},
+		{"Strong Lowercase", Options{Length: 16, ExcludeUppercase: true, ExcludeNumeric: true}, nil},
+		{"Short", Options{Length: 8, ExcludeUppercase: true, ExcludeNumeric: true}, []string{"weak-entropy"}},
+		{"Duplicates", Options{Length: 50, CustomCharset: "abca"}, []string{"duplicate-chars"}},
+		{"Reserved", Options{Length: 40, CustomCharset: "abcd!*"}, []string{"reserved-chars"}},
+		{"Unsafe", Options{Length: 40, CustomCharset: "abcd/?"}, []string{"unsafe-chars"}},
+		{"Invalid", Options{Length: 16, MinLength: 5, MaxLength: 2, ExcludeUppercase: true}, []string{"invalid-options"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := Lint(tc.opts)
+			if len(findings) != len(tc.checks) {
+				t.Fatalf("Expected checks %v, got %v", tc.checks, findings)
+			}
+			for i, f := range findings {
+				if f.Check != tc.checks[i] {
+					t.Errorf("Expected check %s, got %s", tc.checks[i], f)
+				}
+			}
+		})
+	}
+	if got := Lint(Options{Length: 50, CustomCharset: "abca"})[0]; got.Severity != SeverityError || got.String()[:6] != "error:" {
+		t.Errorf("Unexpected finding %s", got)
+	}
+}
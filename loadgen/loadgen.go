@@ -0,0 +1,191 @@
+// Package loadgen drives an ID service over HTTP at a fixed rate and
+// checks that it never hands out the same ID twice. It is the load test of
+// examples/idservice and works with any service returning one ID per
+// response.
+//
+// Example:
+//
+//	report, err := loadgen.Run(ctx, loadgen.Config{
+//	    URL:      "http://localhost:8080/ids",
+//	    QPS:      500,
+//	    Duration: time.Minute,
+//	})
+//	if err != nil {
+//	    log.Fatal(err) // Includes ErrDuplicate
+//	}
+//	fmt.Println(report)
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// Defaults of Config.
+const (
+	DefaultConcurrency = 16
+	DefaultQPS         = 100
+)
+
+// ErrDuplicate is returned by Run when the service returned an ID twice.
+var ErrDuplicate = &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: errors.New("loadgen: duplicate ID")}
+
+// Config describes a load test.
+type Config struct {
+	URL    string
+	Method string // Defaults to POST
+	// QPS is the request rate, Duration how long to send requests.
+	// Requests is an upper bound on their number; zero for none.
+	QPS      float64
+	Duration time.Duration
+	Requests int
+	// Concurrency is the number of requests in flight at most. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+	Client      *http.Client // Defaults to http.DefaultClient
+	// Extract returns the ID of a successful response. Defaults to the
+	// body with surrounding space trimmed.
+	Extract func(*http.Response) (string, error)
+}
+
+// Report is the result of Run.
+type Report struct {
+	Requests   int           // Requests sent
+	Errors     int           // Failed requests and non-2xx responses
+	Duplicates []string      // IDs returned more than once
+	Elapsed    time.Duration // Time from the first request to the last response
+	MaxLatency time.Duration
+}
+
+func (r Report) String() string {
+	qps := 0.0
+	if r.Elapsed > 0 {
+		qps = float64(r.Requests) / r.Elapsed.Seconds()
+	}
+	return fmt.Sprintf("%d requests in %s (%.0f/s), %d errors, %d duplicates, max latency %s",
+		r.Requests, r.Elapsed.Round(time.Millisecond), qps, r.Errors, len(r.Duplicates), r.MaxLatency)
+}
+
+// Run sends requests as described by cfg until cfg.Duration has passed,
+// cfg.Requests were sent or ctx is done, and waits for their responses.
+// It returns ErrDuplicate with the report if any ID was returned twice.
+func Run(ctx context.Context, cfg Config) (Report, error) {
+	if cfg.URL == "" {
+		return Report{}, &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: errors.New("loadgen: no URL")}
+	}
+	if cfg.Method == "" {
+		cfg.Method = http.MethodPost
+	}
+	if cfg.QPS <= 0 {
+		cfg.QPS = DefaultQPS
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultConcurrency
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Extract == nil {
+		cfg.Extract = readBody
+	}
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		mu     sync.Mutex
+		report Report
+		seen   = make(map[string]bool)
+	)
+	record := func(id string, err error, latency time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		if latency > report.MaxLatency {
+			report.MaxLatency = latency
+		}
+		switch {
+		case err != nil:
+			report.Errors++
+		case seen[id]:
+			report.Duplicates = append(report.Duplicates, id)
+		default:
+			seen[id] = true
+		}
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.QPS))
+	defer ticker.Stop()
+	slots := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+loop:
+	for cfg.Requests == 0 || report.Requests < cfg.Requests {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+		}
+		select {
+		case <-ctx.Done():
+			break loop
+		case slots <- struct{}{}:
+		}
+		report.Requests++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			t := time.Now()
+			// Not ctx, so the end of the test does not cancel requests in
+			// flight.
+			id, err := fetch(context.Background(), cfg)
+			record(id, err, time.Since(t))
+		}()
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	if len(report.Duplicates) > 0 {
+		return report, fmt.Errorf("%w: %d IDs, first %q", ErrDuplicate, len(report.Duplicates), report.Duplicates[0])
+	}
+	return report, nil
+}
+
+// fetch sends one request and extracts the ID from the response.
+func fetch(ctx context.Context, cfg Config) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, cfg.Method, cfg.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("loadgen: status %d", resp.StatusCode)
+	}
+	return cfg.Extract(resp)
+}
+
+// readBody returns the trimmed body of resp.
+func readBody(resp *http.Response) (string, error) {
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	id := strings.TrimSpace(string(b))
+	if id == "" {
+		return "", uriuniq.ErrEmptyInput
+	}
+	return id, nil
+}
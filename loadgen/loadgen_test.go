@@ -0,0 +1,65 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRun checks counting requests and detecting duplicates.
+func TestRun(t *testing.T) {
+	var n atomic.Int64
+	unique := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "id%d\n", n.Add(1))
+	}))
+	defer unique.Close()
+	dup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n.Add(1)%3 == 0 {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "same")
+	}))
+	defer dup.Close()
+
+	report, err := Run(context.Background(), Config{URL: unique.URL, QPS: 1000, Requests: 50})
+	if err != nil {
+		t.Fatalf("Run failed: %s", err)
+	}
+	if report.Requests != 50 || report.Errors != 0 || len(report.Duplicates) != 0 {
+		t.Errorf("Unexpected report %s", report)
+	}
+
+	n.Store(0)
+	report, err = Run(context.Background(), Config{URL: dup.URL, QPS: 1000, Requests: 30, Concurrency: 1})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("Expected ErrDuplicate, got %v", err)
+	}
+	if report.Errors != 10 || len(report.Duplicates) != 19 {
+		t.Errorf("Unexpected report %s", report)
+	}
+}
+
+// TestRunDuration checks that Run stops after Config.Duration.
+func TestRunDuration(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	defer srv.Close()
+	start := time.Now()
+	report, err := Run(context.Background(), Config{URL: srv.URL + "/x", QPS: 100, Duration: 50 * time.Millisecond})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate for a constant ID, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second || report.Requests == 0 {
+		t.Errorf("Unexpected %d requests in %s", report.Requests, elapsed)
+	}
+	if _, err := Run(context.Background(), Config{}); err == nil {
+		t.Errorf("Expected error without URL")
+	}
+}
@@ -0,0 +1,63 @@
+package uriuniq
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRUChecker is a UniquenessChecker in memory that keeps only the most
+// recently recorded or seen IDs, up to a capacity, so memory stays bounded
+// in long-running processes. IDs evicted from it are no longer reported as
+// taken: use it where duplicates only matter within a recent window, such
+// as deduplicating retried requests, or as a cache in front of a durable
+// checker.
+type LRUChecker struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Most recent first, of strings
+	index    map[string]*list.Element
+}
+
+// NewLRUChecker creates an empty LRUChecker holding up to capacity IDs.
+func NewLRUChecker(capacity int) (*LRUChecker, error) {
+	if capacity <= 0 {
+		return nil, newError(CodeInvalidArgument, "uriuniq: LRU capacity must be positive")
+	}
+	return &LRUChecker{capacity: capacity, order: list.New(), index: make(map[string]*list.Element)}, nil
+}
+
+// Seen implements UniquenessChecker. A seen ID becomes the most recent.
+func (c *LRUChecker) Seen(id string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.index[id]
+	if ok {
+		c.order.MoveToFront(e)
+	}
+	return ok, nil
+}
+
+// Record implements UniquenessChecker, evicting the least recent ID once
+// the capacity is reached.
+func (c *LRUChecker) Record(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.index[id]; ok {
+		c.order.MoveToFront(e)
+		return nil
+	}
+	c.index[id] = c.order.PushFront(id)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.index, oldest.Value.(string))
+	}
+	return nil
+}
+
+// Len returns the number of IDs held.
+func (c *LRUChecker) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
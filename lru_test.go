@@ -0,0 +1,38 @@
+package uriuniq
+
+import "testing"
+
+// TestLRUChecker checks eviction order and use with GenerateUnique.
+func TestLRUChecker(t *testing.T) {
+	c, err := NewLRUChecker(2)
+	if err != nil {
+		t.Fatalf("NewLRUChecker failed: %s", err)
+	}
+	c.Record("a")
+	c.Record("b")
+	c.Seen("a") // b is now the least recent
+	c.Record("c")
+	for id, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if seen, _ := c.Seen(id); seen != want {
+			t.Errorf("Seen(%q) = %t, expected %t", id, seen, want)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 IDs, got %d", c.Len())
+	}
+
+	// All 10 numeric codes of length 1 fit, so the 11th call exhausts.
+	g, _ := NewGenerator(Options{Length: 1, CustomCharset: Numeric})
+	c, _ = NewLRUChecker(10)
+	for i := 0; i < 10; i++ {
+		if _, err := GenerateUnique(g, c, 1000); err != nil {
+			t.Fatalf("GenerateUnique failed: %s", err)
+		}
+	}
+	if _, err := GenerateUnique(g, c, 50); err != ErrMaxRetriesExceeded {
+		t.Errorf("Expected ErrMaxRetriesExceeded, got %v", err)
+	}
+	if _, err := NewLRUChecker(0); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+}
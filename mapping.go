@@ -0,0 +1,61 @@
+package uriuniq
+
+// chunkedMinLength is the output length from which randFill maps entropy
+// with a charTable, eight bytes at a time. Below it, building the table
+// costs more than it saves.
+const chunkedMinLength = 256
+
+// charTable maps an entropy byte straight to its char, replacing the
+// modulo in the per-byte loop.
+type charTable struct {
+	chars   [256]byte
+	maxByte byte // Bytes above maxByte are rejected
+}
+
+func newCharTable(charset []byte, maxByte byte) *charTable {
+	t := &charTable{maxByte: maxByte}
+	for b := 0; b <= int(maxByte); b++ {
+		t.chars[b] = charset[b%len(charset)]
+	}
+	return t
+}
+
+// mapChunked maps src into dst, skipping rejected bytes, and returns the
+// number of bytes written to dst and read from src. Runs of eight accepted bytes are mapped
+// together, which is the common case: at most half of all bytes, and none
+// for charsets dividing 256, are rejected.
+func (t *charTable) mapChunked(dst, src []byte) (filled, consumed int) {
+	maxByte := t.maxByte
+	for consumed+8 <= len(src) && filled+8 <= len(dst) {
+		b := src[consumed : consumed+8 : consumed+8]
+		if b[0] <= maxByte && b[1] <= maxByte && b[2] <= maxByte && b[3] <= maxByte &&
+			b[4] <= maxByte && b[5] <= maxByte && b[6] <= maxByte && b[7] <= maxByte {
+			d := dst[filled : filled+8 : filled+8]
+			d[0] = t.chars[b[0]]
+			d[1] = t.chars[b[1]]
+			d[2] = t.chars[b[2]]
+			d[3] = t.chars[b[3]]
+			d[4] = t.chars[b[4]]
+			d[5] = t.chars[b[5]]
+			d[6] = t.chars[b[6]]
+			d[7] = t.chars[b[7]]
+			filled += 8
+			consumed += 8
+			continue
+		}
+		for _, c := range b {
+			consumed++
+			if c <= maxByte {
+				dst[filled] = t.chars[c]
+				filled++
+			}
+		}
+	}
+	for ; consumed < len(src) && filled < len(dst); consumed++ {
+		if c := src[consumed]; c <= maxByte {
+			dst[filled] = t.chars[c]
+			filled++
+		}
+	}
+	return filled, consumed
+}
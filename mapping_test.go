@@ -0,0 +1,66 @@
+package uriuniq
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+// mapScalar is the reference per-byte mapping used by randFill.
+func mapScalar(dst, src, charset []byte, maxByte byte) (filled, consumed int) {
+	for ; consumed < len(src) && filled < len(dst); consumed++ {
+		if src[consumed] <= maxByte {
+			dst[filled] = charset[int(src[consumed])%len(charset)]
+			filled++
+		}
+	}
+	return filled, consumed
+}
+
+// TestMapChunked checks the chunked mapping against the scalar loop.
+func TestMapChunked(t *testing.T) {
+	src := make([]byte, MaxBuffLength)
+	if _, err := rand.Read(src); err != nil {
+		t.Fatal(err)
+	}
+	for _, charset := range []Charset{Alphanumeric, Numeric, "ab", Lowercase + Numeric + "-_"} {
+		maxByte := byte(255 - (256 % len(charset)))
+		table := newCharTable([]byte(charset), maxByte)
+		for _, dstLen := range []int{0, 1, 7, 8, 9, 300, 1024, MaxBuffLength} {
+			for _, srcLen := range []int{0, 5, 8, 17, MaxBuffLength} {
+				want := make([]byte, dstLen)
+				got := make([]byte, dstLen)
+				nWant, cWant := mapScalar(want, src[:srcLen], []byte(charset), maxByte)
+				nGot, cGot := table.mapChunked(got, src[:srcLen])
+				if nWant != nGot || cWant != cGot || !bytes.Equal(want, got) {
+					t.Fatalf("%q dst %d src %d: expected %d/%d, got %d/%d", charset, dstLen, srcLen, nWant, cWant, nGot, cGot)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkMapChunked benchmarks mapping a full entropy buffer.
+func BenchmarkMapChunked(b *testing.B) {
+	src := make([]byte, MaxBuffLength)
+	_, _ = rand.Read(src)
+	dst := make([]byte, MaxBuffLength)
+	maxByte := byte(255 - (256 % len(Alphanumeric)))
+	table := newCharTable([]byte(Alphanumeric), maxByte)
+	b.SetBytes(MaxBuffLength)
+	for i := 0; i < b.N; i++ {
+		table.mapChunked(dst, src)
+	}
+}
+
+// BenchmarkMapScalar benchmarks the per-byte loop for comparison.
+func BenchmarkMapScalar(b *testing.B) {
+	src := make([]byte, MaxBuffLength)
+	_, _ = rand.Read(src)
+	dst := make([]byte, MaxBuffLength)
+	maxByte := byte(255 - (256 % len(Alphanumeric)))
+	b.SetBytes(MaxBuffLength)
+	for i := 0; i < b.N; i++ {
+		mapScalar(dst, src, []byte(Alphanumeric), maxByte)
+	}
+}
@@ -0,0 +1,102 @@
+package uriuniq
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// MerkleProof proves that an ID is part of a batch committed with Commit,
+// without revealing the other IDs of the batch.
+type MerkleProof struct {
+	Index int      // Position of the ID in the batch
+	Size  int      // Number of IDs in the batch
+	Path  [][]byte // Sibling hashes from the leaf up to the root
+}
+
+// Commit builds a Merkle tree over ids, such as a batch of coupon or
+// license codes, and returns its root and an inclusion proof for each ID.
+// Publish or sign the root when the batch is authorized; a redeemed code
+// can later be shown to be part of the batch with VerifyInclusion and its
+// proof.
+//
+// The tree is that of RFC 9162 over SHA-256, with the IDs as leaf data, so
+// proofs can be checked with other implementations.
+func Commit(ids []string) (root []byte, proofs []MerkleProof) {
+	if len(ids) == 0 {
+		sum := sha256.Sum256(nil)
+		return sum[:], nil
+	}
+	leaves := make([][]byte, len(ids))
+	proofs = make([]MerkleProof, len(ids))
+	for i, id := range ids {
+		leaves[i] = leafHash(id)
+		proofs[i] = MerkleProof{Index: i, Size: len(ids)}
+	}
+	return merkleRoot(leaves, proofs), proofs
+}
+
+// merkleRoot returns the root of the tree over leaves and appends the
+// sibling hashes to the paths of proofs, which matches leaves.
+func merkleRoot(leaves [][]byte, proofs []MerkleProof) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := 1
+	for k*2 < len(leaves) {
+		k *= 2
+	}
+	left := merkleRoot(leaves[:k], proofs[:k])
+	right := merkleRoot(leaves[k:], proofs[k:])
+	for i := range proofs {
+		if i < k {
+			proofs[i].Path = append(proofs[i].Path, right)
+		} else {
+			proofs[i].Path = append(proofs[i].Path, left)
+		}
+	}
+	return nodeHash(left, right)
+}
+
+// VerifyInclusion reports whether proof shows that id is part of the batch
+// committed to root.
+func VerifyInclusion(root []byte, id string, proof MerkleProof) bool {
+	if proof.Index < 0 || proof.Index >= proof.Size {
+		return false
+	}
+	fn, sn := proof.Index, proof.Size-1
+	r := leafHash(id)
+	for _, p := range proof.Path {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && subtle.ConstantTimeCompare(r, root) == 1
+}
+
+// leafHash returns the hash of the leaf for id.
+func leafHash(id string) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	h.Write([]byte(id))
+	return h.Sum(nil)
+}
+
+// nodeHash returns the hash of the inner node over left and right.
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,56 @@
+package uriuniq
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// TestCommit checks that every proof verifies for its own ID only, for
+// batch sizes that do and do not fill a tree.
+func TestCommit(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			ids := make([]string, n)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("code%d", i)
+			}
+			root, proofs := Commit(ids)
+			for i, proof := range proofs {
+				if !VerifyInclusion(root, ids[i], proof) {
+					t.Errorf("Proof %d does not verify", i)
+				}
+				if VerifyInclusion(root, "other", proof) {
+					t.Errorf("Proof %d verifies another ID", i)
+				}
+				moved := proof
+				moved.Index = (i + 1) % n
+				if n > 1 && VerifyInclusion(root, ids[i], moved) {
+					t.Errorf("Proof %d verifies at index %d", i, moved.Index)
+				}
+			}
+		})
+	}
+}
+
+// TestCommitRFC9162 checks the root against the tree hash of RFC 9162:
+// for a single leaf it is SHA-256(0x00 || id), and for two leaves
+// SHA-256(0x01 || leaf0 || leaf1).
+func TestCommitRFC9162(t *testing.T) {
+	root, _ := Commit([]string{""})
+	if got := hex.EncodeToString(root); got != "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d" {
+		t.Errorf("Unexpected leaf hash %s", got)
+	}
+	root, _ = Commit(nil)
+	if got := hex.EncodeToString(root); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("Unexpected empty root %s", got)
+	}
+	root, _ = Commit([]string{"a", "b"})
+	if !bytes.Equal(root, nodeHash(leafHash("a"), leafHash("b"))) {
+		t.Errorf("Unexpected root for two leaves")
+	}
+	if VerifyInclusion(root, "a", MerkleProof{Index: 0, Size: 2}) {
+		t.Errorf("Expected a proof without path to fail")
+	}
+}
@@ -0,0 +1,108 @@
+package uriuniq
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Metrics receives instrumentation events of Generate and Generators, set
+// as Options.Metrics. Its methods are called on the hot path, possibly
+// from many goroutines at once, so they must be quick and safe for
+// concurrent use. Counters is a ready implementation.
+type Metrics interface {
+	// Generated is called for every string issued.
+	Generated()
+	// Sampled is called after chars were drawn from entropy, with the
+	// entropy bytes consumed, of which rejected were discarded by the
+	// rejection sampler, and retries, the reads beyond the first. A high
+	// rejection rate or many retries point at a charset size far from a
+	// power of 2 or a failing EntropySource.
+	Sampled(consumed, rejected, retries int)
+}
+
+// Counters is a Metrics counting events atomically. Its String method
+// returns JSON, so it can be published with expvar.Publish as is, and
+// WritePrometheus writes the Prometheus text format.
+type Counters struct {
+	generated atomic.Uint64
+	consumed  atomic.Uint64
+	rejected  atomic.Uint64
+	retries   atomic.Uint64
+}
+
+// CountersSnapshot holds the values of Counters at one time.
+type CountersSnapshot struct {
+	Generated     uint64 // Strings issued
+	EntropyBytes  uint64 // Entropy bytes consumed
+	RejectedBytes uint64 // Entropy bytes discarded by rejection sampling
+	Retries       uint64 // Entropy reads beyond the first of a draw
+}
+
+// RejectionRate returns the fraction of consumed entropy bytes that were
+// rejected, or 0 if none were consumed.
+func (s CountersSnapshot) RejectionRate() float64 {
+	if s.EntropyBytes == 0 {
+		return 0
+	}
+	return float64(s.RejectedBytes) / float64(s.EntropyBytes)
+}
+
+// Generated implements Metrics.
+func (c *Counters) Generated() {
+	c.generated.Add(1)
+}
+
+// Sampled implements Metrics.
+func (c *Counters) Sampled(consumed, rejected, retries int) {
+	c.consumed.Add(uint64(consumed))
+	c.rejected.Add(uint64(rejected))
+	c.retries.Add(uint64(retries))
+}
+
+// Snapshot returns the current values of c.
+func (c *Counters) Snapshot() CountersSnapshot {
+	return CountersSnapshot{
+		Generated:     c.generated.Load(),
+		EntropyBytes:  c.consumed.Load(),
+		RejectedBytes: c.rejected.Load(),
+		Retries:       c.retries.Load(),
+	}
+}
+
+// String returns the values of c as a JSON object, implementing
+// expvar.Var.
+func (c *Counters) String() string {
+	s := c.Snapshot()
+	return fmt.Sprintf(`{"generated": %d, "entropy_bytes": %d, "rejected_bytes": %d, "retries": %d, "rejection_rate": %g}`,
+		s.Generated, s.EntropyBytes, s.RejectedBytes, s.Retries, s.RejectionRate())
+}
+
+// WritePrometheus writes the values of c as Prometheus counters named
+// after namespace, such as uriuniq_generated_total, in the text
+// exposition format.
+func (c *Counters) WritePrometheus(w io.Writer, namespace string) error {
+	s := c.Snapshot()
+	for _, m := range []struct {
+		name, help string
+		value      uint64
+	}{
+		{"generated_total", "Strings issued.", s.Generated},
+		{"entropy_bytes_total", "Entropy bytes consumed.", s.EntropyBytes},
+		{"rejected_bytes_total", "Entropy bytes discarded by rejection sampling.", s.RejectedBytes},
+		{"entropy_retries_total", "Entropy reads beyond the first of a draw.", s.Retries},
+	} {
+		name := namespace + "_" + m.name
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, m.help, name, name, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countGenerated reports an issued string to the Metrics of opts.
+func countGenerated(opts Options) {
+	if opts.Metrics != nil {
+		opts.Metrics.Generated()
+	}
+}
@@ -0,0 +1,68 @@
+package uriuniq
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestCounters checks the events counted for Generate and Generators.
+func TestCounters(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(opts Options) error
+	}{
+		{"Generate", func(opts Options) error {
+			for i := 0; i < 3; i++ {
+				if _, err := Generate(opts); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"Generator", func(opts Options) error {
+			opts.EntropyBufferSize = -1
+			g, err := NewGenerator(opts)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < 3; i++ {
+				if _, err := g.Next(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Counters{}
+			// 62 chars accept bytes up to 247, so every other byte is
+			// rejected.
+			src := bytes.NewReader(bytes.Repeat([]byte{250, 1}, 100))
+			opts := Options{Length: 8, EntropySource: src, Metrics: c}
+			if err := tc.run(opts); err != nil {
+				t.Fatalf("Generating failed: %s", err)
+			}
+			s := c.Snapshot()
+			if s.Generated != 3 || s.EntropyBytes-s.RejectedBytes != 24 || s.RejectedBytes < 21 {
+				t.Errorf("Unexpected counters %+v", s)
+			}
+			if rate := s.RejectionRate(); rate < 0.45 || rate > 0.55 {
+				t.Errorf("Unexpected rejection rate %g", rate)
+			}
+		})
+	}
+
+	c := &Counters{}
+	c.Sampled(10, 2, 1)
+	var doc map[string]float64
+	if err := json.Unmarshal([]byte(c.String()), &doc); err != nil || doc["retries"] != 1 || doc["rejection_rate"] != 0.2 {
+		t.Errorf("Unexpected expvar output %s, %v", c.String(), err)
+	}
+	var buf strings.Builder
+	if err := c.WritePrometheus(&buf, "uriuniq"); err != nil || !strings.Contains(buf.String(), "\nuriuniq_rejected_bytes_total 2\n") {
+		t.Errorf("Unexpected Prometheus output %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,92 @@
+package uriuniq
+
+import (
+	"sync"
+	"time"
+)
+
+// ErrMonotonicOverflow is returned by Monotonic.Next when the random part
+// of the previous ID has no successor within its millisecond.
+var ErrMonotonicOverflow = newError(CodeOverflow, "uriuniq: monotonic random part overflows")
+
+// Monotonic creates sortable IDs, as GenerateSortable does, in strictly
+// increasing order within a process, as the monotonic mode of ULID does:
+// an ID of the same millisecond as the previous one, or of an earlier one
+// after the clock stepped back, keeps its timestamp and increments its
+// random part by one in the byte order of the charset. The random part is
+// drawn afresh every new millisecond. A Monotonic is safe for concurrent
+// use.
+type Monotonic struct {
+	layout sortableLayout
+	index  [256]int // Position of each char in layout.digits
+	now    func() time.Time
+
+	mu   sync.Mutex
+	ms   int64
+	last []byte // Random part of the previous ID
+}
+
+// NewMonotonic creates a Monotonic using opts. Options.Blocklist and the
+// composition of Options.MinDigits and the like are not supported, as an
+// increment could break them.
+func NewMonotonic(opts Options) (*Monotonic, error) {
+	l, err := newSortableLayout(opts)
+	if err != nil {
+		return nil, err
+	}
+	if l.random.Blocklist != nil || composed(l.random) {
+		return nil, newError(CodeInvalidArgument, "uriuniq: monotonic IDs cannot use a blocklist or composition")
+	}
+	m := &Monotonic{layout: l, now: time.Now}
+	for i, c := range l.digits {
+		m.index[c] = i
+	}
+	return m, nil
+}
+
+// Next creates the next ID.
+func (m *Monotonic) Next() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ms, err := m.layout.millis(m.now())
+	if err != nil {
+		return "", err
+	}
+	if m.last != nil && ms <= m.ms {
+		next := append([]byte(nil), m.last...)
+		if !m.increment(next[fixedChars(m.layout.random):]) {
+			return "", ErrMonotonicOverflow
+		}
+		m.setLast(next)
+		return m.layout.id(m.ms, next), nil
+	}
+	output, err := generate(m.layout.random)
+	if err != nil {
+		return "", err
+	}
+	m.ms = ms
+	m.setLast(output)
+	return m.layout.id(ms, output), nil
+}
+
+// increment adds one to the big-endian number b in the digits order and
+// reports whether it did not overflow.
+func (m *Monotonic) increment(b []byte) bool {
+	digits := m.layout.digits
+	for i := len(b) - 1; i >= 0; i-- {
+		if d := m.index[b[i]] + 1; d < len(digits) {
+			b[i] = digits[d]
+			return true
+		}
+		b[i] = digits[0]
+	}
+	return false
+}
+
+// setLast replaces the random part of the previous ID.
+func (m *Monotonic) setLast(b []byte) {
+	if m.layout.opts.Sensitive && m.last != nil {
+		wipe(m.last)
+	}
+	m.last = b
+}
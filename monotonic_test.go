@@ -0,0 +1,63 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestMonotonic checks that IDs strictly increase within a millisecond and
+// when the clock steps back, and that the random part overflows cleanly.
+func TestMonotonic(t *testing.T) {
+	m, err := NewMonotonic(Options{Length: 16, Prefix: "evt", CheckChar: true})
+	if err != nil {
+		t.Fatalf("NewMonotonic failed: %s", err)
+	}
+	now := time.UnixMilli(1700000000000)
+	m.now = func() time.Time { return now }
+	prev := ""
+	for i := 0; i < 1000; i++ {
+		switch i {
+		case 300:
+			now = now.Add(-time.Second) // Clock steps back
+		case 600:
+			now = now.Add(time.Hour)
+		}
+		id, err := m.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+		if id <= prev {
+			t.Fatalf("ID %d: %q does not sort after %q", i, id, prev)
+		}
+		if err := Validate(id, Options{Length: 16, Prefix: "evt", CheckChar: true}); err != nil {
+			t.Fatalf("Validate(%q) failed: %s", id, err)
+		}
+		prev = id
+	}
+	if ts, err := SortableTime(prev, Options{Length: 16, Prefix: "evt"}); err != nil || !ts.Equal(now) {
+		t.Errorf("Expected time %s, got %s, %v", now, ts, err)
+	}
+
+	// Two random chars of "ab" have at most three successors.
+	m, err = NewMonotonic(Options{Length: 50, CustomCharset: "ab"})
+	if err != nil {
+		t.Fatalf("NewMonotonic failed: %s", err)
+	}
+	m.now = func() time.Time { return now }
+	for i := 0; ; i++ {
+		if _, err := m.Next(); errors.Is(err, ErrMonotonicOverflow) {
+			break
+		} else if err != nil || i > 4 {
+			t.Fatalf("Expected overflow, got %v after %d IDs", err, i)
+		}
+	}
+	now = now.Add(time.Millisecond)
+	if _, err := m.Next(); err != nil {
+		t.Errorf("Expected a fresh ID in the next millisecond, got %v", err)
+	}
+
+	if _, err := NewMonotonic(Options{Length: 16, MinDigits: 1}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument for composition, got %v", err)
+	}
+}
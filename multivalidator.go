@@ -0,0 +1,42 @@
+package uriuniq
+
+import (
+	"regexp"
+)
+
+// ErrNoFormatMatch is returned when an ID matches none of the formats of a
+// MultiValidator.
+var ErrNoFormatMatch = newError(CodeNoFormatMatch, "uriuniq: no format matches")
+
+// MultiValidator validates IDs against several formats at once, for
+// migrations where old and new IDs must both be accepted for a while.
+// It is safe for concurrent use.
+type MultiValidator struct {
+	formats  []Format
+	patterns []*regexp.Regexp
+}
+
+// NewMultiValidator creates a MultiValidator accepting any of formats. Use
+// OptionsFormat to add formats defined by Options.
+func NewMultiValidator(formats ...Format) (*MultiValidator, error) {
+	m := &MultiValidator{formats: formats}
+	for _, f := range formats {
+		re, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			return nil, errorf(CodeInvalidArgument, "uriuniq: format %q: %w", f.Name, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// Match returns the first format, in the order given to NewMultiValidator,
+// that id is valid for.
+func (m *MultiValidator) Match(id string) (Format, error) {
+	for i, f := range m.formats {
+		if len(id) >= f.MinLength && len(id) <= f.MaxLength && m.patterns[i].MatchString(id) {
+			return f, nil
+		}
+	}
+	return Format{}, ErrNoFormatMatch
+}
@@ -0,0 +1,52 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestMultiValidator checks that IDs are matched to the right format.
+func TestMultiValidator(t *testing.T) {
+	legacy := NewOpts()
+	legacy.Length = 12
+	legacy.ExcludeUppercase = true
+	legacyFormat, err := OptionsFormat("legacy", legacy)
+	if err != nil {
+		t.Fatalf("OptionsFormat failed: %s", err)
+	}
+	m, err := NewMultiValidator(legacyFormat, FormatULID, FormatUUIDv7)
+	if err != nil {
+		t.Fatalf("NewMultiValidator failed: %s", err)
+	}
+
+	legacyID, err := Generate(legacy)
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{legacyID, "legacy"},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "ulid"},
+		{"01890a5d-ac96-774b-bcce-b302099a8057", "uuidv7"},
+		{"01ARZ3NDEKTs", ""},
+		{"", ""},
+	}
+	for _, tc := range tests {
+		f, err := m.Match(tc.id)
+		if tc.expected == "" {
+			if !errors.Is(err, ErrNoFormatMatch) {
+				t.Errorf("%q: expected ErrNoFormatMatch, got %v", tc.id, err)
+			}
+			continue
+		}
+		if err != nil || f.Name != tc.expected {
+			t.Errorf("%q: expected %s, got %s, %v", tc.id, tc.expected, f.Name, err)
+		}
+	}
+
+	if _, err := NewMultiValidator(Format{Name: "bad", Pattern: "("}); err == nil {
+		t.Errorf("Expected error for invalid pattern")
+	}
+}
@@ -0,0 +1,19 @@
+package uriuniq
+
+// NanoIDCharset is the default alphabet of NanoID, in its order: the 64
+// chars of Base64URL, so IDs include '-' and '_'. 6 bits each.
+const NanoIDCharset Charset = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+
+// NanoIDLength is the default length of NanoID, for about 126 bits of
+// entropy.
+const NanoIDLength = 21
+
+// NewNanoIDOpts creates Options for IDs like those of NanoID's default
+// nanoid(), 21 chars of NanoIDCharset, so they mix with IDs minted by its
+// JavaScript implementation: each validates as the other.
+func NewNanoIDOpts() Options {
+	opts := NewOpts()
+	opts.Length = NanoIDLength
+	opts.CustomCharset = NanoIDCharset
+	return opts
+}
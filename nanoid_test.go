@@ -0,0 +1,28 @@
+package uriuniq
+
+import "testing"
+
+// TestNanoID checks that the preset creates and accepts NanoID IDs.
+func TestNanoID(t *testing.T) {
+	g, err := NewGenerator(NewNanoIDOpts())
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	id, err := g.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %s", err)
+	}
+	if len(id) != 21 || checkCharset(id, Base64URL) != nil {
+		t.Errorf("Unexpected ID %q", id)
+	}
+	// The example ID of the NanoID README.
+	if err := Validate("V1StGXR8_Z5jdHi6B-myT", NewNanoIDOpts()); err != nil {
+		t.Errorf("Validate failed: %s", err)
+	}
+	if bits := Entropy(NewNanoIDOpts()); bits != 126 {
+		t.Errorf("Expected 126 bits, got %g", bits)
+	}
+	if cs, ok := LookupCharset("nanoid"); !ok || cs != NanoIDCharset {
+		t.Errorf("Unexpected nanoid charset %q", cs)
+	}
+}
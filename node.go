@@ -0,0 +1,83 @@
+package uriuniq
+
+import "strings"
+
+// checkNode checks the node of prepared opts against charset: its chars
+// must hold Node and leave room for random chars.
+func checkNode(opts Options, charset []byte) error {
+	if opts.NodeChars == 0 {
+		if opts.Node != 0 {
+			return newError(CodeInvalidArgument, "uriuniq: Node without NodeChars")
+		}
+		return nil
+	}
+	if opts.NodeChars < 0 {
+		return newError(CodeInvalidLength, "uriuniq: negative node length")
+	}
+	if opts.InstanceLabel != "" {
+		return newError(CodeInvalidArgument, "uriuniq: Node and InstanceLabel are exclusive")
+	}
+	if min, _ := lengthRange(opts); min <= opts.NodeChars {
+		return errorf(CodeInvalidLength, "uriuniq: node takes all %d chars", min)
+	}
+	capacity := 1
+	for i := 0; i < opts.NodeChars && capacity <= int(opts.Node); i++ {
+		capacity *= len(charset)
+	}
+	if capacity <= int(opts.Node) {
+		return errorf(CodeInvalidArgument, "uriuniq: node %d does not fit in %d chars", opts.Node, opts.NodeChars)
+	}
+	return nil
+}
+
+// stampNode overwrites the first chars of output with the node of
+// prepared opts, if any, as big-endian digits in the order of charset.
+func stampNode(opts Options, charset, output []byte) {
+	node := int(opts.Node)
+	for i := opts.NodeChars - 1; i >= 0; i-- {
+		output[i] = charset[node%len(charset)]
+		node /= len(charset)
+	}
+}
+
+// NodeOf returns the node embedded in an id generated with opts, which
+// must set NodeChars, for routing an ID to the node or shard that minted
+// it. The Node of opts is ignored.
+func NodeOf(id string, opts Options) (uint16, error) {
+	if opts.NodeChars <= 0 {
+		return 0, newError(CodeInvalidArgument, "uriuniq: no node chars")
+	}
+	opts.Node = 0
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return 0, err
+	}
+	prefix := idPrefix(opts)
+	if !strings.HasPrefix(id, prefix) {
+		return 0, ErrMissingPrefix
+	}
+	random, err := ungroup(opts, id[len(prefix):])
+	if err != nil {
+		return 0, err
+	}
+	if len(random) < opts.NodeChars {
+		return 0, errorf(CodeInvalidLength, "uriuniq: ID shorter than %d node chars", opts.NodeChars)
+	}
+	node := 0
+	for i := 0; i < opts.NodeChars; i++ {
+		d := IndexOf(Charset(charset), random[i])
+		if d < 0 {
+			return 0, &ParseError{Index: len(prefix) + groupedIndex(opts, i), Char: rune(random[i]), Charset: Charset(charset)}
+		}
+		if node = node*len(charset) + d; node > 1<<16-1 {
+			return 0, newError(CodeInvalidField, "uriuniq: node out of range")
+		}
+	}
+	return uint16(node), nil
+}
+
+// fixedChars returns the number of chars at the start of the random part
+// of prepared opts that carry no entropy: the fingerprint and the node.
+func fixedChars(opts Options) int {
+	return fingerprintChars(opts) + opts.NodeChars
+}
@@ -0,0 +1,82 @@
+package uriuniq
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestNode checks that the node is embedded in every ID and read back by
+// NodeOf.
+func TestNode(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"Alphanumeric", Options{Length: 12, NodeChars: 2, Node: 3843}},
+		{"Hex Full Range", Options{Length: 12, NodeChars: 4, Node: 65535, CustomCharset: Hex}},
+		{"Prefix And Groups", Options{Length: 12, NodeChars: 3, Node: 77, Prefix: "ord", GroupSize: 2}},
+		{"Zero", Options{Length: 8, NodeChars: 1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := NewGenerator(tc.opts)
+			if err != nil {
+				t.Fatalf("NewGenerator failed: %s", err)
+			}
+			var first string
+			for i := 0; i < 20; i++ {
+				id, err := g.Next()
+				if err != nil {
+					t.Fatalf("Next failed: %s", err)
+				}
+				node, err := NodeOf(id, Options{Length: tc.opts.Length, NodeChars: tc.opts.NodeChars, CustomCharset: tc.opts.CustomCharset, Prefix: tc.opts.Prefix, GroupSize: tc.opts.GroupSize})
+				if err != nil || node != tc.opts.Node {
+					t.Fatalf("NodeOf(%q) = %d, %v, expected %d", id, node, err, tc.opts.Node)
+				}
+				head := strings.TrimPrefix(id, idPrefix(tc.opts))[:tc.opts.NodeChars]
+				if first == "" {
+					first = head
+				} else if head != first {
+					t.Errorf("Node chars changed from %q to %q", first, head)
+				}
+			}
+		})
+	}
+
+	if s, _ := Generate(Options{Length: 6, NodeChars: 2, Node: 0xab, CustomCharset: Hex}); !strings.HasPrefix(s, "ab") {
+		t.Errorf("Expected node 0xab as ab, got %q", s)
+	}
+	if got, want := Entropy(Options{Length: 10, NodeChars: 2}), 8*AlphanumericBits; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected %f bits, got %f", want, got)
+	}
+	var perr *ParseError
+	if _, err := NodeOf("a-cdef", Options{Length: 6, NodeChars: 2}); !errors.As(err, &perr) || perr.Index != 1 {
+		t.Errorf("Expected ParseError at 1, got %v", err)
+	}
+
+	errs := []struct {
+		name string
+		opts Options
+		code Code
+	}{
+		{"Too Large", Options{Length: 8, NodeChars: 2, Node: 3844}, CodeInvalidArgument},
+		{"No Room", Options{Length: 2, NodeChars: 2}, CodeInvalidLength},
+		{"Node Alone", Options{Length: 8, Node: 1}, CodeInvalidArgument},
+		{"With Label", Options{Length: 8, NodeChars: 1, InstanceLabel: "pod"}, CodeInvalidArgument},
+	}
+	for _, tc := range errs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.opts); CodeOf(err) != tc.code {
+				t.Errorf("Expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+	if _, err := GenerateSortable(Options{Length: 16, NodeChars: 1}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument for a sortable node, got %v", err)
+	}
+	if _, err := NodeOf("abc", Options{Length: 3}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument without NodeChars, got %v", err)
+	}
+}
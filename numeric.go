@@ -0,0 +1,64 @@
+package uriuniq
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FormatNumericCode returns a numeric code such as a PIN or one-time code
+// in groups of groupSize digits from the left, separated by spaces, as in
+// "123 456". groupSize <= 0 uses 3.
+func FormatNumericCode(code string, groupSize int) string {
+	if groupSize <= 0 {
+		groupSize = 3
+	}
+	var b strings.Builder
+	for i := 0; i < len(code); i++ {
+		if i > 0 && i%groupSize == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteByte(code[i])
+	}
+	return b.String()
+}
+
+// ParseNumericCode extracts a numeric code from user input, as pasted from
+// a message or typed on a mobile keyboard. It drops all Unicode spaces,
+// including no-break and thin spaces, zero-width spaces and dashes, and maps decimal digits
+// of any script, such as Arabic-Indic or fullwidth digits, to ASCII. If
+// length is positive, the code must have that many digits. Inputs longer
+// than DefaultMaxInputLength are rejected with ErrInputTooLong.
+func ParseNumericCode(s string, length int) (string, error) {
+	if err := checkInputLength(s, DefaultMaxInputLength); err != nil {
+		return "", err
+	}
+	var code []byte
+	for i, r := range s {
+		switch {
+		case unicode.IsSpace(r) || unicode.Is(unicode.Zs, r) || r == '\u200b' || r == '\ufeff' || unicode.Is(unicode.Pd, r):
+		case '0' <= r && r <= '9':
+			code = append(code, byte(r))
+		case unicode.Is(unicode.Nd, r):
+			code = append(code, byte('0'+digitValue(r)))
+		default:
+			return "", &ParseError{Index: i, Char: r, Charset: Numeric}
+		}
+	}
+	if len(code) == 0 {
+		return "", ErrEmptyInput
+	}
+	if length > 0 && len(code) != length {
+		return "", errorf(CodeInvalidLength, "uriuniq: code has %d digits, want %d", len(code), length)
+	}
+	return string(code), nil
+}
+
+// digitValue returns the value of the decimal digit r. Digits come in runs
+// of consecutive sets of ten, starting at zero.
+func digitValue(r rune) int {
+	start := r
+	for unicode.Is(unicode.Nd, start-1) {
+		start--
+	}
+	return int(r-start) % 10
+}
@@ -0,0 +1,55 @@
+package uriuniq
+
+import "testing"
+
+// TestFormatNumericCode checks grouping.
+func TestFormatNumericCode(t *testing.T) {
+	tests := []struct {
+		code      string
+		groupSize int
+		expected  string
+	}{
+		{"123456", 0, "123 456"},
+		{"12345678", 4, "1234 5678"},
+		{"1234567", 3, "123 456 7"},
+		{"", 3, ""},
+	}
+	for _, tc := range tests {
+		if got := FormatNumericCode(tc.code, tc.groupSize); got != tc.expected {
+			t.Errorf("FormatNumericCode(%q, %d) = %q, expected %q", tc.code, tc.groupSize, got, tc.expected)
+		}
+	}
+}
+
+// TestParseNumericCode checks separators and digits of other scripts.
+func TestParseNumericCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Plain", "123456", "123456"},
+		{"Spaces", " 123 456 ", "123456"},
+		{"No-Break Space", "123\u00a0456", "123456"},
+		{"Narrow No-Break Space", "123\u202f456", "123456"},
+		{"Dash", "123-456", "123456"},
+		{"Zero-Width Space", "123\u200b456", "123456"},
+		{"Arabic-Indic Digits", "\u0661\u0662\u0663\u0664\u0665\u0666", "123456"},
+		{"Fullwidth Digits", "\uff11\uff12\uff13 \uff14\uff15\uff16", "123456"},
+		{"Math Bold And Double-Struck Digits", "\U0001d7cf\U0001d7d0\U0001d7d1\U0001d7d9\U0001d7da\U0001d7db", "123123"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseNumericCode(tc.input, 6)
+			if err != nil || got != tc.expected {
+				t.Errorf("ParseNumericCode(%q) = %q, %v, expected %q", tc.input, got, err, tc.expected)
+			}
+		})
+	}
+
+	for _, input := range []string{"12345", "12a456", " "} {
+		if _, err := ParseNumericCode(input, 6); err == nil {
+			t.Errorf("ParseNumericCode(%q) should fail", input)
+		}
+	}
+}
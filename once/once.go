@@ -0,0 +1,177 @@
+// Package once issues single-use tokens with an expiry, such as email
+// verification or password reset links. Tokens are stored only as SHA-256
+// digests, and redeeming one marks it used atomically in the Store, so a
+// token works exactly once even under concurrent requests.
+//
+// Example:
+//
+//	issuer := &once.Issuer{Store: once.NewMemoryStore(), TTL: time.Hour}
+//	token, err := issuer.Issue(ctx)
+//	// Send token.Value to the user, later:
+//	err = issuer.Redeem(ctx, value)
+package once
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+var (
+	// ErrNotFound is returned by a Store for an unknown or used digest.
+	ErrNotFound = &uriuniq.Error{Code: uriuniq.CodeInvalidToken, Err: errors.New("once: token not found")}
+	// ErrInvalidToken is returned when redeeming an unknown or used token.
+	ErrInvalidToken = &uriuniq.Error{Code: uriuniq.CodeInvalidToken, Err: errors.New("once: invalid token")}
+	// ErrExpired is returned when redeeming a token after its expiry.
+	ErrExpired = &uriuniq.Error{Code: uriuniq.CodeTokenExpired, Err: errors.New("once: token expired")}
+)
+
+// Store persists token digests. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Save stores digest with its expiry.
+	Save(ctx context.Context, digest string, expires time.Time) error
+	// Consume atomically removes or marks digest used and returns its
+	// expiry. It returns ErrNotFound if digest is unknown or already used.
+	Consume(ctx context.Context, digest string) (expires time.Time, err error)
+}
+
+// Token is an issued token. Only Value must be sent to the user.
+type Token struct {
+	Value   string
+	Expires time.Time
+}
+
+// Issuer issues and redeems tokens.
+type Issuer struct {
+	Store   Store
+	TTL     time.Duration    // Validity of a token, defaults to 24 hours
+	Options *uriuniq.Options // Token format, defaults to 32 alphanumeric chars
+	Now     func() time.Time // Defaults to time.Now
+}
+
+// Issue creates a token and saves its digest.
+func (i *Issuer) Issue(ctx context.Context) (Token, error) {
+	opts := uriuniq.NewOpts()
+	opts.Length = 32
+	if i.Options != nil {
+		opts = *i.Options
+	}
+	opts.EntropyBufferSize = -1
+	g, err := uriuniq.NewGenerator(opts)
+	if err != nil {
+		return Token{}, err
+	}
+	value, err := g.Next()
+	if err != nil {
+		return Token{}, err
+	}
+	ttl := i.TTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	token := Token{Value: value, Expires: i.now().Add(ttl)}
+	if err := i.Store.Save(ctx, Digest(value), token.Expires); err != nil {
+		return Token{}, err
+	}
+	return token, nil
+}
+
+// Redeem consumes the token value. It succeeds at most once per token.
+func (i *Issuer) Redeem(ctx context.Context, value string) error {
+	expires, err := i.Store.Consume(ctx, Digest(value))
+	if errors.Is(err, ErrNotFound) {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if !i.now().Before(expires) {
+		return ErrExpired
+	}
+	return nil
+}
+
+func (i *Issuer) now() time.Time {
+	if i.Now != nil {
+		return i.Now()
+	}
+	return time.Now()
+}
+
+// Digest returns the hex SHA-256 digest a token is stored under.
+func Digest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// minSweep is the number of digests a MemoryStore holds before Save
+// first sweeps out expired ones.
+const minSweep = 64
+
+// MemoryStore is a Store in memory, for tests and single-process use.
+// Expired digests are evicted by Save in sweeps, run whenever the store
+// has doubled in size since the last one, so memory stays proportional to
+// the digests still valid. A token whose digest was evicted redeems as
+// ErrInvalidToken rather than ErrExpired.
+type MemoryStore struct {
+	Now func() time.Time // Defaults to time.Now
+
+	mu        sync.Mutex
+	expires   map[string]time.Time
+	sweepSize int // Size triggering the next sweep
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{expires: make(map[string]time.Time), sweepSize: minSweep}
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, digest string, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.expires[digest]; ok {
+		return &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: errors.New("once: duplicate token")}
+	}
+	s.expires[digest] = expires
+	if len(s.expires) >= s.sweepSize {
+		s.sweep()
+	}
+	return nil
+}
+
+// sweep evicts expired digests and sets the size triggering the next
+// sweep, so sweeps cost amortized constant time per Save.
+func (s *MemoryStore) sweep() {
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+	for digest, expires := range s.expires {
+		if !now.Before(expires) {
+			delete(s.expires, digest)
+		}
+	}
+	s.sweepSize = 2 * len(s.expires)
+	if s.sweepSize < minSweep {
+		s.sweepSize = minSweep
+	}
+}
+
+// Consume implements Store.
+func (s *MemoryStore) Consume(_ context.Context, digest string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expires, ok := s.expires[digest]
+	if !ok {
+		return time.Time{}, ErrNotFound
+	}
+	delete(s.expires, digest)
+	return expires, nil
+}
@@ -0,0 +1,110 @@
+package once
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestIssuer checks single use and expiry of tokens.
+func TestIssuer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	issuer := &Issuer{Store: NewMemoryStore(), TTL: time.Hour, Now: func() time.Time { return now }}
+
+	token, err := issuer.Issue(ctx)
+	if err != nil {
+		t.Fatalf("Issue failed: %s", err)
+	}
+	if len(token.Value) != 32 || !token.Expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("Unexpected token %+v", token)
+	}
+	if err := issuer.Redeem(ctx, token.Value); err != nil {
+		t.Errorf("Redeem failed: %s", err)
+	}
+	if err := issuer.Redeem(ctx, token.Value); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken on reuse, got %v", err)
+	}
+	if err := issuer.Redeem(ctx, "unknown"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+
+	token, err = issuer.Issue(ctx)
+	if err != nil {
+		t.Fatalf("Issue failed: %s", err)
+	}
+	now = now.Add(time.Hour)
+	if err := issuer.Redeem(ctx, token.Value); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+}
+
+// TestRedeemConcurrent checks that a token is redeemed once under races.
+func TestRedeemConcurrent(t *testing.T) {
+	ctx := context.Background()
+	issuer := &Issuer{Store: NewMemoryStore()}
+	token, err := issuer.Issue(ctx)
+	if err != nil {
+		t.Fatalf("Issue failed: %s", err)
+	}
+
+	var ok int32
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if issuer.Redeem(ctx, token.Value) == nil {
+				atomic.AddInt32(&ok, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if ok != 1 {
+		t.Errorf("Expected 1 successful redeem, got %d", ok)
+	}
+}
+
+// TestErrorCodes checks that redeem errors carry codes.
+func TestErrorCodes(t *testing.T) {
+	issuer := &Issuer{Store: NewMemoryStore()}
+	err := issuer.Redeem(context.Background(), "unknown")
+	if code := uriuniq.CodeOf(err); code != uriuniq.CodeInvalidToken {
+		t.Errorf("Expected %s, got %s", uriuniq.CodeInvalidToken, code)
+	}
+	if code := uriuniq.CodeOf(ErrExpired); code != uriuniq.CodeTokenExpired {
+		t.Errorf("Expected %s, got %s", uriuniq.CodeTokenExpired, code)
+	}
+}
+
+// TestMemoryStoreEviction checks that expired digests do not accumulate.
+func TestMemoryStoreEviction(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.Now = func() time.Time { return now }
+	issuer := &Issuer{Store: store, TTL: time.Minute, Now: store.Now}
+	long := &Issuer{Store: store, TTL: 24 * time.Hour, Now: store.Now}
+
+	live, err := long.Issue(ctx)
+	if err != nil {
+		t.Fatalf("Issue failed: %s", err)
+	}
+	for i := 0; i < 10000; i++ {
+		if _, err := issuer.Issue(ctx); err != nil {
+			t.Fatalf("Issue failed: %s", err)
+		}
+		now = now.Add(time.Second)
+	}
+	if n := len(store.expires); n > 4*minSweep+60 {
+		t.Errorf("Expected expired digests to be evicted, %d left", n)
+	}
+	if err := issuer.Redeem(ctx, live.Value); err != nil {
+		t.Errorf("Expected an unexpired token to survive sweeps, got %v", err)
+	}
+}
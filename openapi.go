@@ -0,0 +1,14 @@
+package uriuniq
+
+// OpenAPISchema returns the pattern and length constraints of the strings
+// generated with opts, for the pattern, minLength and maxLength keywords of
+// an OpenAPI string schema. It returns an empty pattern and zero lengths if
+// opts has no valid charset.
+func OpenAPISchema(opts Options) (pattern string, minLen, maxLen int) {
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return "", 0, 0
+	}
+	minLen, maxLen = idLengthRange(opts)
+	return idPattern(opts, charset), minLen, maxLen
+}
@@ -0,0 +1,34 @@
+package uriuniq
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestOpenAPISchema verifies the schema matches generated strings.
+func TestOpenAPISchema(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 24
+	opts.ExcludeLowercase = true
+	pattern, minLen, maxLen := OpenAPISchema(opts)
+	if pattern != "^[0-9A-Z]{24}$" || minLen != 24 || maxLen != 24 {
+		t.Fatalf("Unexpected schema %s %d %d", pattern, minLen, maxLen)
+	}
+
+	re := regexp.MustCompile(pattern)
+	for i := 0; i < 10; i++ {
+		result, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate failed: %s", err)
+		}
+		if !re.MatchString(result) {
+			t.Errorf("%q does not match %s", result, pattern)
+		}
+	}
+
+	opts.Transform = TransformLowerStrict
+	opts.CustomCharset = "aA"
+	if pattern, _, _ := OpenAPISchema(opts); pattern != "" {
+		t.Errorf("Expected empty pattern for invalid options, got %s", pattern)
+	}
+}
@@ -0,0 +1,182 @@
+package uriuniq
+
+import (
+	"io"
+	"time"
+)
+
+// Option sets one field of Options, checking its value. Options are
+// applied in order on top of NewOpts.
+type Option func(*Options) error
+
+// BuildOptions returns NewOpts with opts applied, checked as a whole as
+// Generate would check them, so a bad configuration fails at construction
+// time, not on first use.
+func BuildOptions(opts ...Option) (Options, error) {
+	o := NewOpts()
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return Options{}, err
+		}
+	}
+	if _, _, err := prepare(o); err != nil {
+		return Options{}, err
+	}
+	return o, nil
+}
+
+// GenerateWith creates a random string from NewOpts with opts applied, as
+// in GenerateWith(WithLength(24), WithCharset(Lowercase)).
+func GenerateWith(opts ...Option) (string, error) {
+	o, err := BuildOptions(opts...)
+	if err != nil {
+		return "", err
+	}
+	return generateID(o)
+}
+
+// NewGeneratorWith creates a Generator from NewOpts with opts applied.
+func NewGeneratorWith(opts ...Option) (*Generator, error) {
+	o, err := BuildOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewGenerator(o)
+}
+
+// WithOptions sets all fields to those of opts, to start from an Options
+// value rather than NewOpts, as in GenerateWith(WithOptions(opts)).
+func WithOptions(opts Options) Option {
+	return func(o *Options) error { *o = opts; return nil }
+}
+
+// WithLength sets Length, which must be positive.
+func WithLength(n int) Option {
+	return func(o *Options) error {
+		if n <= 0 {
+			return errorf(CodeInvalidLength, "uriuniq: length %d must be positive", n)
+		}
+		o.Length = n
+		return nil
+	}
+}
+
+// WithLengthRange sets MinLength and MaxLength.
+func WithLengthRange(min, max int) Option {
+	return func(o *Options) error {
+		if min < 1 || min > max {
+			return errorf(CodeInvalidArgument, "uriuniq: invalid length range %d-%d", min, max)
+		}
+		o.MinLength, o.MaxLength = min, max
+		return nil
+	}
+}
+
+// WithCharset sets CustomCharset, which must have 2 to 256 distinct chars.
+func WithCharset(charset Charset) Option {
+	return func(o *Options) error {
+		if len(charset) < 2 || len(charset) > 256 {
+			return newError(CodeCharsetSize, "uriuniq: charset size 2-256")
+		}
+		for i := 1; i < len(charset); i++ {
+			if IndexOf(charset[:i], charset[i]) >= 0 {
+				return errorf(CodeDuplicateChar, "uriuniq: duplicate char %q", charset[i])
+			}
+		}
+		o.CustomCharset = charset
+		return nil
+	}
+}
+
+// ExcludeNumeric sets Options.ExcludeNumeric.
+func ExcludeNumeric() Option {
+	return func(o *Options) error { o.ExcludeNumeric = true; return nil }
+}
+
+// ExcludeLowercase sets Options.ExcludeLowercase.
+func ExcludeLowercase() Option {
+	return func(o *Options) error { o.ExcludeLowercase = true; return nil }
+}
+
+// ExcludeUppercase sets Options.ExcludeUppercase.
+func ExcludeUppercase() Option {
+	return func(o *Options) error { o.ExcludeUppercase = true; return nil }
+}
+
+// ExcludeAmbiguous sets Options.ExcludeAmbiguous.
+func ExcludeAmbiguous() Option {
+	return func(o *Options) error { o.ExcludeAmbiguous = true; return nil }
+}
+
+// WithMaxBadReads sets MaxBadReads, which must be positive.
+func WithMaxBadReads(n int) Option {
+	return func(o *Options) error {
+		if n <= 0 {
+			return errorf(CodeInvalidArgument, "uriuniq: max bad reads %d must be positive", n)
+		}
+		o.MaxBadReads = n
+		return nil
+	}
+}
+
+// WithEntropyRetries sets EntropyRetries and EntropyRetryBackoff, which
+// must not be negative.
+func WithEntropyRetries(retries int, backoff time.Duration) Option {
+	return func(o *Options) error {
+		if retries < 0 || backoff < 0 {
+			return errorf(CodeInvalidArgument, "uriuniq: entropy retries %d with backoff %s must not be negative", retries, backoff)
+		}
+		o.EntropyRetries = retries
+		o.EntropyRetryBackoff = backoff
+		return nil
+	}
+}
+
+// WithMinEntropyBits sets MinEntropyBits, which must be positive.
+func WithMinEntropyBits(bits int) Option {
+	return func(o *Options) error {
+		if bits <= 0 {
+			return errorf(CodeInvalidArgument, "uriuniq: min entropy bits %d must be positive", bits)
+		}
+		o.MinEntropyBits = bits
+		return nil
+	}
+}
+
+// WithRequireEntropyBits sets RequireEntropyBits, which must be positive.
+func WithRequireEntropyBits(bits int) Option {
+	return func(o *Options) error {
+		if bits <= 0 {
+			return errorf(CodeInvalidArgument, "uriuniq: required entropy bits %d must be positive", bits)
+		}
+		o.RequireEntropyBits = bits
+		return nil
+	}
+}
+
+// WithEntropySource sets EntropySource, such as a reader of an HSM
+// device or a failing reader in tests.
+func WithEntropySource(r io.Reader) Option {
+	return func(o *Options) error {
+		if r == nil {
+			return newError(CodeInvalidArgument, "uriuniq: nil entropy source")
+		}
+		o.EntropySource = r
+		return nil
+	}
+}
+
+// WithSensitive sets Options.Sensitive.
+func WithSensitive() Option {
+	return func(o *Options) error { o.Sensitive = true; return nil }
+}
+
+// WithStrict sets Options.Strict.
+func WithStrict() Option {
+	return func(o *Options) error { o.Strict = true; return nil }
+}
+
+// WithTransform sets Options.Transform.
+func WithTransform(t Transform) Option {
+	return func(o *Options) error { o.Transform = t; return nil }
+}
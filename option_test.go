@@ -0,0 +1,73 @@
+package uriuniq
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/laofun/uriuniq/uriuniqtest"
+)
+
+// TestFunctionalOptions checks GenerateWith and option validation.
+func TestFunctionalOptions(t *testing.T) {
+	id, err := GenerateWith(WithLength(24), WithCharset(Lowercase), ExcludeUppercase())
+	if err != nil || len(id) != 24 || checkCharset(id, Lowercase) != nil {
+		t.Errorf("Unexpected ID %q, %v", id, err)
+	}
+	if id, _ := GenerateWith(); len(id) != DefaultLength {
+		t.Errorf("Expected default length, got %q", id)
+	}
+	if id, err := GenerateWith(WithOptions(Options{Length: 5, CustomCharset: "xyz"})); err != nil || len(id) != 5 || checkCharset(id, "xyz") != nil {
+		t.Errorf("Unexpected ID %q, %v", id, err)
+	}
+	g, err := NewGeneratorWith(WithLengthRange(4, 6), ExcludeNumeric(), ExcludeLowercase())
+	if err != nil {
+		t.Fatalf("NewGeneratorWith failed: %s", err)
+	}
+	if id, _ := g.Next(); len(id) < 4 || len(id) > 6 || checkCharset(id, Uppercase) != nil {
+		t.Errorf("Unexpected ID %q", id)
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		code Code
+	}{
+		{"Zero Length", []Option{WithLength(0)}, CodeInvalidLength},
+		{"Bad Range", []Option{WithLengthRange(5, 2)}, CodeInvalidArgument},
+		{"Small Charset", []Option{WithCharset("a")}, CodeCharsetSize},
+		{"Duplicate Char", []Option{WithCharset("abca")}, CodeDuplicateChar},
+		{"Bad Reads", []Option{WithMaxBadReads(-1)}, CodeInvalidArgument},
+		{"Negative Retries", []Option{WithEntropyRetries(-1, 0)}, CodeInvalidArgument},
+		{"Checked Together", []Option{WithLengthRange(1, 2), WithSensitive(), WithTransform(TransformNone)}, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := BuildOptions(tc.opts...)
+			if CodeOf(err) != tc.code && !(tc.code == "" && err == nil) {
+				t.Errorf("Expected code %q, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+// TestWithEntropySource checks that the source is used and its errors
+// are returned.
+func TestWithEntropySource(t *testing.T) {
+	fail := errors.New("hsm offline")
+	g, err := NewGeneratorWith(WithEntropySource(uriuniqtest.FailingSource(0, fail)))
+	if err != nil {
+		t.Fatalf("NewGeneratorWith failed: %s", err)
+	}
+	if _, err := g.Next(); !errors.Is(err, fail) {
+		t.Errorf("Expected source error, got %v", err)
+	}
+	if _, err := BuildOptions(WithEntropySource(nil)); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected error for nil source, got %v", err)
+	}
+	id, err := GenerateWith(WithEntropySource(bytes.NewReader(bytes.Repeat([]byte{0}, 64))))
+	if err != nil || id != strings.Repeat("0", DefaultLength) {
+		t.Errorf("Expected zeros from source, got %q, %v", id, err)
+	}
+}
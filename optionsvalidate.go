@@ -0,0 +1,71 @@
+package uriuniq
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate reports every problem of opts at once, joined with errors.Join,
+// so services can check their configuration at startup instead of on the
+// first Generate: a Length that is not positive, exclusions that a
+// CustomCharset ignores or that leave no chars, a CustomCharset that is
+// not URI-safe or repeats chars, an effective charset that is too small or
+// too large, and the first of any other errors Generate would return.
+// Options that Generate accepts with a warning or a fallback, such as a
+// Length of 0, are reported too. Use CodeOf or errors.Is on the result.
+func (opts Options) Validate() error {
+	var errs []error
+	codes := make(map[Code]bool)
+	add := func(err error) {
+		errs = append(errs, err)
+		codes[CodeOf(err)] = true
+	}
+
+	if opts.Length < 0 || opts.Length == 0 && opts.MaxLength == 0 && opts.MinEntropyBits == 0 {
+		add(fmt.Errorf("%w %d", ErrInvalidLength, opts.Length))
+	}
+	if opts.CustomCharset == "" && opts.CharsetName == "" && !opts.DNSLabel &&
+		opts.ExcludeNumeric && opts.ExcludeLowercase && opts.ExcludeUppercase {
+		// Generate falls back to Alphanumeric.
+		add(fmt.Errorf("%w: every char class is excluded", ErrEmptyCharset))
+	}
+	if cs := opts.CustomCharset; cs != "" && !isRuneCharset(cs) {
+		if opts.ExcludeNumeric || opts.ExcludeLowercase || opts.ExcludeUppercase {
+			add(newError(CodeInvalidArgument, "uriuniq: ExcludeNumeric, ExcludeLowercase and ExcludeUppercase do not apply to a CustomCharset"))
+		}
+		if opts.Escape != EscapePercent {
+			if err := checkCharset(string(cs), uriSafe); err != nil {
+				add(fmt.Errorf("%w: %v", ErrUnsafeCharset, err))
+			}
+		}
+		var seen [256]bool
+		for i := 0; i < len(cs); i++ {
+			if seen[cs[i]] {
+				add(errorf(CodeDuplicateChar, "uriuniq: duplicate char %q in charset", cs[i]))
+				break
+			}
+			seen[cs[i]] = true
+		}
+	}
+
+	// The problems reported above would stop prepare early or only print
+	// a warning, so it runs with them out of the way.
+	rest := opts
+	rest.Logf = nil
+	if codes[CodeInvalidLength] {
+		rest.Length, rest.MinLength, rest.MaxLength = DefaultLength, 0, 0
+	}
+	var err error
+	if isRuneCharset(rest.CustomCharset) {
+		_, _, err = prepareRunes(rest)
+	} else {
+		var charset []byte
+		if _, charset, err = prepare(rest); err == nil && (len(charset) < 2 || len(charset) > 256) {
+			err = errorf(CodeCharsetSize, "uriuniq: charset has %d chars, want 2-256", len(charset))
+		}
+	}
+	if err != nil && !codes[CodeOf(err)] {
+		add(err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,45 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestOptionsValidate checks that all problems are reported together.
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		codes []Code
+	}{
+		{"Defaults", NewOpts(), nil},
+		{"Entropy Only", Options{MinEntropyBits: 128}, nil},
+		{"Escaped", Options{Length: 8, CustomCharset: "ab/", Escape: EscapePercent}, nil},
+		{"Zero Length", Options{}, []Code{CodeInvalidLength}},
+		{"Everything Excluded", Options{Length: 8, ExcludeNumeric: true, ExcludeLowercase: true, ExcludeUppercase: true}, []Code{CodeNoValidChars}},
+		{"Too Small", Options{Length: 8, CustomCharset: "a"}, []Code{CodeCharsetSize}},
+		{"Several", Options{Length: -1, CustomCharset: "ab<a", ExcludeNumeric: true}, []Code{CodeInvalidLength, CodeInvalidArgument, CodeUnsafeCharset, CodeDuplicateChar}},
+		{"Prepare", Options{Length: 8, MinDigits: 1, ExcludeNumeric: true}, []Code{CodeInvalidArgument}},
+		{"Runes", Options{Length: 8, CustomCharset: "äöü", CheckChar: true}, []Code{CodeInvalidArgument}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			var errs []error
+			if err != nil {
+				errs = err.(interface{ Unwrap() []error }).Unwrap()
+			}
+			if len(errs) != len(tc.codes) {
+				t.Fatalf("Expected %d errors, got %v", len(tc.codes), err)
+			}
+			for i, code := range tc.codes {
+				if CodeOf(errs[i]) != code {
+					t.Errorf("Error %d: expected %s, got %v", i, code, errs[i])
+				}
+			}
+		})
+	}
+	if err := (Options{}).Validate(); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Expected ErrInvalidLength, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package uriuniq
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sort"
+)
+
+// packVersion is the first byte of data written by PackIDs.
+const packVersion = 1
+
+// Flags of packed data.
+const (
+	packDelta = 1 << iota // Each ID stores the length it shares with the previous one
+	packFixed             // All IDs have the same length after the prefix
+)
+
+// ErrInvalidPack is returned by UnpackIDs for data not written by PackIDs
+// with the same Options.
+var ErrInvalidPack = newError(CodeInvalidArgument, "uriuniq: invalid packed IDs")
+
+// PackIDs packs ids, generated with opts, into a compact binary form for
+// storage or transport of large ID lists. Each char takes the bits of its
+// IndexOf, ceil(log2(len(charset))) bits, and a prefix shared by all IDs
+// is stored once. Sorted ids, such as time-ordered ones, are delta coded:
+// each ID stores only what differs from the previous one. UnpackIDs with
+// the same opts restores ids in order.
+func PackIDs(ids []string, opts Options) ([]byte, error) {
+	_, charset, err := prepare(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(charset) > 256 {
+		return nil, newError(CodeCharsetSize, "uriuniq: PackIDs needs a charset of at most 256 chars")
+	}
+	var index [256]int
+	for i, c := range charset {
+		index[c] = i
+	}
+	for _, id := range ids {
+		if id == "" {
+			return nil, ErrEmptyInput
+		}
+		if err := checkCharset(id, Charset(charset)); err != nil {
+			return nil, err
+		}
+	}
+
+	prefix := sharedPrefix(ids)
+	if len(ids) > 1 && len(prefix) == len(ids[0]) {
+		// Keep a char of each ID in the bits, so their count is bounded
+		// by the size of the data.
+		prefix = prefix[:len(prefix)-1]
+	}
+	var flags byte
+	if len(ids) > 1 && sort.StringsAreSorted(ids) {
+		flags |= packDelta
+	}
+	maxRest, fixed := 0, true
+	for i, id := range ids {
+		n := len(id) - len(prefix)
+		if n > maxRest {
+			maxRest = n
+		}
+		if i > 0 && len(id) != len(ids[0]) {
+			fixed = false
+		}
+	}
+	if fixed {
+		flags |= packFixed
+	}
+
+	data := []byte{packVersion, flags}
+	data = binary.AppendUvarint(data, uint64(len(ids)))
+	data = binary.AppendUvarint(data, uint64(len(prefix)))
+	data = append(data, prefix...)
+	data = binary.AppendUvarint(data, uint64(maxRest))
+
+	charBits := uint(bits.Len(uint(len(charset) - 1)))
+	lenBits := uint(bits.Len(uint(maxRest)))
+	w := bitWriter{data: data}
+	prev := prefix
+	for _, id := range ids {
+		shared := len(prefix)
+		if flags&packDelta != 0 {
+			shared = len(sharedPrefix([]string{prev, id}))
+			w.write(uint64(shared-len(prefix)), lenBits)
+		}
+		if !fixed {
+			w.write(uint64(len(id)-shared), lenBits)
+		}
+		for i := shared; i < len(id); i++ {
+			w.write(uint64(index[id[i]]), charBits)
+		}
+		prev = id
+	}
+	return w.flush(), nil
+}
+
+// UnpackIDs restores the IDs packed by PackIDs with opts.
+func UnpackIDs(data []byte, opts Options) ([]string, error) {
+	_, charset, err := prepare(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != packVersion {
+		return nil, ErrInvalidPack
+	}
+	flags := data[1]
+	data = data[2:]
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, ErrInvalidPack
+	}
+	data = data[n:]
+	prefixLen, n := binary.Uvarint(data)
+	if n <= 0 || prefixLen > uint64(len(data)-n) {
+		return nil, ErrInvalidPack
+	}
+	prefix := string(data[n : n+int(prefixLen)])
+	data = data[n+int(prefixLen):]
+	maxRest, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, ErrInvalidPack
+	}
+	data = data[n:]
+
+	charBits := uint(bits.Len(uint(len(charset) - 1)))
+	lenBits := uint(bits.Len(uint(maxRest)))
+	// With more than one ID, each takes at least one bit.
+	if count > 1 && (maxRest == 0 || count > uint64(len(data))*8) {
+		return nil, ErrInvalidPack
+	}
+	r := bitReader{data: data}
+	ids := make([]string, 0, count)
+	prev := prefix
+	var buf []byte
+	for i := uint64(0); i < count; i++ {
+		shared := len(prefix)
+		if flags&packDelta != 0 {
+			shared += int(r.read(lenBits))
+			if shared > len(prev) {
+				return nil, ErrInvalidPack
+			}
+		}
+		rest := int(maxRest) - (shared - len(prefix))
+		if flags&packFixed == 0 {
+			rest = int(r.read(lenBits))
+		}
+		if rest < 0 || shared-len(prefix)+rest > int(maxRest) {
+			return nil, ErrInvalidPack
+		}
+		buf = append(buf[:0], prev[:shared]...)
+		for j := 0; j < rest && !r.err; j++ {
+			c := r.read(charBits)
+			if c >= uint64(len(charset)) {
+				return nil, ErrInvalidPack
+			}
+			buf = append(buf, charset[c])
+		}
+		if r.err {
+			return nil, ErrInvalidPack
+		}
+		prev = string(buf)
+		ids = append(ids, prev)
+	}
+	return ids, nil
+}
+
+// sharedPrefix returns the longest prefix of all ids.
+func sharedPrefix(ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	prefix := ids[0]
+	for _, id := range ids[1:] {
+		i := 0
+		for i < len(prefix) && i < len(id) && prefix[i] == id[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	return prefix
+}
+
+// bitWriter appends bits to data, most significant first.
+type bitWriter struct {
+	data []byte
+	acc  uint64
+	n    uint // Bits in acc
+}
+
+func (w *bitWriter) write(v uint64, n uint) {
+	for n > 0 {
+		take := n
+		if take > 8 {
+			take = 8
+		}
+		n -= take
+		w.acc = w.acc<<take | (v>>n)&(1<<take-1)
+		w.n += take
+		for w.n >= 8 {
+			w.n -= 8
+			w.data = append(w.data, byte(w.acc>>w.n))
+		}
+	}
+}
+
+// flush writes the remaining bits, padded with zeros, and returns data.
+func (w *bitWriter) flush() []byte {
+	if w.n > 0 {
+		w.data = append(w.data, byte(w.acc<<(8-w.n)))
+		w.n = 0
+	}
+	return w.data
+}
+
+// bitReader reads bits written by bitWriter. err is set on reading past
+// the end.
+type bitReader struct {
+	data []byte
+	pos  uint // Bit offset
+	err  bool
+}
+
+func (r *bitReader) read(n uint) uint64 {
+	var v uint64
+	for i := uint(0); i < n; i++ {
+		if r.pos/8 >= uint(len(r.data)) {
+			r.err = true
+			return 0
+		}
+		bit := r.data[r.pos/8] >> (7 - r.pos%8) & 1
+		v = v<<1 | uint64(bit)
+		r.pos++
+	}
+	return v
+}
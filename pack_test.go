@@ -0,0 +1,85 @@
+package uriuniq
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestPackIDs checks that packed IDs unpack to the same list and are
+// smaller than the IDs.
+func TestPackIDs(t *testing.T) {
+	opts := NewOpts()
+	random, err := GenerateN(opts, 200)
+	if err != nil {
+		t.Fatalf("GenerateN failed: %s", err)
+	}
+	sorted := append([]string(nil), random...)
+	sort.Strings(sorted)
+	prefixed := make([]string, len(random))
+	for i, id := range random {
+		prefixed[i] = "ord" + id
+	}
+	varOpts := Options{MinLength: 4, MaxLength: 12, CustomCharset: Numeric}
+	variable, err := GenerateN(varOpts, 50)
+	if err != nil {
+		t.Fatalf("GenerateN failed: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		ids  []string
+		opts Options
+	}{
+		{"random", random, opts},
+		{"sorted", sorted, opts},
+		{"prefix", prefixed, opts},
+		{"variable", variable, varOpts},
+		{"one", random[:1], opts},
+		{"same", []string{"abc", "abc", "abc"}, opts},
+		{"empty", nil, opts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := PackIDs(tt.ids, tt.opts)
+			if err != nil {
+				t.Fatalf("PackIDs failed: %s", err)
+			}
+			size := 0
+			for _, id := range tt.ids {
+				size += len(id)
+			}
+			if len(tt.ids) > 10 && len(data) >= size {
+				t.Errorf("Packed %d bytes into %d", size, len(data))
+			}
+			ids, err := UnpackIDs(data, tt.opts)
+			if err != nil {
+				t.Fatalf("UnpackIDs failed: %s", err)
+			}
+			if len(ids) != len(tt.ids) || (len(ids) > 0 && !reflect.DeepEqual(ids, tt.ids)) {
+				t.Errorf("Expected %v, got %v", tt.ids, ids)
+			}
+		})
+	}
+}
+
+// TestPackIDsErrors checks rejecting IDs outside the charset and
+// malformed data.
+func TestPackIDsErrors(t *testing.T) {
+	opts := Options{Length: 4, CustomCharset: Numeric}
+	if _, err := PackIDs([]string{"12a4"}, opts); CodeOf(err) != CodeInvalidChar {
+		t.Errorf("Expected CodeInvalidChar, got %v", err)
+	}
+	if _, err := PackIDs([]string{""}, opts); err != ErrEmptyInput {
+		t.Errorf("Expected ErrEmptyInput, got %v", err)
+	}
+	data, err := PackIDs([]string{"1234", "5678", "9012"}, opts)
+	if err != nil {
+		t.Fatalf("PackIDs failed: %s", err)
+	}
+	for _, bad := range [][]byte{nil, {9, 0}, data[:len(data)-1], {packVersion, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0, 1}} {
+		if _, err := UnpackIDs(bad, opts); err != ErrInvalidPack {
+			t.Errorf("Expected ErrInvalidPack for %v, got %v", bad, err)
+		}
+	}
+}
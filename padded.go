@@ -0,0 +1,65 @@
+package uriuniq
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ErrPadInCharset is returned when the pad char could appear in an ID.
+var ErrPadInCharset = newError(CodePadInCharset, "uriuniq: pad char in charset")
+
+// GeneratePadded creates a random string using Options, as Generate does,
+// and right-pads it with padChar to exactly width bytes, for fixed-width
+// record formats. padChar must not be part of the charset, so padding can
+// always be told apart from the ID.
+func GeneratePadded(width int, padChar byte, opts Options) (string, error) {
+	prepared, charset, err := prepare(opts)
+	if err != nil {
+		return "", err
+	}
+	if bytes.IndexByte(charset, padChar) >= 0 {
+		return "", fmt.Errorf("%w: %q", ErrPadInCharset, padChar)
+	}
+	_, max := lengthRange(prepared)
+	if n := len(idPrefix(prepared)) + groupedLength(prepared, max+checkChars(prepared)); n > width {
+		return "", errorf(CodeInvalidLength, "uriuniq: length %d exceeds width %d", n, width)
+	}
+
+	id, err := generateID(opts)
+	if err != nil {
+		return "", err
+	}
+	if len(id) > width {
+		// Only percent-encoding grows an ID past the bound checked above.
+		return "", errorf(CodeInvalidLength, "uriuniq: escaped length %d exceeds width %d", len(id), width)
+	}
+	// string(padChar) would encode a byte of 0x80 or above as a 2-byte rune.
+	return id + strings.Repeat(string([]byte{padChar}), width-len(id)), nil
+}
+
+// ParsePadded extracts the ID from a field created by GeneratePadded. The
+// field must be exactly width bytes and the ID is checked by Validate.
+// Fields longer than both width and DefaultMaxInputLength are rejected
+// with ErrInputTooLong.
+func ParsePadded(field string, width int, padChar byte, opts Options) (string, error) {
+	if len(field) > width {
+		if err := checkInputLength(field, DefaultMaxInputLength); err != nil {
+			return "", err
+		}
+	}
+	if len(field) != width {
+		return "", errorf(CodeInvalidLength, "uriuniq: field is %d bytes, want %d", len(field), width)
+	}
+	// Trim bytes rather than runes: a padChar of 0x80 or above is not a
+	// rune of its own.
+	end := len(field)
+	for end > 0 && field[end-1] == padChar {
+		end--
+	}
+	id := field[:end]
+	if err := Validate(id, opts); err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,89 @@
+package uriuniq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestGeneratePadded checks padding and the round trip through ParsePadded.
+func TestGeneratePadded(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 12
+	field, err := GeneratePadded(20, ' ', opts)
+	if err != nil {
+		t.Fatalf("GeneratePadded failed: %s", err)
+	}
+	if len(field) != 20 || !strings.HasSuffix(field, strings.Repeat(" ", 8)) {
+		t.Fatalf("Unexpected field %q", field)
+	}
+
+	id, err := ParsePadded(field, 20, ' ', opts)
+	if err != nil {
+		t.Fatalf("ParsePadded failed: %s", err)
+	}
+	if id != strings.TrimRight(field, " ") || len(id) != 12 {
+		t.Errorf("Unexpected id %q", id)
+	}
+}
+
+// TestPaddedErrors verifies the error cases of padded fields.
+func TestPaddedErrors(t *testing.T) {
+	opts := NewOpts()
+	if _, err := GeneratePadded(20, 'a', opts); !errors.Is(err, ErrPadInCharset) {
+		t.Errorf("Expected ErrPadInCharset, got %v", err)
+	}
+	if _, err := GeneratePadded(8, ' ', opts); err == nil {
+		t.Errorf("Expected error for width below length")
+	}
+	if _, err := ParsePadded("abc  ", 6, ' ', opts); err == nil {
+		t.Errorf("Expected error for wrong width")
+	}
+	var perr *ParseError
+	if _, err := ParsePadded("ab c  ", 6, ' ', Options{Length: 4}); !errors.As(err, &perr) || perr.Index != 2 {
+		t.Errorf("Expected ParseError at index 2, got %v", err)
+	}
+}
+
+// TestGeneratePaddedCheckChar checks that padded IDs carry the prefix and
+// check char of the Options.
+func TestGeneratePaddedCheckChar(t *testing.T) {
+	opts := Options{Length: 8, Prefix: "cus", CheckChar: true}
+	field, err := GeneratePadded(20, ' ', opts)
+	if err != nil {
+		t.Fatalf("GeneratePadded failed: %s", err)
+	}
+	id := strings.TrimRight(field, " ")
+	if len(id) != len("cus_")+8+1 || !strings.HasPrefix(id, "cus_") {
+		t.Fatalf("Unexpected ID %q", id)
+	}
+	_, charset, _ := prepare(opts)
+	if c := luhnMod(id[4:12], Charset(charset)); id[12] != c {
+		t.Errorf("Expected check char %q, got %q", c, id[12])
+	}
+	if _, err := GeneratePadded(12, ' ', opts); CodeOf(err) != CodeInvalidLength {
+		t.Errorf("Expected %s for a width without room for the check char, got %v", CodeInvalidLength, err)
+	}
+}
+
+// TestParsePaddedHighByte checks padding with a byte of 0x80 or above and
+// that ParsePadded validates the ID it extracts.
+func TestParsePaddedHighByte(t *testing.T) {
+	opts := Options{Length: 8, CheckChar: true}
+	field, err := GeneratePadded(16, 0xff, opts)
+	if err != nil {
+		t.Fatalf("GeneratePadded failed: %s", err)
+	}
+	if len(field) != 16 || !strings.HasSuffix(field, "\xff\xff\xff\xff\xff\xff\xff") {
+		t.Fatalf("Unexpected field %q", field)
+	}
+	id, err := ParsePadded(field, 16, 0xff, opts)
+	if err != nil || id != field[:9] {
+		t.Errorf("ParsePadded(%q) = %q, %v", field, id, err)
+	}
+
+	short := id[:8] + strings.Repeat("\xff", 8)
+	if _, err := ParsePadded(short, 16, 0xff, opts); CodeOf(err) != CodeInvalidLength {
+		t.Errorf("Expected %s for a wrong-length ID, got %v", CodeInvalidLength, err)
+	}
+}
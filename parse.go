@@ -0,0 +1,74 @@
+package uriuniq
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrEmptyInput is returned when parsing an empty string.
+var ErrEmptyInput = newError(CodeEmptyInput, "uriuniq: empty input")
+
+// ParsedID describes a string accepted by ParseAny.
+type ParsedID struct {
+	Value   string
+	Length  int     // Length in chars
+	Charset Charset // Smallest charset Generate would draw Value from
+}
+
+// ParseAny inspects an untrusted string and reports the charset it was drawn
+// from. It is safe to call on hostile input: it never panics and does a
+// single linear pass over s. Inputs longer than DefaultMaxInputLength are
+// rejected with ErrInputTooLong before being scanned. Chars outside the
+// URI-safe set are reported as a *ParseError.
+func ParseAny(s string) (ParsedID, error) {
+	return ParseAnyWithLimits(s, NewLimits())
+}
+
+// ParseAnyWithLimits is like ParseAny but applies the given Limits.
+func ParseAnyWithLimits(s string, limits Limits) (ParsedID, error) {
+	if s == "" {
+		return ParsedID{}, ErrEmptyInput
+	}
+	if err := limits.check(s); err != nil {
+		return ParsedID{}, err
+	}
+
+	var digit, lower, upper, other bool
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case '0' <= c && c <= '9':
+			digit = true
+		case 'a' <= c && c <= 'z':
+			lower = true
+		case 'A' <= c && c <= 'Z':
+			upper = true
+		default:
+			if c >= utf8.RuneSelf || strings.IndexByte(string(uriSafe), c) < 0 {
+				r, _ := utf8.DecodeRuneInString(s[i:])
+				return ParsedID{}, &ParseError{Index: i, Char: r, Charset: uriSafe}
+			}
+			other = true
+		}
+	}
+
+	return ParsedID{Value: s, Length: len(s), Charset: classCharset(digit, lower, upper, other)}, nil
+}
+
+// classCharset returns the charset getCharset builds for the given classes.
+func classCharset(digit, lower, upper, other bool) Charset {
+	if other {
+		return uriSafe
+	}
+	var charset Charset
+	if digit {
+		charset += Numeric
+	}
+	if lower {
+		charset += Lowercase
+	}
+	if upper {
+		charset += Uppercase
+	}
+	return charset
+}
@@ -0,0 +1,74 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestParseAny checks charset detection and error reporting.
+func TestParseAny(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		charset Charset
+		errIdx  int // -1 when no ParseError is expected
+	}{
+		{"Numeric", "0123", Numeric, -1},
+		{"Lowercase", "abc", Lowercase, -1},
+		{"Uppercase", "ABC", Uppercase, -1},
+		{"Lower Numeric", "a1b2", Numeric + Lowercase, -1},
+		{"Alphanumeric", "aZ9", Numeric + Lowercase + Uppercase, -1},
+		{"URI-safe", "a-b_c", uriSafe, -1},
+		{"Invalid", "abc/def", "", 3},
+		{"Non-ASCII", "abcé", "", 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := ParseAny(tc.input)
+			if tc.errIdx >= 0 {
+				var perr *ParseError
+				if !errors.As(err, &perr) || perr.Index != tc.errIdx {
+					t.Fatalf("%s: expected ParseError at %d, got %v", tc.name, tc.errIdx, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: ParseAny failed: %s", tc.name, err)
+			}
+			if parsed.Charset != tc.charset || parsed.Length != len(tc.input) {
+				t.Errorf("%s: got charset %q length %d", tc.name, parsed.Charset, parsed.Length)
+			}
+		})
+	}
+
+	if _, err := ParseAny(""); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Expected ErrEmptyInput, got %v", err)
+	}
+}
+
+// FuzzParseAny asserts ParseAny never panics and its results are consistent.
+func FuzzParseAny(f *testing.F) {
+	for _, seed := range []string{"", "a", "0123456789", "cus_h8aK3", "~!*'()", "a b", "\x00", "日本"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		parsed, err := ParseAny(s)
+		if err != nil {
+			var perr *ParseError
+			if errors.As(err, &perr) {
+				if perr.Index < 0 || perr.Index >= len(s) {
+					t.Fatalf("index %d out of range for %q", perr.Index, s)
+				}
+			}
+			return
+		}
+		if parsed.Length != utf8.RuneCountInString(s) {
+			t.Fatalf("length %d mismatch for %q", parsed.Length, s)
+		}
+		if err := checkCharset(s, parsed.Charset); err != nil {
+			t.Fatalf("%q not in reported charset: %s", s, err)
+		}
+	})
+}
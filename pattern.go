@@ -0,0 +1,92 @@
+package uriuniq
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Pattern returns an anchored regexp matching exactly the strings that can be
+// generated with opts, for use in gateways and WAF rules.
+func Pattern(opts Options) (*regexp.Regexp, error) {
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(idPattern(opts, charset))
+}
+
+// CharsetOf returns the charset strings generated with opts are drawn
+// from, in the order of IndexOf.
+func CharsetOf(opts Options) (Charset, error) {
+	_, charset, err := prepare(opts)
+	if err != nil {
+		return "", err
+	}
+	return Charset(charset), nil
+}
+
+// idPattern returns the anchored regexp source matching the strings
+// generated with opts, which must already be prepared.
+func idPattern(opts Options, charset []byte) string {
+	min, max := lengthRange(opts)
+	min, max = min+checkChars(opts), max+checkChars(opts)
+	prefix := regexp.QuoteMeta(idPrefix(opts))
+	if opts.GroupSize > 0 {
+		return fmt.Sprintf("^%s%s$", prefix, groupedPattern(opts, charClass(charset), min, max))
+	}
+	if min != max {
+		return fmt.Sprintf("^%s%s{%d,%d}$", prefix, charClass(charset), min, max)
+	}
+	return fmt.Sprintf("^%s%s{%d}$", prefix, charClass(charset), min)
+}
+
+// charClass returns a regexp char class matching exactly the chars of
+// charset, with consecutive chars collapsed into ranges, e.g. "[0-9a-z]".
+func charClass(charset []byte) string {
+	var seen [256]bool
+	for _, c := range charset {
+		seen[c] = true
+	}
+	var chars []byte
+	for c := 0; c < 256; c++ {
+		if seen[c] {
+			chars = append(chars, byte(c))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := 0; i < len(chars); {
+		j := i
+		for j+1 < len(chars) && chars[j+1] == chars[j]+1 {
+			j++
+		}
+		writeClassChar(&b, chars[i])
+		if j-i >= 2 {
+			b.WriteByte('-')
+		}
+		if j > i {
+			writeClassChar(&b, chars[j])
+		}
+		i = j + 1
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// writeClassChar writes c escaped for use inside a char class.
+func writeClassChar(b *strings.Builder, c byte) {
+	switch {
+	case c == '\\' || c == ']' || c == '[' || c == '^' || c == '-':
+		b.WriteByte('\\')
+		b.WriteByte(c)
+	case c < 0x20 || c >= 0x7f:
+		const hex = "0123456789abcdef"
+		b.WriteString(`\x`)
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0xf])
+	default:
+		b.WriteByte(c)
+	}
+}
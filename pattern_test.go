@@ -0,0 +1,88 @@
+package uriuniq
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestCharClass checks range collapsing and escaping of char classes.
+func TestCharClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		charset  string
+		expected string
+	}{
+		{"Alphanumeric", string(Alphanumeric), "[0-9A-Za-z]"},
+		{"Pairs", "abxy", "[abxy]"},
+		{"Three", "cab", "[a-c]"},
+		{"Duplicates", "aab", "[ab]"},
+		{"Escaped", `-]^\a`, `[\-\\-\^a]`},
+		{"URI-safe", string(uriSafe), `[!'-*\-.0-9A-Z_a-z~]`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := charClass([]byte(tc.charset))
+			if got != tc.expected {
+				t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, got)
+			}
+			if !regexp.MustCompile("^" + got + "+$").MatchString(tc.charset) {
+				t.Errorf("%s: %s does not match its charset", tc.name, got)
+			}
+		})
+	}
+}
+
+// TestPattern checks the regexp against generated and invalid strings.
+func TestPattern(t *testing.T) {
+	opts := NewOpts()
+	opts.CustomCharset = "abc-"
+	re, err := Pattern(opts)
+	if err != nil {
+		t.Fatalf("Pattern failed: %s", err)
+	}
+	for i := 0; i < 10; i++ {
+		result, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate failed: %s", err)
+		}
+		if !re.MatchString(result) {
+			t.Errorf("%q does not match %s", result, re)
+		}
+	}
+	for _, invalid := range []string{"", "abcabcabcabcabc", "abcabcabcabcabcab", "abcabcabcabcabcd", "xabcabcabcabcabca"} {
+		if re.MatchString(invalid) {
+			t.Errorf("%q should not match %s", invalid, re)
+		}
+	}
+}
+
+// TestPatternLengthRange checks the quantifier of a length range.
+func TestPatternLengthRange(t *testing.T) {
+	opts := NewOpts()
+	opts.CustomCharset = "ab"
+	opts.MinLength, opts.MaxLength = 2, 4
+	re, err := Pattern(opts)
+	if err != nil {
+		t.Fatalf("Pattern failed: %s", err)
+	}
+	if re.String() != "^[ab]{2,4}$" {
+		t.Errorf("Unexpected pattern %s", re)
+	}
+	if _, minLen, maxLen := OpenAPISchema(opts); minLen != 2 || maxLen != 4 {
+		t.Errorf("Expected lengths 2-4, got %d-%d", minLen, maxLen)
+	}
+}
+
+// TestCharsetOf checks the charset built from Options.
+func TestCharsetOf(t *testing.T) {
+	if cs, err := CharsetOf(Options{Length: 4, ExcludeUppercase: true}); err != nil || cs != Numeric+Lowercase {
+		t.Errorf("Unexpected charset %q, %v", cs, err)
+	}
+	if cs, _ := CharsetOf(Options{Length: 4, CustomCharset: "xyz"}); cs != "xyz" {
+		t.Errorf("Expected custom charset, got %q", cs)
+	}
+	if _, err := CharsetOf(Options{Length: 4, MinLength: 2, MaxLength: 1}); err == nil {
+		t.Errorf("Expected error for invalid Options")
+	}
+}
@@ -0,0 +1,167 @@
+// Package perf captures and compares performance baselines of ID
+// generation, so projects can detect when an upgrade of this module slows
+// down their hot path. Save a Baseline once, then compare a fresh capture
+// against it in tests or CI.
+//
+// Example:
+//
+//	current, err := perf.Capture(perf.DefaultPresets(), time.Second)
+//	for _, r := range perf.Compare(saved, current, perf.Thresholds{}) {
+//	    log.Printf("regression: %s", r)
+//	}
+package perf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// Result is the measured cost of one preset.
+type Result struct {
+	OpsPerSec   float64 `json:"ops_per_sec"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+}
+
+// Baseline holds the Results of a capture by preset name.
+type Baseline struct {
+	GoVersion string            `json:"go_version"`
+	Results   map[string]Result `json:"results"`
+}
+
+// DefaultPresets returns the presets measured by default: the default
+// Options, an 8-digit numeric code and a 64-char token.
+func DefaultPresets() map[string]uriuniq.Options {
+	return map[string]uriuniq.Options{
+		"default":  uriuniq.NewOpts(),
+		"numeric8": {Length: 8, CustomCharset: uriuniq.Numeric},
+		"token64":  {Length: 64},
+	}
+}
+
+// Capture measures Generator.Next for each preset for about d each.
+// Measurements are noisy on shared machines; pair them with generous
+// Thresholds.
+func Capture(presets map[string]uriuniq.Options, d time.Duration) (Baseline, error) {
+	b := Baseline{GoVersion: runtime.Version(), Results: make(map[string]Result, len(presets))}
+	for name, opts := range presets {
+		g, err := uriuniq.NewGenerator(opts)
+		if err != nil {
+			return b, fmt.Errorf("perf: preset %s: %w", name, err)
+		}
+		r, err := measure(func() error { _, err := g.Next(); return err }, d)
+		if err != nil {
+			return b, fmt.Errorf("perf: preset %s: %w", name, err)
+		}
+		b.Results[name] = r
+	}
+	return b, nil
+}
+
+// measure runs f in growing rounds until d has passed.
+func measure(f func() error, d time.Duration) (Result, error) {
+	if err := f(); err != nil { // Warm up and fail fast
+		return Result{}, err
+	}
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	ops := 0
+	for round := 1; ; round *= 2 {
+		for i := 0; i < round; i++ {
+			if err := f(); err != nil {
+				return Result{}, err
+			}
+		}
+		ops += round
+		if time.Since(start) >= d {
+			break
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return Result{
+		OpsPerSec:   float64(ops) / elapsed.Seconds(),
+		BytesPerOp:  float64(after.TotalAlloc-before.TotalAlloc) / float64(ops),
+		AllocsPerOp: float64(after.Mallocs-before.Mallocs) / float64(ops),
+	}, nil
+}
+
+// Threshold bounds the regression allowed for a preset.
+type Threshold struct {
+	MaxSlowdown   float64 // Allowed drop in ops/sec, as a fraction; defaults to 0.2
+	MaxExtraBytes float64 // Allowed increase in B/op; defaults to 16
+}
+
+// Thresholds holds a Threshold per preset name, and Default for the rest.
+type Thresholds struct {
+	Default   Threshold
+	PerPreset map[string]Threshold
+}
+
+// Regression is a metric of a preset outside its Threshold.
+type Regression struct {
+	Preset   string
+	Metric   string // "ops/sec" or "B/op"
+	Baseline float64
+	Current  float64
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: %s %.1f, baseline %.1f", r.Preset, r.Metric, r.Current, r.Baseline)
+}
+
+// Compare returns the regressions of current against base, ordered by
+// preset. Presets missing from either are ignored.
+func Compare(base, current Baseline, th Thresholds) []Regression {
+	names := make([]string, 0, len(base.Results))
+	for name := range base.Results {
+		if _, ok := current.Results[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var out []Regression
+	for _, name := range names {
+		b, c := base.Results[name], current.Results[name]
+		t, ok := th.PerPreset[name]
+		if !ok {
+			t = th.Default
+		}
+		if t.MaxSlowdown <= 0 {
+			t.MaxSlowdown = 0.2
+		}
+		if t.MaxExtraBytes <= 0 {
+			t.MaxExtraBytes = 16
+		}
+		if c.OpsPerSec < b.OpsPerSec*(1-t.MaxSlowdown) {
+			out = append(out, Regression{name, "ops/sec", b.OpsPerSec, c.OpsPerSec})
+		}
+		if c.BytesPerOp > b.BytesPerOp+t.MaxExtraBytes {
+			out = append(out, Regression{name, "B/op", b.BytesPerOp, c.BytesPerOp})
+		}
+	}
+	return out
+}
+
+// Save writes b as JSON.
+func (b Baseline) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Load reads a Baseline written by Save.
+func Load(r io.Reader) (Baseline, error) {
+	var b Baseline
+	err := json.NewDecoder(r).Decode(&b)
+	return b, err
+}
@@ -0,0 +1,59 @@
+package perf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestCapture checks that every preset is measured.
+func TestCapture(t *testing.T) {
+	b, err := Capture(DefaultPresets(), 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Capture failed: %s", err)
+	}
+	if len(b.Results) != 3 || b.GoVersion == "" {
+		t.Fatalf("Unexpected baseline %+v", b)
+	}
+	for name, r := range b.Results {
+		if r.OpsPerSec <= 0 || r.BytesPerOp < 0 {
+			t.Errorf("%s: unexpected result %+v", name, r)
+		}
+	}
+	if _, err := Capture(map[string]uriuniq.Options{"bad": {Length: 4, MinLength: 3, MaxLength: 1}}, time.Millisecond); err == nil {
+		t.Errorf("Expected error for invalid preset")
+	}
+}
+
+// TestCompare checks thresholds and the JSON round trip.
+func TestCompare(t *testing.T) {
+	base := Baseline{Results: map[string]Result{
+		"a": {OpsPerSec: 1000, BytesPerOp: 32},
+		"b": {OpsPerSec: 1000, BytesPerOp: 32},
+		"c": {OpsPerSec: 1000, BytesPerOp: 32},
+	}}
+	current := Baseline{Results: map[string]Result{
+		"a": {OpsPerSec: 900, BytesPerOp: 40},
+		"b": {OpsPerSec: 700, BytesPerOp: 64},
+	}}
+	got := Compare(base, current, Thresholds{PerPreset: map[string]Threshold{"a": {MaxSlowdown: 0.05}}})
+	want := []Regression{
+		{"a", "ops/sec", 1000, 900},
+		{"b", "ops/sec", 1000, 700},
+		{"b", "B/op", 32, 64},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	var buf bytes.Buffer
+	if err := base.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %s", err)
+	}
+	if loaded, err := Load(&buf); err != nil || !reflect.DeepEqual(loaded, base) {
+		t.Errorf("Expected %+v, got %+v, %v", base, loaded, err)
+	}
+}
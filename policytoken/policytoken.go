@@ -0,0 +1,193 @@
+// Package policytoken lets trusted clients of a central ID service
+// override its Options per request with a compact signed token, so one
+// service can serve many formats without configuration for each. Tokens
+// are created offline from an Options value and signed with HMAC-SHA256;
+// the service accepts only the fields it allows.
+//
+// Example:
+//
+//	// Offline:
+//	token, err := policytoken.Sign(uriuniq.Options{Length: 8, CustomCharset: uriuniq.Numeric}, key, 0)
+//	// In the service, for a request carrying token:
+//	p, err := policytoken.Verify(token, key, policytoken.Length, policytoken.Charset)
+//	opts, err := p.Apply(serviceOpts)
+//	id, err := uriuniq.GenerateWith(uriuniq.WithOptions(opts))
+package policytoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// Field is a group of Options fields a token may override.
+type Field string
+
+const (
+	Length      Field = "length"       // Length
+	LengthRange Field = "length_range" // MinLength and MaxLength
+	Charset     Field = "charset"      // CustomCharset
+	Classes     Field = "classes"      // ExcludeNumeric, ExcludeLowercase and ExcludeUppercase
+)
+
+var (
+	// ErrInvalidToken is returned for a malformed token or a bad signature.
+	ErrInvalidToken = &uriuniq.Error{Code: uriuniq.CodeInvalidToken, Err: errors.New("policytoken: invalid token")}
+	// ErrExpired is returned for a token past its expiry.
+	ErrExpired = &uriuniq.Error{Code: uriuniq.CodeTokenExpired, Err: errors.New("policytoken: token expired")}
+	// ErrNotAllowed is returned for a token overriding a Field that is not
+	// allowed.
+	ErrNotAllowed = &uriuniq.Error{Code: uriuniq.CodeInvalidField, Err: errors.New("policytoken: field not allowed")}
+	// ErrConflict is returned by Apply when the result would set both
+	// CharsetName and CustomCharset.
+	ErrConflict = &uriuniq.Error{Code: uriuniq.CodeInvalidArgument, Err: errors.New("policytoken: both CharsetName and CustomCharset set")}
+)
+
+// claims is the signed payload. Short JSON names keep tokens compact.
+type claims struct {
+	Length           int    `json:"l,omitempty"`
+	MinLength        int    `json:"mn,omitempty"`
+	MaxLength        int    `json:"mx,omitempty"`
+	CustomCharset    string `json:"cs,omitempty"`
+	ExcludeNumeric   bool   `json:"xn,omitempty"`
+	ExcludeLowercase bool   `json:"xl,omitempty"`
+	ExcludeUppercase bool   `json:"xu,omitempty"`
+	Expires          int64  `json:"exp,omitempty"`
+}
+
+// fields returns the Fields set in c.
+func (c claims) fields() []Field {
+	var out []Field
+	if c.Length != 0 {
+		out = append(out, Length)
+	}
+	if c.MinLength != 0 || c.MaxLength != 0 {
+		out = append(out, LengthRange)
+	}
+	if c.CustomCharset != "" {
+		out = append(out, Charset)
+	}
+	if c.ExcludeNumeric || c.ExcludeLowercase || c.ExcludeUppercase {
+		out = append(out, Classes)
+	}
+	return out
+}
+
+// Policy is the override carried by a verified token.
+type Policy struct {
+	c claims
+}
+
+// Sign creates a token overriding the fields of opts set to non-zero
+// values. Only the Fields above are carried; others are ignored. A ttl of
+// 0 or less creates a token that never expires.
+func Sign(opts uriuniq.Options, key []byte, ttl time.Duration) (string, error) {
+	return sign(opts, key, ttl, time.Now())
+}
+
+func sign(opts uriuniq.Options, key []byte, ttl time.Duration, now time.Time) (string, error) {
+	c := claims{
+		Length:           opts.Length,
+		MinLength:        opts.MinLength,
+		MaxLength:        opts.MaxLength,
+		CustomCharset:    string(opts.CustomCharset),
+		ExcludeNumeric:   opts.ExcludeNumeric,
+		ExcludeLowercase: opts.ExcludeLowercase,
+		ExcludeUppercase: opts.ExcludeUppercase,
+	}
+	if ttl > 0 {
+		c.Expires = now.Add(ttl).Unix()
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + mac(body, key), nil
+}
+
+// Verify checks the signature and expiry of token and that it overrides
+// only allowed Fields.
+func Verify(token string, key []byte, allowed ...Field) (*Policy, error) {
+	return verify(token, key, allowed, time.Now())
+}
+
+func verify(token string, key []byte, allowed []Field, now time.Time) (*Policy, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(mac(body, key))) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if c.Expires != 0 && now.Unix() >= c.Expires {
+		return nil, ErrExpired
+	}
+	for _, f := range c.fields() {
+		if !isAllowed(f, allowed) {
+			return nil, fmt.Errorf("%w: %s", ErrNotAllowed, f)
+		}
+	}
+	return &Policy{c: c}, nil
+}
+
+// Fields returns the Fields p overrides.
+func (p *Policy) Fields() []Field {
+	return p.c.fields()
+}
+
+// Apply returns base with the fields of p overridden. A Length override
+// clears a length range of base, and a length range override wins over
+// Length, as in Options. It returns ErrConflict if the result sets both
+// CharsetName and CustomCharset, such as for a Charset override of a base
+// picking its charset by name, rather than leaving generation to fail.
+func (p *Policy) Apply(base uriuniq.Options) (uriuniq.Options, error) {
+	c := p.c
+	if c.Length != 0 {
+		base.Length = c.Length
+		base.MinLength, base.MaxLength = 0, 0
+	}
+	if c.MinLength != 0 || c.MaxLength != 0 {
+		base.MinLength, base.MaxLength = c.MinLength, c.MaxLength
+	}
+	if c.CustomCharset != "" {
+		base.CustomCharset = uriuniq.Charset(c.CustomCharset)
+	}
+	if c.ExcludeNumeric || c.ExcludeLowercase || c.ExcludeUppercase {
+		base.ExcludeNumeric = c.ExcludeNumeric
+		base.ExcludeLowercase = c.ExcludeLowercase
+		base.ExcludeUppercase = c.ExcludeUppercase
+	}
+	if base.CharsetName != "" && base.CustomCharset != "" {
+		return uriuniq.Options{}, ErrConflict
+	}
+	return base, nil
+}
+
+func isAllowed(f Field, allowed []Field) bool {
+	for _, a := range allowed {
+		if a == f {
+			return true
+		}
+	}
+	return false
+}
+
+// mac returns the base64url HMAC-SHA256 of body under key.
+func mac(body string, key []byte) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package policytoken
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestSignVerify checks that a token carries its overrides.
+func TestSignVerify(t *testing.T) {
+	key := []byte("key")
+	token, err := Sign(uriuniq.Options{Length: 8, CustomCharset: uriuniq.Numeric}, key, 0)
+	if err != nil {
+		t.Fatalf("Sign failed: %s", err)
+	}
+	p, err := Verify(token, key, Length, Charset)
+	if err != nil {
+		t.Fatalf("Verify failed: %s", err)
+	}
+	if !reflect.DeepEqual(p.Fields(), []Field{Length, Charset}) {
+		t.Errorf("Unexpected fields %v", p.Fields())
+	}
+	opts, err := p.Apply(uriuniq.Options{MinLength: 20, MaxLength: 30, MaxBadReads: 7})
+	if err != nil {
+		t.Fatalf("Apply failed: %s", err)
+	}
+	if opts.Length != 8 || opts.MaxLength != 0 || opts.CustomCharset != uriuniq.Numeric || opts.MaxBadReads != 7 {
+		t.Errorf("Unexpected options %+v", opts)
+	}
+	id, err := uriuniq.GenerateWith(uriuniq.WithOptions(opts))
+	if err != nil || len(id) != 8 {
+		t.Errorf("Unexpected ID %q, %v", id, err)
+	}
+	if _, err := p.Apply(uriuniq.Options{CharsetName: "base58"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+}
+
+// TestVerifyErrors checks rejected tokens.
+func TestVerifyErrors(t *testing.T) {
+	key := []byte("key")
+	now := time.Unix(1700000000, 0)
+	token, _ := sign(uriuniq.Options{ExcludeUppercase: true}, key, time.Hour, now)
+	tests := []struct {
+		name    string
+		token   string
+		key     string
+		allowed []Field
+		now     time.Time
+		err     error
+	}{
+		{"Valid", token, "key", []Field{Classes}, now, nil},
+		{"Wrong Key", token, "other", []Field{Classes}, now, ErrInvalidToken},
+		{"Tampered", "x" + token, "key", []Field{Classes}, now, ErrInvalidToken},
+		{"No Signature", "abc", "key", []Field{Classes}, now, ErrInvalidToken},
+		{"Expired", token, "key", []Field{Classes}, now.Add(2 * time.Hour), ErrExpired},
+		{"Not Allowed", token, "key", []Field{Length}, now, ErrNotAllowed},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := verify(tc.token, []byte(tc.key), tc.allowed, tc.now)
+			if tc.err == nil && err != nil || tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("Expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
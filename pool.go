@@ -0,0 +1,112 @@
+package uriuniq
+
+import (
+	"sync"
+	"time"
+)
+
+// Pool serves IDs from a Generator and keeps a reserve of pre-generated
+// ones. While the EntropySource fails, Next serves the reserve instead of
+// failing, for at most MaxDegraded, and reports the degraded state. Every
+// reserved ID is served once, so uniqueness is kept; the reserve is
+// refilled as soon as the source recovers.
+//
+// Set the fields before the first call to Next. A Pool is safe for
+// concurrent use.
+type Pool struct {
+	// MaxDegraded is how long the reserve may be served after the source
+	// started failing. Zero never serves it.
+	MaxDegraded time.Duration
+	// OnDegraded, if set, is called with the error when the source starts
+	// failing, and OnRecovered when it works again.
+	OnDegraded  func(err error)
+	OnRecovered func()
+
+	g    *Generator
+	size int
+	now  func() time.Time
+
+	mu       sync.Mutex
+	reserve  []string
+	degraded time.Time // Start of the failure, zero if healthy
+	closed   bool
+}
+
+// NewPool creates a Pool with a reserve of size IDs generated with opts.
+func NewPool(opts Options, size int) (*Pool, error) {
+	if size < 0 {
+		return nil, newError(CodeInvalidArgument, "uriuniq: negative pool size")
+	}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pool{g: g, size: size, now: time.Now, reserve: make([]string, 0, size)}
+	for len(p.reserve) < size {
+		id, err := g.Next()
+		if err != nil {
+			return nil, err
+		}
+		p.reserve = append(p.reserve, id)
+	}
+	return p, nil
+}
+
+// Next returns a fresh ID or, while degraded, one from the reserve. It
+// returns the source error once the reserve is empty or MaxDegraded has
+// passed.
+func (p *Pool) Next() (string, error) {
+	if p.isClosed() {
+		return "", ErrClosed
+	}
+	id, err := p.g.Next()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return "", ErrClosed
+	}
+	if err == nil {
+		if !p.degraded.IsZero() {
+			p.degraded = time.Time{}
+			if p.OnRecovered != nil {
+				p.OnRecovered()
+			}
+		}
+		// Top up the reserve by one ID per call.
+		if len(p.reserve) < p.size {
+			if extra, err := p.g.Next(); err == nil {
+				p.reserve = append(p.reserve, extra)
+			}
+		}
+		return id, nil
+	}
+
+	now := p.now()
+	if p.degraded.IsZero() {
+		p.degraded = now
+		if p.OnDegraded != nil {
+			p.OnDegraded(err)
+		}
+	}
+	if len(p.reserve) == 0 || now.Sub(p.degraded) >= p.MaxDegraded {
+		return "", err
+	}
+	id = p.reserve[len(p.reserve)-1]
+	p.reserve = p.reserve[:len(p.reserve)-1]
+	return id, nil
+}
+
+// Degraded reports whether the source is failing and since when.
+func (p *Pool) Degraded() (since time.Time, degraded bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.degraded, !p.degraded.IsZero()
+}
+
+// Reserve returns the number of IDs left in the reserve.
+func (p *Pool) Reserve() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.reserve)
+}
@@ -0,0 +1,100 @@
+package uriuniq
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/laofun/uriuniq/uriuniqtest"
+)
+
+// switchReader reads from crypto/rand unless err is set.
+type switchReader struct{ err error }
+
+func (r *switchReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return rand.Read(p)
+}
+
+// TestPoolDegraded checks serving the reserve while the source fails.
+func TestPoolDegraded(t *testing.T) {
+	src := &switchReader{}
+	opts := NewOpts()
+	opts.EntropySource = src
+	opts.EntropyBufferSize = -1
+	p, err := NewPool(opts, 3)
+	if err != nil {
+		t.Fatalf("NewPool failed: %s", err)
+	}
+	now := time.Unix(1000, 0)
+	p.now = func() time.Time { return now }
+	p.MaxDegraded = time.Minute
+	var degraded, recovered int
+	p.OnDegraded = func(error) { degraded++ }
+	p.OnRecovered = func() { recovered++ }
+
+	failure := errors.New("source down")
+	src.err = failure
+	seen := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		id, err := p.Next()
+		if err != nil || seen[id] {
+			t.Fatalf("Expected a reserved ID, got %q, %v", id, err)
+		}
+		seen[id] = true
+	}
+	if since, ok := p.Degraded(); !ok || !since.Equal(time.Unix(1000, 0)) || degraded != 1 {
+		t.Errorf("Expected degraded since start, got %v, %v, %d", since, ok, degraded)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := p.Next(); !errors.Is(err, failure) {
+		t.Errorf("Expected source error after MaxDegraded, got %v", err)
+	}
+
+	src.err = nil
+	if _, err := p.Next(); err != nil {
+		t.Fatalf("Next failed: %s", err)
+	}
+	if _, ok := p.Degraded(); ok || recovered != 1 {
+		t.Errorf("Expected recovery, got %v, %d", ok, recovered)
+	}
+	if p.Reserve() != 2 {
+		t.Errorf("Expected reserve to be topped up to 2, got %d", p.Reserve())
+	}
+
+	// Once the reserve is empty, errors surface even within MaxDegraded.
+	src.err = failure
+	for i := 0; i < 2; i++ {
+		p.Next()
+	}
+	if _, err := p.Next(); !errors.Is(err, failure) {
+		t.Errorf("Expected source error with empty reserve, got %v", err)
+	}
+}
+
+// TestPoolDrain checks that Drain hands back the reserve and closes.
+func TestPoolDrain(t *testing.T) {
+	uriuniqtest.CheckGoroutines(t)
+	p, err := NewPool(NewOpts(), 3)
+	if err != nil {
+		t.Fatalf("NewPool failed: %s", err)
+	}
+	ids, err := p.Drain(context.Background())
+	if err != nil || len(ids) != 3 {
+		t.Errorf("Expected 3 drained IDs, got %v, %v", ids, err)
+	}
+	if _, err := p.Next(); !errors.Is(err, ErrClosed) || CodeOf(err) != CodeClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+	if err := p.Warmup(context.Background(), 1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed from Warmup, got %v", err)
+	}
+	if ids, _ := p.Drain(context.Background()); len(ids) != 0 || p.Close() != nil {
+		t.Errorf("Expected nothing left after Drain, got %v", ids)
+	}
+}
@@ -0,0 +1,54 @@
+package uriuniq
+
+import "strings"
+
+// DefaultPrefixSeparator separates Options.Prefix from the random part.
+const DefaultPrefixSeparator = "_"
+
+// SplitPrefix splits id at the first DefaultPrefixSeparator into the
+// prefix and the random part, as in "cus", "h8aK3..." for "cus_h8aK3...".
+// ok is false if id has no separator, prefix or random part. Use
+// strings.Cut directly for IDs with another PrefixSeparator.
+func SplitPrefix(id string) (prefix, random string, ok bool) {
+	prefix, random, ok = strings.Cut(id, DefaultPrefixSeparator)
+	if !ok || prefix == "" || random == "" {
+		return "", id, false
+	}
+	return prefix, random, true
+}
+
+// checkPrefix applies the default separator and checks the prefix of
+// opts against charset.
+func checkPrefix(opts Options, charset []byte) (Options, error) {
+	if opts.Prefix == "" {
+		if opts.PrefixSeparator != "" {
+			return opts, newError(CodeInvalidArgument, "uriuniq: PrefixSeparator without Prefix")
+		}
+		return opts, nil
+	}
+	if opts.PrefixSeparator == "" {
+		opts.PrefixSeparator = DefaultPrefixSeparator
+	}
+	if !isURISafe(opts.Prefix) || !isURISafe(opts.PrefixSeparator) {
+		return opts, errorf(CodeInvalidChar, "uriuniq: prefix %q is not URI-safe", opts.Prefix+opts.PrefixSeparator)
+	}
+	if strings.Contains(opts.Prefix, opts.PrefixSeparator) {
+		return opts, errorf(CodeInvalidArgument, "uriuniq: prefix %q contains the separator", opts.Prefix)
+	}
+	if strings.ContainsAny(opts.PrefixSeparator, string(charset)) {
+		return opts, errorf(CodeInvalidArgument, "uriuniq: separator %q is part of the charset", opts.PrefixSeparator)
+	}
+	return opts, nil
+}
+
+// idPrefix returns the prefix with separator put before strings generated
+// with opts, if any.
+func idPrefix(opts Options) string {
+	if opts.Prefix == "" {
+		return ""
+	}
+	if opts.PrefixSeparator == "" {
+		return opts.Prefix + DefaultPrefixSeparator
+	}
+	return opts.Prefix + opts.PrefixSeparator
+}
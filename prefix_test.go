@@ -0,0 +1,87 @@
+package uriuniq
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPrefix checks that prefixed IDs keep the length of the random part
+// and match Pattern.
+func TestPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		prefix string
+	}{
+		{"default separator", Options{Length: 24, Prefix: "cus"}, "cus_"},
+		{"custom separator", Options{Length: 24, Prefix: "inv", PrefixSeparator: "-"}, "inv-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator(tt.opts)
+			if err != nil {
+				t.Fatalf("NewGenerator failed: %s", err)
+			}
+			re, err := Pattern(tt.opts)
+			if err != nil {
+				t.Fatalf("Pattern failed: %s", err)
+			}
+			for _, next := range []func() (string, error){g.Next, func() (string, error) { return Generate(tt.opts) }} {
+				id, err := next()
+				if err != nil {
+					t.Fatalf("Generate failed: %s", err)
+				}
+				if !strings.HasPrefix(id, tt.prefix) || len(id) != len(tt.prefix)+24 || !re.MatchString(id) {
+					t.Errorf("Unexpected ID %q for pattern %s", id, re)
+				}
+			}
+		})
+	}
+	_, min, max := OpenAPISchema(Options{Length: 8, Prefix: "cus"})
+	if min != 12 || max != 12 {
+		t.Errorf("Expected OpenAPI lengths 12, got %d-%d", min, max)
+	}
+}
+
+// TestPrefixErrors checks rejecting prefixes that cannot be split off.
+func TestPrefixErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		code Code
+	}{
+		{"separator in prefix", Options{Prefix: "a_b"}, CodeInvalidArgument},
+		{"separator in charset", Options{Prefix: "a", CustomCharset: "ab_"}, CodeInvalidArgument},
+		{"unsafe prefix", Options{Prefix: "a/b"}, CodeInvalidChar},
+		{"separator only", Options{PrefixSeparator: "-"}, CodeInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Length = 8
+			if _, err := Generate(tt.opts); CodeOf(err) != tt.code {
+				t.Errorf("Expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+// TestSplitPrefix checks splitting IDs at the default separator.
+func TestSplitPrefix(t *testing.T) {
+	tests := []struct {
+		id, prefix, random string
+		ok                 bool
+	}{
+		{"cus_h8aK3", "cus", "h8aK3", true},
+		{"h8aK3", "", "h8aK3", false},
+		{"_h8aK3", "", "_h8aK3", false},
+		{"cus_", "", "cus_", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			prefix, random, ok := SplitPrefix(tt.id)
+			if prefix != tt.prefix || random != tt.random || ok != tt.ok {
+				t.Errorf("Expected %q, %q, %v, got %q, %q, %v", tt.prefix, tt.random, tt.ok, prefix, random, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package uriuniq
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// profiled runs f. If enabled, f runs with the pprof label uriuniq=op and
+// inside the runtime/trace region "uriuniq.op", see Options.ProfileLabels.
+func profiled(enabled bool, op string, f func()) {
+	if !enabled {
+		f()
+		return
+	}
+	pprof.Do(context.Background(), pprof.Labels("uriuniq", op), func(ctx context.Context) {
+		trace.WithRegion(ctx, "uriuniq."+op, f)
+	})
+}
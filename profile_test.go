@@ -0,0 +1,38 @@
+package uriuniq
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+// goroutineLabels returns the goroutine profile, which lists active labels.
+func goroutineLabels(t *testing.T) string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		t.Fatalf("WriteTo failed: %s", err)
+	}
+	return buf.String()
+}
+
+// TestProfiled checks that labels are applied only when enabled.
+func TestProfiled(t *testing.T) {
+	const label = `"uriuniq":"read"`
+	profiled(false, "read", func() {
+		if strings.Contains(goroutineLabels(t), label) {
+			t.Errorf("Unexpected label when disabled")
+		}
+	})
+	profiled(true, "read", func() {
+		if !strings.Contains(goroutineLabels(t), label) {
+			t.Errorf("Expected label %s", label)
+		}
+	})
+
+	opts := NewOpts()
+	opts.ProfileLabels = true
+	if _, err := Generate(opts); err != nil {
+		t.Errorf("Generate failed: %s", err)
+	}
+}
@@ -0,0 +1,24 @@
+package uriuniq
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ProtoRules returns protovalidate string rules matching the strings
+// generated with opts, to be attached to a proto field as
+// (buf.validate.field).string, for example:
+//
+//	{min_len: 16, max_len: 16, pattern: "^[0-9A-Za-z]{16}$"}
+//
+// The rules are also compatible with protoc-gen-validate's
+// (validate.rules).string. See proto/uriuniq/v1/id.proto.
+func ProtoRules(opts Options) (string, error) {
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return "", err
+	}
+	pattern := idPattern(opts, charset)
+	min, max := idLengthRange(opts)
+	return fmt.Sprintf("{min_len: %d, max_len: %d, pattern: %s}", min, max, strconv.Quote(pattern)), nil
+}
@@ -0,0 +1,17 @@
+package uriuniq
+
+import "testing"
+
+// TestProtoRules checks the rules generated from options.
+func TestProtoRules(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 20
+	opts.ExcludeUppercase = true
+	rules, err := ProtoRules(opts)
+	if err != nil {
+		t.Fatalf("ProtoRules failed: %s", err)
+	}
+	if expected := `{min_len: 20, max_len: 20, pattern: "^[0-9a-z]{20}$"}`; rules != expected {
+		t.Errorf("Expected %s, got %s", expected, rules)
+	}
+}
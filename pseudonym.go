@@ -0,0 +1,66 @@
+package uriuniq
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"io"
+	"regexp"
+)
+
+// Pseudonymize returns a stable pseudonym of id under key, of the same
+// length and shape: each digit, lowercase and uppercase letter is replaced
+// by a random char of its class, and other chars are kept. The same id and
+// key always yield the same pseudonym, so pseudonymized logs can still be
+// correlated, but recovering id needs the key. Like random IDs of that
+// shape, two ids may share a pseudonym.
+func Pseudonymize(id string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	stream := deriveStream(mac.Sum(nil), "uriuniq pseudonym")
+
+	out := []byte(id)
+	var b [1]byte
+	for i, c := range out {
+		var class Charset
+		switch {
+		case '0' <= c && c <= '9':
+			class = Numeric
+		case 'a' <= c && c <= 'z':
+			class = Lowercase
+		case 'A' <= c && c <= 'Z':
+			class = Uppercase
+		default:
+			continue
+		}
+		maxByte := byte(255 - 256%len(class))
+		for {
+			stream.Read(b[:])
+			if b[0] <= maxByte {
+				out[i] = class[int(b[0])%len(class)]
+				break
+			}
+		}
+	}
+	return string(out)
+}
+
+// ScrubLog copies r to w line by line, replacing every match of re with its
+// Pseudonymize pseudonym under key, so logs can be shared without the real
+// IDs. Use Pattern without its anchors, or a pattern of your own, for re.
+func ScrubLog(w io.Writer, r io.Reader, re *regexp.Regexp, key []byte) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	bw := bufio.NewWriter(w)
+	for sc.Scan() {
+		line := re.ReplaceAllStringFunc(sc.Text(), func(id string) string {
+			return Pseudonymize(id, key)
+		})
+		bw.WriteString(line)
+		bw.WriteByte('\n')
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
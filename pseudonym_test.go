@@ -0,0 +1,39 @@
+package uriuniq
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestPseudonymize checks that pseudonyms keep the shape and are keyed.
+func TestPseudonymize(t *testing.T) {
+	key := []byte("key")
+	id := "usr_Ab3-xY9z"
+	p := Pseudonymize(id, key)
+	if p != Pseudonymize(id, key) {
+		t.Errorf("Pseudonymize is not stable")
+	}
+	if p == id || p == Pseudonymize(id, []byte("other")) || p == Pseudonymize("usr_Ab3-xY9y", key) {
+		t.Errorf("Pseudonym should depend on key and id: %q", p)
+	}
+	re := regexp.MustCompile(`^[a-z]{3}_[A-Z][a-z][0-9]-[a-z][A-Z][0-9][a-z]$`)
+	if !re.MatchString(p) {
+		t.Errorf("Pseudonym %q does not keep the shape of %q", p, id)
+	}
+}
+
+// TestScrubLog checks that only matched IDs are replaced.
+func TestScrubLog(t *testing.T) {
+	key := []byte("key")
+	in := "GET /orders/ord_h8aK3f1Z user=42\nno ids here\n"
+	var out bytes.Buffer
+	if err := ScrubLog(&out, strings.NewReader(in), regexp.MustCompile(`ord_[0-9A-Za-z]{8}`), key); err != nil {
+		t.Fatalf("ScrubLog failed: %s", err)
+	}
+	want := "GET /orders/" + Pseudonymize("ord_h8aK3f1Z", key) + " user=42\nno ids here\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
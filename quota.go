@@ -0,0 +1,145 @@
+package uriuniq
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults of a QuotaGuard.
+const (
+	DefaultQuotaChunk = 100
+	DefaultQuotaPoll  = 100 * time.Millisecond
+)
+
+// QuotaGuard paces batch generation to the capacity of a downstream
+// system, such as the backend reserving the codes. Concurrent batches take
+// turns in arrival order, one chunk per turn, so a large batch cannot
+// starve a small one. A QuotaGuard is safe for concurrent use.
+type QuotaGuard struct {
+	// Remaining returns the number of IDs the downstream system can take
+	// now. While it returns 0 or less, batches wait.
+	Remaining func(ctx context.Context) (int, error)
+	Chunk     int           // Most IDs per turn, defaults to DefaultQuotaChunk
+	Poll      time.Duration // Wait between calls to Remaining without quota, defaults to DefaultQuotaPoll
+
+	gen     *Generator
+	mu      sync.Mutex
+	busy    bool            // A batch holds the turn
+	waiters []chan struct{} // Batches waiting for the turn, oldest first
+}
+
+// NewQuotaGuard creates a QuotaGuard generating IDs with opts.
+func NewQuotaGuard(opts Options, remaining func(ctx context.Context) (int, error)) (*QuotaGuard, error) {
+	gen, err := NewGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &QuotaGuard{Remaining: remaining, gen: gen}, nil
+}
+
+// Batch generates n IDs in chunks no larger than the remaining quota and
+// passes each chunk to consume, which should hand it to the downstream
+// system. consume runs during the batch's turn, so the next turn sees the
+// quota it used. Batch returns early with the error of consume, Remaining
+// or ctx.
+func (q *QuotaGuard) Batch(ctx context.Context, n int, consume func(ids []string) error) error {
+	chunk := q.Chunk
+	if chunk <= 0 {
+		chunk = DefaultQuotaChunk
+	}
+	poll := q.Poll
+	if poll <= 0 {
+		poll = DefaultQuotaPoll
+	}
+	for n > 0 {
+		if err := q.acquire(ctx); err != nil {
+			return err
+		}
+		k, err := q.turn(ctx, n, chunk, poll, consume)
+		q.release()
+		if err != nil {
+			return err
+		}
+		n -= k
+	}
+	return nil
+}
+
+// turn waits for quota, then generates and consumes one chunk of at most
+// n IDs, returning its size. Waiting holds the turn: no other batch could
+// make progress without quota, and the batch next in line goes first
+// once quota is back.
+func (q *QuotaGuard) turn(ctx context.Context, n, chunk int, poll time.Duration, consume func([]string) error) (int, error) {
+	for {
+		k, err := q.Remaining(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if k > 0 {
+			if k > chunk {
+				k = chunk
+			}
+			if k > n {
+				k = n
+			}
+			ids := make([]string, k)
+			for i := range ids {
+				if ids[i], err = q.gen.Next(); err != nil {
+					return 0, err
+				}
+			}
+			return k, consume(ids)
+		}
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// acquire waits for the turn.
+func (q *QuotaGuard) acquire(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.busy {
+		q.busy = true
+		q.mu.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	q.waiters = append(q.waiters, ch)
+	q.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		for i, w := range q.waiters {
+			if w == ch {
+				q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+				q.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		q.mu.Unlock()
+		// The turn was handed over while ctx was done; pass it on.
+		q.release()
+		return ctx.Err()
+	}
+}
+
+// release hands the turn to the oldest waiting batch.
+func (q *QuotaGuard) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.waiters) == 0 {
+		q.busy = false
+		return
+	}
+	close(q.waiters[0])
+	q.waiters = q.waiters[1:]
+}
@@ -0,0 +1,100 @@
+package uriuniq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestQuotaGuard checks that batches respect the quota and take turns.
+func TestQuotaGuard(t *testing.T) {
+	var mu sync.Mutex
+	quota := 0
+	var order []string
+	q, err := NewQuotaGuard(NewOpts(), func(context.Context) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return quota, nil
+	})
+	if err != nil {
+		t.Fatalf("NewQuotaGuard failed: %s", err)
+	}
+	q.Chunk = 5
+	q.Poll = time.Millisecond
+
+	consume := func(job string) func([]string) error {
+		return func(ids []string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if len(ids) > quota {
+				t.Errorf("Chunk of %d exceeds quota %d", len(ids), quota)
+			}
+			quota -= len(ids)
+			for range ids {
+				order = append(order, job)
+			}
+			return nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range []string{"big", "small"} {
+		n := 20
+		if job == "small" {
+			n = 5
+		}
+		wg.Add(1)
+		go func(job string, n int) {
+			defer wg.Done()
+			if err := q.Batch(context.Background(), n, consume(job)); err != nil {
+				t.Errorf("Batch failed: %s", err)
+			}
+		}(job, n)
+	}
+	// Both batches wait until quota is granted.
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	if len(order) != 0 {
+		t.Errorf("Expected no IDs without quota, got %d", len(order))
+	}
+	quota = 25
+	mu.Unlock()
+	wg.Wait()
+
+	if len(order) != 25 {
+		t.Fatalf("Expected 25 IDs, got %d", len(order))
+	}
+	// The small batch gets a turn within the first two chunks.
+	small := 0
+	for _, job := range order[:10] {
+		if job == "small" {
+			small++
+		}
+	}
+	if small != 5 {
+		t.Errorf("Expected the small batch to finish early, got order %v", order)
+	}
+}
+
+// TestQuotaGuardErrors checks cancellation and callback errors.
+func TestQuotaGuardErrors(t *testing.T) {
+	q, _ := NewQuotaGuard(NewOpts(), func(context.Context) (int, error) { return 0, nil })
+	q.Poll = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Batch(ctx, 1, func([]string) error { return nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline error, got %v", err)
+	}
+
+	fail := errors.New("backend down")
+	q.Remaining = func(context.Context) (int, error) { return 10, nil }
+	if err := q.Batch(context.Background(), 3, func([]string) error { return fail }); !errors.Is(err, fail) {
+		t.Errorf("Expected consume error, got %v", err)
+	}
+	q.Remaining = func(context.Context) (int, error) { return 0, fail }
+	if err := q.Batch(context.Background(), 3, func([]string) error { return nil }); !errors.Is(err, fail) {
+		t.Errorf("Expected Remaining error, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package uriuniq
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by Recover in place of a panic.
+type PanicError struct {
+	Value any    // The value passed to panic
+	Stack []byte // Stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("uriuniq: internal error: %v", e.Value)
+}
+
+// ErrorCode returns CodeInternal.
+func (e *PanicError) ErrorCode() Code { return CodeInternal }
+
+// Recover runs f and returns its error. If f panics, the panic is
+// recovered and returned as a *PanicError, after calling onPanic, if not
+// nil, to log or report the stack. Servers and command-line tools wrap
+// each request in Recover so a bug cannot take the process down.
+func Recover(f func() error, onPanic func(*PanicError)) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			perr := &PanicError{Value: v, Stack: debug.Stack()}
+			if onPanic != nil {
+				onPanic(perr)
+			}
+			err = perr
+		}
+	}()
+	return f()
+}
@@ -0,0 +1,94 @@
+package uriuniq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// TestRecover checks that panics become errors and reach the hook.
+func TestRecover(t *testing.T) {
+	var hooked *PanicError
+	err := Recover(func() error { panic("boom") }, func(e *PanicError) { hooked = e })
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value != "boom" || hooked != perr {
+		t.Fatalf("Expected PanicError, got %v", err)
+	}
+	if !bytes.Contains(perr.Stack, []byte("TestRecover")) {
+		t.Errorf("Stack does not show the panicking function")
+	}
+	if CodeOf(err) != CodeInternal {
+		t.Errorf("Expected %s, got %s", CodeInternal, CodeOf(err))
+	}
+
+	want := errors.New("plain")
+	if err := Recover(func() error { return want }, nil); err != want {
+		t.Errorf("Expected the error of f, got %v", err)
+	}
+}
+
+// FuzzEntryPoints asserts that the entry points of the package never panic,
+// whatever the Options and input.
+func FuzzEntryPoints(f *testing.F) {
+	f.Add([]byte{16, 0, 0, 0, 0, 0, 0}, "", "abc")
+	f.Add([]byte{0xff, 3, 2, 1, 4, 9, 1}, "aAbB/", "aB/")
+	f.Add([]byte{2, 0xff, 0xff, 7, 0x80, 1, 2}, "ab", "")
+
+	f.Fuzz(func(t *testing.T, config []byte, charset, input string) {
+		config = append(config, make([]byte, 7)...)
+		opts := Options{
+			Length:           int(int8(config[0])),
+			ExcludeNumeric:   config[1]&1 != 0,
+			ExcludeLowercase: config[1]&2 != 0,
+			ExcludeUppercase: config[1]&4 != 0,
+			Transform:        Transform(int8(config[2])),
+			Sampler:          Sampler(int8(config[3])),
+			MinLength:        int(int8(config[4])),
+			MaxLength:        int(int8(config[5])),
+			LengthUnit:       LengthUnit(int8(config[6]) % 4),
+			MaxBadReads:      int(binary.BigEndian.Uint16(config[1:3]) % 4),
+			CustomCharset:    Charset(charset),
+		}
+		if config[1]&8 != 0 {
+			opts.InstanceLabel = input
+			opts.FingerprintChars = int(int8(config[6])) % 4
+		}
+
+		err := Recover(func() error {
+			Generate(opts)
+			if g, err := NewGenerator(opts); err == nil {
+				g.Next()
+			}
+			GenerateArena(opts, 3)
+			GeneratePadded(int(config[0]), '#', opts)
+			Entropy(opts)
+			Pattern(opts)
+			OpenAPISchema(opts)
+			ProtoRules(opts)
+			if format, err := OptionsFormat("fuzz", opts); err == nil {
+				JSONSchema(format)
+				Canonical(input, format)
+			}
+			ParsePadded(input, int(config[0]), '#', opts)
+			ConvertLength(int(int8(config[0])), LengthUnit(config[6]%4), LengthUnit(config[5]%4), opts)
+			EncodeBytes([]byte(input), opts)
+			DecodeBytes(input, int(config[0]%8), opts)
+			DecodeEntropy(input)
+			ParseAny(input)
+			Split(Charset(charset), input)
+			Canonical(input, FormatULID)
+			Canonical(input, FormatUUIDv7)
+			GenerateIDNSlug(charset, input, opts)
+			HashedTokenAlg(input)
+			Bucket(input, int(int8(config[0])), []byte(charset))
+			Encode([]byte(input), Charset(charset))
+			Decode(input, Charset(charset))
+			return nil
+		}, nil)
+		var perr *PanicError
+		if errors.As(err, &perr) {
+			t.Fatalf("Panic with %+v: %v\n%s", opts, perr.Value, perr.Stack)
+		}
+	})
+}
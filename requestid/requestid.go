@@ -0,0 +1,88 @@
+// Package requestid carries a request ID through a context and propagates
+// it to outgoing calls, so one ID can be followed across services.
+//
+// Example:
+//
+//	ctx := requestid.NewContext(ctx, id)
+//	client := &http.Client{Transport: &requestid.Transport{}}
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	resp, err := client.Do(req) // Sent with X-Request-ID: id
+//
+// The requestidgrpc module holds the gRPC client interceptors propagating
+// the ID with Metadata, so this module does not depend on gRPC.
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/laofun/uriuniq"
+)
+
+// DefaultHeader is the HTTP header carrying the ID.
+const DefaultHeader = "X-Request-ID"
+
+// DefaultMetadataKey is the gRPC metadata key carrying the ID. Metadata
+// keys are lowercase.
+const DefaultMetadataKey = "x-request-id"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the ID carried by ctx, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// New creates a request ID with the default Options.
+func New() (string, error) {
+	return ids.Next()
+}
+
+// ids generates the IDs of New.
+var ids, _ = uriuniq.NewGenerator(uriuniq.NewOpts())
+
+// Metadata returns the gRPC metadata pair for the ID carried by ctx.
+// key defaults to DefaultMetadataKey and is lowercased.
+func Metadata(ctx context.Context, key string) (k, v string, ok bool) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", "", false
+	}
+	if key == "" {
+		key = DefaultMetadataKey
+	}
+	return strings.ToLower(key), id, true
+}
+
+// Transport is an http.RoundTripper that sets Header on outgoing requests
+// to the ID carried by their context. Requests that already have the
+// header or carry no ID are sent unchanged.
+type Transport struct {
+	Base   http.RoundTripper // Defaults to http.DefaultTransport
+	Header string            // Defaults to DefaultHeader
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	header := t.Header
+	if header == "" {
+		header = DefaultHeader
+	}
+	if id, ok := FromContext(req.Context()); ok && req.Header.Get(header) == "" {
+		// A RoundTripper must not modify the request it was given.
+		req = req.Clone(req.Context())
+		req.Header.Set(header, id)
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
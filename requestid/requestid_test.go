@@ -0,0 +1,82 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/laofun/uriuniq"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// TestTransport checks that the context's ID is sent.
+func TestTransport(t *testing.T) {
+	var got string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get("X-Trace")
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	client := &http.Client{Transport: &Transport{Base: base, Header: "X-Trace"}}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		header string
+		want   string
+	}{
+		{"From Context", NewContext(context.Background(), "abc"), "", "abc"},
+		{"No ID", context.Background(), "", ""},
+		{"Header Kept", NewContext(context.Background(), "abc"), "set", "set"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(tc.ctx, http.MethodGet, "http://example.com", nil)
+			if tc.header != "" {
+				req.Header.Set("X-Trace", tc.header)
+			}
+			if _, err := client.Do(req); err != nil {
+				t.Fatalf("Do failed: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("Expected header %q, got %q", tc.want, got)
+			}
+			if tc.header == "" && req.Header.Get("X-Trace") != "" {
+				t.Errorf("Transport modified the caller's request")
+			}
+		})
+	}
+}
+
+// TestMetadata checks the gRPC metadata pair.
+func TestMetadata(t *testing.T) {
+	ctx := NewContext(context.Background(), "abc")
+	if k, v, ok := Metadata(ctx, ""); !ok || k != DefaultMetadataKey || v != "abc" {
+		t.Errorf("Unexpected pair %q %q %v", k, v, ok)
+	}
+	if k, _, _ := Metadata(ctx, "X-Correlation-ID"); k != "x-correlation-id" {
+		t.Errorf("Expected lowercase key, got %q", k)
+	}
+	if _, _, ok := Metadata(context.Background(), ""); ok {
+		t.Errorf("Expected no pair without an ID")
+	}
+	if id, err := New(); err != nil || len(id) != 16 {
+		t.Errorf("Unexpected ID %q, %v", id, err)
+	}
+}
+
+// TestNewNotDeprecated checks that New does not count as a call of the
+// deprecated Generate.
+func TestNewNotDeprecated(t *testing.T) {
+	calls := 0
+	uriuniq.SetDeprecationHook(func(string) { calls++ })
+	defer uriuniq.SetDeprecationHook(nil)
+	if _, err := New(); err != nil {
+		t.Fatalf("New failed: %s", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no deprecated calls, got %d", calls)
+	}
+}
@@ -0,0 +1,45 @@
+// Package requestidgrpc holds gRPC client interceptors sending the request
+// ID carried by the context of a call, as requestid.Transport does for
+// HTTP. It is a module of its own, so the uriuniq module does not depend
+// on gRPC.
+//
+// Example:
+//
+//	conn, err := grpc.NewClient(target,
+//	    grpc.WithUnaryInterceptor(requestidgrpc.UnaryClientInterceptor("")),
+//	    grpc.WithStreamInterceptor(requestidgrpc.StreamClientInterceptor("")))
+package requestidgrpc
+
+import (
+	"context"
+
+	"github.com/laofun/uriuniq/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor returns an interceptor adding the request ID of
+// the context of each call to its outgoing metadata under key, which
+// defaults to requestid.DefaultMetadataKey. Calls whose context carries no
+// ID are sent unchanged.
+func UnaryClientInterceptor(key string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx, key), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor is UnaryClientInterceptor for streams.
+func StreamClientInterceptor(key string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx, key), desc, cc, method, opts...)
+	}
+}
+
+// outgoing returns ctx with the request ID it carries appended to its
+// outgoing metadata under key.
+func outgoing(ctx context.Context, key string) context.Context {
+	if k, v, ok := requestid.Metadata(ctx, key); ok {
+		return metadata.AppendToOutgoingContext(ctx, k, v)
+	}
+	return ctx
+}
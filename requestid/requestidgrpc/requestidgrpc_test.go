@@ -0,0 +1,54 @@
+package requestidgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/laofun/uriuniq/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TestClientInterceptors checks the metadata sent by both interceptors,
+// with the default and a custom key, and without a request ID.
+func TestClientInterceptors(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		id   string
+		want string // Metadata key expected to carry id, "" for none
+	}{
+		{"Default", "", "req-1", requestid.DefaultMetadataKey},
+		{"Custom", "X-Trace-ID", "req-2", "x-trace-id"},
+		{"None", "", "", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.id != "" {
+				ctx = requestid.NewContext(ctx, tc.id)
+			}
+			check := func(ctx context.Context) {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				if tc.want == "" {
+					if len(md) != 0 {
+						t.Errorf("Expected no metadata, got %v", md)
+					}
+				} else if got := md.Get(tc.want); len(got) != 1 || got[0] != tc.id {
+					t.Errorf("Expected %s: %s, got %v", tc.want, tc.id, md)
+				}
+			}
+
+			unary := UnaryClientInterceptor(tc.key)
+			unary(ctx, "/svc/Method", nil, nil, nil, func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				check(ctx)
+				return nil
+			})
+			stream := StreamClientInterceptor(tc.key)
+			stream(ctx, &grpc.StreamDesc{}, nil, "/svc/Stream", func(ctx context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, _ string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+				check(ctx)
+				return nil, nil
+			})
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package requestidzap is the zap counterpart of requestid.Handler: a
+// zapcore.Core adding a unique event ID to every entry, and the request ID
+// of a context passed as a Context field. It is a module of its own, so
+// the uriuniq module does not depend on zap.
+//
+// Example:
+//
+//	core, err := requestidzap.NewCore(zapcore.NewCore(enc, out, zap.InfoLevel), uriuniq.NewOpts())
+//	logger := zap.New(core)
+//	// In a handler:
+//	logger.Info("charged", requestidzap.Context(r.Context()))
+package requestidzap
+
+import (
+	"context"
+
+	"github.com/laofun/uriuniq"
+	"github.com/laofun/uriuniq/requestid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// contextKey is the key of the fields made by Context. They are of
+// zapcore.SkipType, so encoders drop them if they reach one.
+const contextKey = "requestidzap.context"
+
+// Context returns a field carrying ctx, which a Core replaces with the
+// requestid.RequestIDKey of the request ID ctx carries, if any. zap
+// entries have no context of their own, so this is how it reaches a Core.
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// Core is a zapcore.Core that adds a unique requestid.EventIDKey to every
+// entry it writes, and the request IDs of Context fields, before passing
+// the entry on. Like any field, they are placed in the current namespace.
+type Core struct {
+	inner zapcore.Core
+	gen   *uriuniq.Generator
+}
+
+// NewCore wraps inner, drawing event IDs with opts.
+func NewCore(inner zapcore.Core, opts uriuniq.Options) (*Core, error) {
+	gen, err := uriuniq.NewGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Core{inner: inner, gen: gen}, nil
+}
+
+// Enabled implements zapcore.Core.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return c.inner.Enabled(level)
+}
+
+// With implements zapcore.Core.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	return &Core{inner: c.inner.With(requestFields(fields)), gen: c.gen}
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core. An entry is still written, without an
+// event ID, if the ID cannot be generated.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields = requestFields(fields)
+	if id, err := c.gen.Next(); err == nil {
+		// Copy rather than append into the caller's array.
+		fields = append(fields[:len(fields):len(fields)], zap.String(requestid.EventIDKey, id))
+	}
+	return c.inner.Write(ent, fields)
+}
+
+// Sync implements zapcore.Core.
+func (c *Core) Sync() error {
+	return c.inner.Sync()
+}
+
+// requestFields returns fields with every Context field replaced by the
+// request ID its context carries, or dropped if it carries none. fields
+// is returned as is if it holds no Context field.
+func requestFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		if f.Type != zapcore.SkipType || f.Key != contextKey {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)), fields[:i]...)
+		}
+		if ctx, ok := f.Interface.(context.Context); ok {
+			if id, ok := requestid.FromContext(ctx); ok {
+				out = append(out, zap.String(requestid.RequestIDKey, id))
+			}
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package requestidzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/laofun/uriuniq"
+	"github.com/laofun/uriuniq/requestid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestCore checks that event and request IDs are added to entries.
+func TestCore(t *testing.T) {
+	inner, logs := observer.New(zap.InfoLevel)
+	core, err := NewCore(inner, uriuniq.NewOpts())
+	if err != nil {
+		t.Fatalf("NewCore failed: %s", err)
+	}
+	logger := zap.New(core).With(zap.String("svc", "api"))
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	logger.Info("first", Context(ctx))
+	logger.Info("second", Context(context.Background()))
+	logger.With(Context(ctx)).Info("third")
+	logger.Debug("dropped")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	seen := make(map[string]bool)
+	for i, want := range []string{"req-1", "", "req-1"} {
+		fields := entries[i].ContextMap()
+		if got, _ := fields[requestid.RequestIDKey].(string); got != want || fields["svc"] != "api" {
+			t.Errorf("Unexpected fields of entry %d: %v", i, fields)
+		}
+		if _, ok := fields[contextKey]; ok {
+			t.Errorf("Context field left in entry %d: %v", i, fields)
+		}
+		id, _ := fields[requestid.EventIDKey].(string)
+		if !uriuniq.Verify(id, uriuniq.NewOpts()) || seen[id] {
+			t.Errorf("Expected a unique event ID, got %q", id)
+		}
+		seen[id] = true
+	}
+}
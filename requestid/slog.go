@@ -0,0 +1,63 @@
+//go:build go1.21
+
+package requestid
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/laofun/uriuniq"
+)
+
+// Attribute keys added by Handler.
+const (
+	EventIDKey   = "event_id"
+	RequestIDKey = "request_id"
+)
+
+// Handler is a slog.Handler that adds a unique EventIDKey to every record,
+// and the RequestIDKey of the record's context if it carries one, before
+// passing it on. Like any record attribute, they are placed in the
+// handler's current group. The requestidzap module holds the same for
+// zap, so this module does not depend on it.
+type Handler struct {
+	inner slog.Handler
+	gen   *uriuniq.Generator
+}
+
+// NewHandler wraps inner, drawing event IDs with opts.
+func NewHandler(inner slog.Handler, opts uriuniq.Options) (*Handler, error) {
+	gen, err := uriuniq.NewGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{inner: inner, gen: gen}, nil
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler. A record is still logged, without an
+// event ID, if the ID cannot be generated.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	if id, err := h.gen.Next(); err == nil {
+		r.AddAttrs(slog.String(EventIDKey, id))
+	}
+	if id, ok := FromContext(ctx); ok {
+		r.AddAttrs(slog.String(RequestIDKey, id))
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs), gen: h.gen}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name), gen: h.gen}
+}
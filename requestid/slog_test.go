@@ -0,0 +1,45 @@
+//go:build go1.21
+
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestHandler checks that event and request IDs are added to records.
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewHandler(slog.NewJSONHandler(&buf, nil), uriuniq.NewOpts())
+	if err != nil {
+		t.Fatalf("NewHandler failed: %s", err)
+	}
+	logger := slog.New(h).With("svc", "api")
+	logger.InfoContext(NewContext(context.Background(), "req-1"), "first")
+	logger.Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	var first, second map[string]any
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+	if first[RequestIDKey] != "req-1" || first["svc"] != "api" {
+		t.Errorf("Unexpected first record %v", first)
+	}
+	if _, ok := second[RequestIDKey]; ok {
+		t.Errorf("Unexpected request ID without context: %v", second)
+	}
+	id1, _ := first[EventIDKey].(string)
+	id2, _ := second[EventIDKey].(string)
+	if len(id1) != uriuniq.DefaultLength || id1 == id2 {
+		t.Errorf("Expected distinct event IDs, got %q and %q", id1, id2)
+	}
+}
@@ -0,0 +1,71 @@
+package uriuniq
+
+import (
+	"crypto/rand"
+	"io"
+	"time"
+)
+
+// ErrEntropyExhausted is wrapped by the errors returned when no usable
+// entropy could be read: when reads of the EntropySource kept failing
+// through Options.EntropyRetries retries, with CodeEntropySource and the
+// last read error also wrapped, and when Options.MaxBadReads reads in a
+// row gave no chars, with CodeTooManyBadReads.
+var ErrEntropyExhausted = newError(CodeEntropySource, "uriuniq: entropy exhausted")
+
+// entropyReader reads from the EntropySource of opts, retrying failed
+// reads with the policy of opts. It is used as a value, so the hot paths
+// do not allocate.
+type entropyReader struct {
+	src     io.Reader
+	retries int
+	backoff time.Duration
+}
+
+// newEntropyReader returns the entropyReader of opts, reading from
+// crypto/rand by default.
+func newEntropyReader(opts Options) entropyReader {
+	src := opts.EntropySource
+	if src == nil {
+		src = rand.Reader
+	}
+	return entropyReader{src: src, retries: opts.EntropyRetries, backoff: opts.EntropyRetryBackoff}
+}
+
+// Read reads into p. A read returning bytes succeeds; one failing without
+// any is retried up to r.retries times in a row, waiting r.backoff before
+// the first retry and twice as long before each further one.
+func (r entropyReader) Read(p []byte) (int, error) {
+	backoff := r.backoff
+	for failures := 0; ; failures++ {
+		n, err := r.src.Read(p)
+		if n > 0 || err == nil {
+			return n, nil
+		}
+		if failures >= r.retries {
+			return 0, errorf(CodeEntropySource, "%w after %d attempts: %w", ErrEntropyExhausted, failures+1, err)
+		}
+		if backoff > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+// ReadFull fills p as io.ReadFull does, without boxing r.
+func (r entropyReader) ReadFull(p []byte) error {
+	for filled := 0; filled < len(p); {
+		n, err := r.Read(p[filled:])
+		if err != nil {
+			return err
+		}
+		filled += n
+	}
+	return nil
+}
+
+// errBadReads returns the error for maxBadReads reads in a row that gave
+// no chars.
+func errBadReads(maxBadReads int) error {
+	return errorf(CodeTooManyBadReads, "%w: %d reads in a row gave no chars", ErrEntropyExhausted, maxBadReads)
+}
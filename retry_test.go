@@ -0,0 +1,105 @@
+package uriuniq
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyReader fails its first failures reads, then reads from crypto/rand.
+type flakyReader struct {
+	failures int
+	reads    int
+}
+
+func (r *flakyReader) Read(p []byte) (int, error) {
+	r.reads++
+	if r.reads <= r.failures {
+		return 0, errFlaky
+	}
+	return rand.Read(p)
+}
+
+var errFlaky = errors.New("flaky")
+
+// patternReader serves one byte per read, cycling through bytes.
+type patternReader struct {
+	bytes []byte
+	i     int
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	p[0] = r.bytes[r.i%len(r.bytes)]
+	r.i++
+	return 1, nil
+}
+
+// TestEntropyRetries checks that failed reads are retried as configured,
+// with backoff, and reported as ErrEntropyExhausted wrapping the cause.
+func TestEntropyRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		retries  int
+		ok       bool
+	}{
+		{"No Failures", 0, 0, true},
+		{"No Retries", 1, 0, false},
+		{"Recovered", 2, 2, true},
+		{"Exhausted", 3, 2, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := &flakyReader{failures: tc.failures}
+			_, err := Generate(Options{Length: 8, EntropySource: src, EntropyRetries: tc.retries})
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("Generate failed: %s", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrEntropyExhausted) || !errors.Is(err, errFlaky) || CodeOf(err) != CodeEntropySource {
+				t.Errorf("Expected ErrEntropyExhausted wrapping the read error, got %v", err)
+			}
+			if src.reads != tc.retries+1 {
+				t.Errorf("Expected %d reads, got %d", tc.retries+1, src.reads)
+			}
+		})
+	}
+
+	start := time.Now()
+	src := &flakyReader{failures: 2}
+	opts := Options{Length: 8, EntropySource: src, EntropyRetries: 2, EntropyRetryBackoff: 5 * time.Millisecond}
+	if _, err := Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("Expected backoff of 5ms and 10ms, took %s", elapsed)
+	}
+}
+
+// TestMaxBadReads checks that MaxBadReads limits the reads in a row that
+// give no chars, not the reads of a whole call.
+func TestMaxBadReads(t *testing.T) {
+	// 0xff is rejected for 62 chars, 0x00 is accepted.
+	src := &patternReader{bytes: []byte{0xff, 0x00}}
+	if _, err := Generate(Options{Length: 16, MaxBadReads: 2, EntropySource: src}); err != nil {
+		t.Errorf("Expected alternating reads to succeed, got %v", err)
+	}
+
+	samplers := []Sampler{SamplerRejection, SamplerArithmetic}
+	for _, sampler := range samplers {
+		src := &patternReader{bytes: []byte{0xff}}
+		_, err := Generate(Options{Length: 1, MaxBadReads: 3, Sampler: sampler, EntropySource: src})
+		if !errors.Is(err, ErrEntropyExhausted) || CodeOf(err) != CodeTooManyBadReads {
+			t.Errorf("Sampler %d: expected CodeTooManyBadReads, got %v", sampler, err)
+		}
+		if src.i != 3 {
+			t.Errorf("Sampler %d: expected 3 reads, got %d", sampler, src.i)
+		}
+	}
+}
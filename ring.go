@@ -0,0 +1,145 @@
+package uriuniq
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultReplicas is the number of points of each backend on a Ring.
+const DefaultReplicas = 160
+
+// Ring maps IDs to backends by consistent hashing, for sharding storage by
+// ID: adding or removing a backend moves only the IDs of that backend.
+// Positions on the ring are the first 8 bytes, big-endian, of the SHA-256
+// of the ID or of "backend#i" for point i of a backend, so other services
+// can compute the same mapping.
+//
+// Locate is a pure lookup. Place additionally bounds the load: it skips
+// backends already holding more than LoadFactor times the mean number of
+// placed IDs, and Release undoes a placement. A Ring is safe for
+// concurrent use.
+type Ring struct {
+	mu         sync.Mutex
+	replicas   int
+	loadFactor float64
+	points     []ringPoint // Sorted by hash
+	loads      map[string]int
+	placed     int
+}
+
+type ringPoint struct {
+	hash    uint64
+	backend string
+}
+
+// NewRing creates a Ring of backends with replicas points each, or
+// DefaultReplicas if replicas <= 0. Place allows loadFactor times the mean
+// load, at least 1; 1.25 is a common choice.
+func NewRing(backends []string, replicas int, loadFactor float64) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+	if loadFactor < 1 {
+		loadFactor = 1
+	}
+	r := &Ring{replicas: replicas, loadFactor: loadFactor, loads: make(map[string]int)}
+	for _, b := range backends {
+		r.Add(b)
+	}
+	return r
+}
+
+// Add adds backend to the ring, if it is not there yet.
+func (r *Ring) Add(backend string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.loads[backend]; ok {
+		return
+	}
+	r.loads[backend] = 0
+	for i := 0; i < r.replicas; i++ {
+		r.points = append(r.points, ringPoint{ringHash(backend + "#" + strconv.Itoa(i)), backend})
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i].hash < r.points[j].hash })
+}
+
+// Remove removes backend and its placements from the ring.
+func (r *Ring) Remove(backend string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	load, ok := r.loads[backend]
+	if !ok {
+		return
+	}
+	r.placed -= load
+	delete(r.loads, backend)
+	points := r.points[:0]
+	for _, p := range r.points {
+		if p.backend != backend {
+			points = append(points, p)
+		}
+	}
+	r.points = points
+}
+
+// Locate returns the backend of id, or "" if the ring is empty.
+func (r *Ring) Locate(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.points) == 0 {
+		return ""
+	}
+	return r.points[r.search(id)].backend
+}
+
+// Place returns the first backend clockwise from id that is below the load
+// bound and records the placement, or "" if the ring is empty.
+func (r *Ring) Place(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.points) == 0 {
+		return ""
+	}
+	bound := int(math.Ceil(r.loadFactor * float64(r.placed+1) / float64(len(r.loads))))
+	start := r.search(id)
+	for i := 0; i < len(r.points); i++ {
+		b := r.points[(start+i)%len(r.points)].backend
+		if r.loads[b] < bound {
+			r.loads[b]++
+			r.placed++
+			return b
+		}
+	}
+	// Unreachable: the bound is at least the mean load.
+	return r.points[start].backend
+}
+
+// Release undoes one placement on backend.
+func (r *Ring) Release(backend string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loads[backend] > 0 {
+		r.loads[backend]--
+		r.placed--
+	}
+}
+
+// search returns the index of the first point at or after the hash of id.
+func (r *Ring) search(id string) int {
+	h := ringHash(id)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return i
+}
+
+// ringHash returns the position of s on a Ring.
+func ringHash(s string) uint64 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint64(sum[:8])
+}
@@ -0,0 +1,66 @@
+package uriuniq
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestRingMovement checks that membership changes move few IDs.
+func TestRingMovement(t *testing.T) {
+	r := NewRing([]string{"a", "b", "c", "d"}, 0, 0)
+	const ids = 10000
+	before := make([]string, ids)
+	counts := make(map[string]int)
+	for i := range before {
+		before[i] = r.Locate(fmt.Sprintf("id-%d", i))
+		counts[before[i]]++
+	}
+	for b, c := range counts {
+		if c < ids/4*7/10 || c > ids/4*13/10 {
+			t.Errorf("Backend %s has %d IDs", b, c)
+		}
+	}
+
+	r.Add("e")
+	moved := 0
+	for i := range before {
+		after := r.Locate(fmt.Sprintf("id-%d", i))
+		if after != before[i] {
+			moved++
+			if after != "e" {
+				t.Fatalf("ID moved between old backends: %s to %s", before[i], after)
+			}
+		}
+	}
+	if moved < ids/5*7/10 || moved > ids/5*13/10 {
+		t.Errorf("Expected about a fifth of IDs to move, got %d", moved)
+	}
+
+	r.Remove("e")
+	for i := range before {
+		if got := r.Locate(fmt.Sprintf("id-%d", i)); got != before[i] {
+			t.Fatalf("Removing e did not restore %s", got)
+		}
+	}
+	if NewRing(nil, 0, 0).Locate("id") != "" {
+		t.Errorf("Expected no backend on an empty ring")
+	}
+}
+
+// TestRingPlace checks the load bound of placements.
+func TestRingPlace(t *testing.T) {
+	r := NewRing([]string{"a", "b", "c"}, 4, 1.25)
+	loads := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		loads[r.Place(fmt.Sprintf("id-%d", i))]++
+	}
+	for b, l := range loads {
+		if l > 1250 {
+			t.Errorf("Backend %s has load %d above the bound", b, l)
+		}
+	}
+	r.Release("a")
+	if r.loads["a"] != loads["a"]-1 || r.placed != 2999 {
+		t.Errorf("Release did not undo a placement")
+	}
+}
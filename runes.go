@@ -0,0 +1,273 @@
+package uriuniq
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RuneClass selects the runes GenerateRunes draws from: every valid rune in
+// any of the Tables that also passes Filter, if set. For example, letters of
+// Latin-1:
+//
+//	uriuniq.RuneClass{
+//	    Tables: []*unicode.RangeTable{unicode.Letter},
+//	    Filter: func(r rune) bool { return r <= unicode.MaxLatin1 },
+//	}
+type RuneClass struct {
+	Tables []*unicode.RangeTable
+	Filter func(rune) bool
+}
+
+// RuneResult is a string generated by GenerateRunes.
+type RuneResult struct {
+	Value         string
+	Runes         int // Length in runes
+	Bytes         int // Length in UTF-8 bytes
+	EncodedLength int // Length once percent-encoded for a URI
+}
+
+// maxRuneAlphabet bounds the number of runes a RuneClass may hold.
+const maxRuneAlphabet = 1 << 21
+
+// GenerateRunes creates a random string of opts.Length runes drawn
+// uniformly from class, and reports its percent-encoded length. Only
+// Length, MaxBadReads, EntropySource, its retry policy and Sensitive are
+// used from opts.
+func GenerateRunes(class RuneClass, opts Options) (RuneResult, error) {
+	if opts.Length <= 0 {
+		opts.Length = DefaultLength
+	}
+	if opts.MaxBadReads <= 0 {
+		opts.MaxBadReads = DefaultMaxBadReads
+	}
+	alphabet := class.runes()
+	if len(alphabet) < 2 {
+		return RuneResult{}, newError(CodeCharsetSize, "uriuniq: rune class needs at least 2 runes")
+	}
+
+	runes, err := randRunes(opts, alphabet)
+	if err != nil {
+		return RuneResult{}, err
+	}
+	value := string(runes)
+	return RuneResult{
+		Value:         value,
+		Runes:         len(runes),
+		Bytes:         len(value),
+		EncodedLength: percentEncodedLength(value),
+	}, nil
+}
+
+// runes lists the runes of the class in ascending order.
+func (c RuneClass) runes() []rune {
+	var out []rune
+	seen := make(map[rune]bool)
+	add := func(r rune) {
+		if utf8.ValidRune(r) && !seen[r] && (c.Filter == nil || c.Filter(r)) {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+	for _, table := range c.Tables {
+		for _, r16 := range table.R16 {
+			for r := rune(r16.Lo); r <= rune(r16.Hi) && len(out) < maxRuneAlphabet; r += rune(r16.Stride) {
+				add(r)
+			}
+		}
+		for _, r32 := range table.R32 {
+			for r := rune(r32.Lo); r <= rune(r32.Hi) && len(out) < maxRuneAlphabet; r += rune(r32.Stride) {
+				add(r)
+			}
+		}
+	}
+	return out
+}
+
+// randRunes draws opts.Length runes uniformly from alphabet, rejecting
+// 32-bit samples that would bias the result.
+func randRunes(opts Options, alphabet []rune) ([]rune, error) {
+	src := newEntropyReader(opts)
+	n := uint32(len(alphabet))
+	limit := (1<<32 - 1) / n * n // Samples at or above limit are rejected
+
+	out := make([]rune, 0, opts.Length)
+	buffer := make([]byte, 4*(opts.Length+opts.Length/4+2))
+	if opts.Sensitive {
+		defer wipe(buffer)
+	}
+	consumed, reads, badReads := 0, 0, 0
+	for ; len(out) < opts.Length; reads++ {
+		if badReads >= opts.MaxBadReads {
+			return nil, errBadReads(opts.MaxBadReads)
+		}
+		if err := src.ReadFull(buffer); err != nil {
+			return nil, err
+		}
+		before := len(out)
+		for i := 0; i+4 <= len(buffer) && len(out) < opts.Length; i += 4 {
+			consumed += 4
+			if v := binary.BigEndian.Uint32(buffer[i:]); v < limit {
+				out = append(out, alphabet[v%n])
+			}
+		}
+		if len(out) > before {
+			badReads = 0
+		} else {
+			badReads++
+		}
+	}
+	if opts.Metrics != nil {
+		opts.Metrics.Sampled(consumed, consumed-4*len(out), reads-1)
+	}
+	return out, nil
+}
+
+// percentEncodedLength returns the length of s once every byte other than
+// the URI unreserved chars is percent-encoded.
+func percentEncodedLength(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			n++
+		} else {
+			n += 3
+		}
+	}
+	return n
+}
+
+// isRuneCharset reports whether cs is valid UTF-8 with multi-byte runes,
+// which Generate, Generators and Validate sample as whole runes. Other
+// charsets take the byte path.
+func isRuneCharset(cs Charset) bool {
+	for i := 0; i < len(cs); i++ {
+		if cs[i] >= utf8.RuneSelf {
+			return utf8.ValidString(string(cs))
+		}
+	}
+	return false
+}
+
+// prepareRunes applies defaults to opts with a CustomCharset of multi-byte
+// runes and returns its runes. Only the options that do not map chars to
+// bytes are supported.
+func prepareRunes(opts Options) (Options, []rune, error) {
+	if opts.ExcludeNumeric || opts.ExcludeLowercase || opts.ExcludeUppercase || opts.ExcludeAmbiguous ||
+		opts.Transform != TransformNone || opts.CaseInsensitiveSafe || opts.DNSLabel || opts.NoLeadingDigit ||
+		opts.CheckChar || opts.GroupSize > 0 || opts.InstanceLabel != "" || opts.NodeChars > 0 || composed(opts) ||
+		opts.Blocklist != nil || opts.Sampler == SamplerArithmetic || opts.MaxLength > 0 || opts.LengthUnit != Characters {
+		return opts, nil, newError(CodeInvalidArgument, "uriuniq: option not supported with a multi-byte charset")
+	}
+	alphabet := []rune(string(opts.CustomCharset))
+	if len(alphabet) < 2 {
+		return opts, nil, newError(CodeCharsetSize, "uriuniq: charset needs at least 2 chars")
+	}
+	seen := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		if seen[r] {
+			return opts, nil, errorf(CodeDuplicateChar, "uriuniq: duplicate char %q in charset", r)
+		}
+		seen[r] = true
+	}
+	if opts.Escape == EscapeReject {
+		if err := checkCharset(string(opts.CustomCharset), unreserved); err != nil {
+			return opts, nil, fmt.Errorf("uriuniq: charset needs escaping: %w", err)
+		}
+	}
+	if opts.Escape != EscapePercent {
+		if opts.Strict {
+			return opts, nil, fmt.Errorf("%w: %v", ErrUnsafeCharset, checkCharset(string(opts.CustomCharset), uriSafe))
+		}
+		logf(opts, "uriuniq: CustomCharset %q contains chars that are not URI-safe", opts.CustomCharset)
+	}
+	var err error
+	if opts, err = checkPrefix(opts, []byte(opts.CustomCharset)); err != nil {
+		return opts, nil, err
+	}
+	if opts.Length <= 0 && opts.MinEntropyBits > 0 {
+		opts.Length = 1 // Raised to the entropy below
+	}
+	if opts.Length <= 0 && opts.Strict {
+		return opts, nil, fmt.Errorf("%w %d", ErrInvalidLength, opts.Length)
+	}
+	if opts.Length <= 0 {
+		deprecated(DeprecatedDefaultLength)
+		logf(opts, "uriuniq: invalid length %d, using default length %d", opts.Length, DefaultLength)
+		opts.Length = DefaultLength
+	}
+	if opts.MinEntropyBits < 0 {
+		return opts, nil, errorf(CodeInvalidLength, "uriuniq: cannot reach %d bits of entropy", opts.MinEntropyBits)
+	}
+	if need := int(math.Ceil(float64(opts.MinEntropyBits)/math.Log2(float64(len(alphabet))) - 1e-9)); opts.Length < need {
+		opts.Length = need
+	}
+	if err := checkEntropyFloor(opts, float64(opts.Length)*math.Log2(float64(len(alphabet)))); err != nil {
+		return opts, nil, err
+	}
+	if opts.MaxBadReads <= 0 {
+		opts.MaxBadReads = DefaultMaxBadReads
+	}
+	return opts, alphabet, nil
+}
+
+// generateRunes creates the UTF-8 bytes of a string of runes for
+// Generate, with opts as prepareRunes accepts them.
+func generateRunes(opts Options) ([]byte, error) {
+	opts, alphabet, err := prepareRunes(opts)
+	if err != nil {
+		return nil, err
+	}
+	output, err := appendRunes(nil, opts, alphabet)
+	if err != nil {
+		return nil, err
+	}
+	totalIssued.Add(1)
+	countGenerated(opts)
+	return output, nil
+}
+
+// appendRunes appends opts.Length runes drawn from alphabet to dst as
+// UTF-8.
+func appendRunes(dst []byte, opts Options, alphabet []rune) ([]byte, error) {
+	runes, err := randRunes(opts, alphabet)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range runes {
+		dst = utf8.AppendRune(dst, r)
+		if opts.Sensitive {
+			runes[i] = 0
+		}
+	}
+	return dst, nil
+}
+
+// validateRunes is Validate for a CustomCharset of multi-byte runes.
+func validateRunes(s string, opts Options) error {
+	opts, _, err := prepareRunes(opts)
+	if err != nil {
+		return err
+	}
+	if err := checkInputLength(s, inputLimit(opts)); err != nil {
+		return err
+	}
+	prefix := idPrefix(opts)
+	if !strings.HasPrefix(s, prefix) {
+		return fmt.Errorf("%w %q", ErrMissingPrefix, prefix)
+	}
+	id := s[len(prefix):]
+	if n := utf8.RuneCountInString(id); n != opts.Length {
+		return errorf(CodeInvalidLength, "uriuniq: length %d, want %d", n, opts.Length)
+	}
+	if err := checkCharset(id, opts.CustomCharset); err != nil {
+		err.(*ParseError).Index += len(prefix)
+		return err
+	}
+	return nil
+}
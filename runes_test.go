@@ -0,0 +1,112 @@
+package uriuniq
+
+import (
+	"net/url"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TestGenerateRunes checks class membership and reported lengths.
+func TestGenerateRunes(t *testing.T) {
+	class := RuneClass{
+		Tables: []*unicode.RangeTable{unicode.Letter},
+		Filter: func(r rune) bool { return r <= unicode.MaxLatin1 },
+	}
+	opts := NewOpts()
+	opts.Length = 40
+	result, err := GenerateRunes(class, opts)
+	if err != nil {
+		t.Fatalf("GenerateRunes failed: %s", err)
+	}
+	if result.Runes != 40 || utf8.RuneCountInString(result.Value) != 40 || result.Bytes != len(result.Value) {
+		t.Errorf("Unexpected lengths %+v", result)
+	}
+	for _, r := range result.Value {
+		if !unicode.IsLetter(r) || r > unicode.MaxLatin1 {
+			t.Errorf("Rune %q not in class", r)
+		}
+	}
+	if encoded := url.PathEscape(result.Value); len(encoded) != result.EncodedLength {
+		t.Errorf("Expected encoded length %d, got %d", len(encoded), result.EncodedLength)
+	}
+
+	if _, err := GenerateRunes(RuneClass{}, opts); err == nil {
+		t.Errorf("Expected error for empty class")
+	}
+}
+
+// TestRuneClass checks enumeration of a class with strides.
+func TestRuneClass(t *testing.T) {
+	table := &unicode.RangeTable{R16: []unicode.Range16{{Lo: 'a', Hi: 'e', Stride: 2}}}
+	got := string(RuneClass{Tables: []*unicode.RangeTable{table, table}}.runes())
+	if got != "ace" {
+		t.Errorf("Expected ace, got %s", got)
+	}
+}
+
+// TestRuneCharset checks that a CustomCharset of multi-byte runes yields
+// whole runes through Generate, Generators and Validate.
+func TestRuneCharset(t *testing.T) {
+	const cyrillic Charset = "абвгдежзийклмнопрстуфхцчшщъыьэюя"
+	opts := Options{Length: 10, CustomCharset: cyrillic, Prefix: "ru"}
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	tests := []struct {
+		name string
+		next func() (string, error)
+	}{
+		{"Generate", func() (string, error) { return Generate(opts) }},
+		{"Generator", g.Next},
+		{"AppendTo", func() (string, error) {
+			b, err := g.AppendTo(nil)
+			return string(b), err
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := tc.next()
+			if err != nil {
+				t.Fatalf("Generating failed: %s", err)
+			}
+			rest := id[len("ru_"):]
+			if !utf8.ValidString(id) || utf8.RuneCountInString(rest) != 10 || checkCharset(rest, cyrillic) != nil {
+				t.Errorf("Unexpected ID %q", id)
+			}
+			if err := Validate(id, opts); err != nil {
+				t.Errorf("Validate(%q) failed: %s", id, err)
+			}
+		})
+	}
+
+	if bits := Entropy(opts); bits != 50 {
+		t.Errorf("Expected 50 bits, got %g", bits)
+	}
+	escaped := Options{Length: 4, CustomCharset: "äöü", Escape: EscapePercent}
+	if id, err := Generate(escaped); err != nil || len(id) != 4*6 {
+		t.Errorf("Unexpected escaped ID %q, %v", id, err)
+	}
+
+	invalid := []struct {
+		name string
+		id   string
+		opts Options
+		code Code
+	}{
+		{"Short", "ru_абв", opts, CodeInvalidLength},
+		{"Foreign Rune", "ru_абвгдежзиé", opts, CodeInvalidChar},
+		{"Check Char", "", Options{Length: 4, CustomCharset: cyrillic, CheckChar: true}, CodeInvalidArgument},
+		{"Duplicate", "", Options{Length: 4, CustomCharset: "ааб"}, CodeDuplicateChar},
+		{"Strict", "", Options{Length: 4, CustomCharset: cyrillic, Strict: true}, CodeUnsafeCharset},
+	}
+	for _, tc := range invalid {
+		if err := Validate(tc.id, tc.opts); CodeOf(err) != tc.code {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.code, err)
+		}
+	}
+	if _, err := GenerateSortable(opts); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument for a sortable ID, got %v", err)
+	}
+}
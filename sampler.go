@@ -0,0 +1,168 @@
+package uriuniq
+
+import (
+	"math/big"
+)
+
+// Sampler selects how entropy is turned into chars, see Options.Sampler.
+type Sampler int
+
+const (
+	// SamplerRejection maps each random byte to a char, rejecting the bytes
+	// that would bias the result. It is the fastest sampler.
+	SamplerRejection Sampler = iota
+
+	// SamplerArithmetic draws the whole string as one random integer below
+	// len(charset)^Length and writes it out in base len(charset), using
+	// about Length*log2(len(charset)) bits instead of 8 bits per char. No
+	// entropy is rejected: when the bytes read give an integer out of
+	// range, its excess over the range is itself uniform and is kept as
+	// the start of the next integer, extended with fresh bytes, so a draw
+	// only ever reads more. Apart from fewer than 8 bits left over by the
+	// last byte, every bit read ends up in the string, and the expected
+	// entropy read is within about a byte of the minimum. An exactly
+	// uniform draw from a range that is not a power of 2 cannot read a
+	// fixed amount of entropy, so the number of reads varies: each one
+	// beyond the first, of one or a few bytes, is needed with probability
+	// below 1/2, at most MaxBadReads times. Prefer it where entropy is
+	// scarce, such as VM cold boot or embedded devices.
+	SamplerArithmetic
+
+	// SamplerBits takes log2(len(charset)) bits of entropy per char for
+	// charsets of a power-of-two size, such as Hex, Base32Crockford and
+	// Base64URL, so no entropy is discarded: 6 bits per char for 64 chars
+	// instead of a byte. Unlike SamplerRejection and SamplerArithmetic it
+	// reads a fixed ceil(Length*bits/8) bytes per
+	// string. The entropy is read as one big-endian bit string. Other
+	// charsets are sampled as with SamplerRejection.
+	SamplerBits
+)
+
+// bitsFill fills dst using SamplerBits, for a charset of 1<<bits chars
+// with bits from 1 to 8, reading entropy into buffer.
+func bitsFill(opts Options, charset, dst, buffer []byte, bits uint) error {
+	src := newEntropyReader(opts)
+	mask := uint64(1)<<bits - 1
+	need := (len(dst)*int(bits) + 7) / 8
+	var acc uint64 // Bits read but not used yet, in the low held bits
+	var held uint
+	filled, reads := 0, 0
+	for need > 0 {
+		chunk := buffer
+		if len(chunk) > need {
+			chunk = chunk[:need]
+		}
+		var err error
+		profiled(opts.ProfileLabels, "read", func() {
+			err = src.ReadFull(chunk)
+		})
+		if err != nil {
+			return err
+		}
+		need -= len(chunk)
+		reads++
+		for _, b := range chunk {
+			acc = acc<<8 | uint64(b)
+			for held += 8; held >= bits && filled < len(dst); held -= bits {
+				dst[filled] = charset[acc>>(held-bits)&mask]
+				filled++
+			}
+		}
+	}
+	if opts.Metrics != nil {
+		opts.Metrics.Sampled((len(dst)*int(bits)+7)/8, 0, reads-1)
+	}
+	return nil
+}
+
+// wipeInt zeroes the words backing x, also those beyond its current
+// length that held larger values before.
+func wipeInt(x *big.Int) {
+	words := x.Bits()
+	words = words[:cap(words)]
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
+
+// charsetBits returns log2(n) if n is a power of 2 from 2 to 256, or 0.
+func charsetBits(n int) uint {
+	for bits := uint(1); bits <= 8; bits++ {
+		if n == 1<<bits {
+			return bits
+		}
+	}
+	return 0
+}
+
+// arithmeticFill fills dst using SamplerArithmetic. It keeps x uniform
+// below m and extends both with fresh bytes until m reaches the limit
+// len(charset)^len(dst). The largest multiple of the limit below m then
+// splits x: below it, x modulo the limit is the draw; above it, x minus
+// the multiple is uniform below the remainder and carried on, so no
+// entropy read is thrown away.
+func arithmeticFill(opts Options, charset, dst []byte) error {
+	src := newEntropyReader(opts)
+	if len(charset) < 2 || len(charset) > 256 {
+		return newError(CodeCharsetSize, "uriuniq: charset size 2-256")
+	}
+
+	base := big.NewInt(int64(len(charset)))
+	limit := new(big.Int).Exp(base, big.NewInt(int64(len(dst))), nil)
+	bits := new(big.Int).Sub(limit, big.NewInt(1)).BitLen()
+	buf := make([]byte, (bits+7)/8)
+	if opts.Sensitive {
+		defer wipe(buf)
+	}
+
+	x := new(big.Int)
+	m := big.NewInt(1)
+	split := new(big.Int)
+	digit := new(big.Int)
+	if opts.Sensitive {
+		defer wipeInt(x)
+		defer wipeInt(split)
+		defer wipeInt(digit)
+	}
+	consumed := 0
+	for reads := 0; ; reads++ {
+		if reads >= opts.MaxBadReads {
+			return errBadReads(opts.MaxBadReads)
+		}
+		for m.Cmp(limit) < 0 {
+			// Read the bytes m lacks to reach the bits of the limit, at
+			// least one.
+			n := (bits - digit.Sub(m, big.NewInt(1)).BitLen() + 7) / 8
+			if n < 1 {
+				n = 1
+			}
+			var err error
+			profiled(opts.ProfileLabels, "read", func() {
+				err = src.ReadFull(buf[:n])
+			})
+			if err != nil {
+				return err
+			}
+			consumed += n
+			x.Lsh(x, uint(8*n)).Or(x, digit.SetBytes(buf[:n]))
+			m.Lsh(m, uint(8*n))
+		}
+		split.Div(m, limit).Mul(split, limit)
+		if x.Cmp(split) < 0 {
+			x.Mod(x, limit)
+			if opts.Metrics != nil {
+				opts.Metrics.Sampled(consumed, 0, reads)
+			}
+			break
+		}
+		x.Sub(x, split)
+		m.Sub(m, split)
+	}
+
+	for i := len(dst) - 1; i >= 0; i-- {
+		x.DivMod(x, base, digit)
+		dst[i] = charset[digit.Int64()]
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package uriuniq
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+// TestArithmeticFill checks decoding and entropy use of the arithmetic sampler.
+func TestArithmeticFill(t *testing.T) {
+	// 10^3 needs 10 bits, read as 2 bytes.
+	opts := Options{Length: 3, MaxBadReads: DefaultMaxBadReads, Sampler: SamplerArithmetic}
+	src := &countingReader{r: bytes.NewReader([]byte{0xff, 0xff, 0x01, 0x7b})}
+	opts.EntropySource = src
+	metrics := &Counters{}
+	opts.Metrics = metrics
+	dst := make([]byte, 3)
+	if _, err := randFill(opts, []byte(Numeric), dst, nil); err != nil {
+		t.Fatalf("randFill failed: %s", err)
+	}
+	// 0xffff = 65535 is past 65000, the last multiple of 1000 below 2^16,
+	// so its excess 535 is kept and extended by 0x01 to 136961, below
+	// 137000. Nothing is rejected.
+	if string(dst) != "961" {
+		t.Errorf("Expected 961, got %s", dst)
+	}
+	if s := metrics.Snapshot(); s.EntropyBytes != 3 || s.RejectedBytes != 0 || s.Retries != 1 {
+		t.Errorf("Expected 3 bytes consumed, none rejected and 1 retry, got %+v", s)
+	}
+
+	// A source of 0xff bytes always lands past the last multiple.
+	opts = Options{Length: 3, MaxBadReads: 3, Sampler: SamplerArithmetic, EntropySource: bytes.NewReader(bytes.Repeat([]byte{0xff}, 64))}
+	if _, err := randFill(opts, []byte(Numeric), dst, nil); CodeOf(err) != CodeTooManyBadReads {
+		t.Errorf("Expected %s, got %v", CodeTooManyBadReads, err)
+	}
+
+	opts = NewOpts()
+	opts.Sampler = SamplerArithmetic
+	opts.Length = 22
+	src = &countingReader{r: CryptoRand.Reader}
+	opts.EntropySource = src
+	for i := 0; i < 20; i++ {
+		result, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate failed: %s", err)
+		}
+		if len(result) != 22 || checkCharset(result, Alphanumeric) != nil {
+			t.Errorf("Unexpected result %q", result)
+		}
+	}
+	// 22 chars of base62 need 131 bits, i.e. 17 bytes per draw.
+	if src.reads < 20 || src.reads > 40 {
+		t.Errorf("Unexpected number of draws %d", src.reads)
+	}
+}
+
+// TestBitsFill checks that SamplerBits packs chars into the bits of
+// entropy and falls back to rejection sampling for other sizes.
+func TestBitsFill(t *testing.T) {
+	// 0b000001 000010 000011 111111 across the 3 bytes 0x04 0x20 0xff.
+	tests := []struct {
+		name    string
+		charset Charset
+		src     []byte
+		want    string
+	}{
+		{"Base64URL", Base64URL, []byte{0x04, 0x20, 0xff}, "BCD_"},
+		{"Hex", Hex, []byte{0x4a, 0x0f}, "4a0f"},
+		{"Binary", "01", []byte{0xa5}, "10100101"},
+		{"Base58", Base58, []byte{0, 1, 2, 3}, "1234"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := bytes.NewReader(tc.src)
+			opts := Options{Length: len(tc.want), CustomCharset: tc.charset, Sampler: SamplerBits, EntropySource: src}
+			got, err := Generate(opts)
+			if err != nil {
+				t.Fatalf("Generate failed: %s", err)
+			}
+			if got != tc.want || src.Len() != 0 {
+				t.Errorf("Expected %s, got %s with %d bytes left", tc.want, got, src.Len())
+			}
+		})
+	}
+
+	// 64 Base64URL chars take 48 bytes instead of 64, also in a Generator.
+	for _, size := range []int{-1, 0} {
+		c := &Counters{}
+		opts := Options{Length: 64, CustomCharset: Base64URL, Sampler: SamplerBits, Metrics: c, EntropyBufferSize: size}
+		g, err := NewGenerator(opts)
+		if err != nil {
+			t.Fatalf("NewGenerator failed: %s", err)
+		}
+		if id, err := g.Next(); err != nil || len(id) != 64 || checkCharset(id, Base64URL) != nil {
+			t.Errorf("Unexpected ID %q, %v", id, err)
+		}
+		if s := c.Snapshot(); s.EntropyBytes != 48 || s.RejectedBytes != 0 {
+			t.Errorf("Unexpected counters %+v", s)
+		}
+	}
+}
+
+// BenchmarkGenerateArithmetic benchmarks the arithmetic sampler.
+func BenchmarkGenerateArithmetic(b *testing.B) {
+	opts := NewOpts()
+	opts.Sampler = SamplerArithmetic
+	for i := 0; i < b.N; i++ {
+		if _, err := Generate(opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGenerateBits benchmarks the bit-packing sampler for Base64URL.
+func BenchmarkGenerateBits(b *testing.B) {
+	opts := NewOpts()
+	opts.CustomCharset = Base64URL
+	opts.Sampler = SamplerBits
+	for i := 0; i < b.N; i++ {
+		if _, err := Generate(opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestWipeInt checks that wipeInt zeroes the words a big.Int held.
+func TestWipeInt(t *testing.T) {
+	x := new(big.Int).Lsh(big.NewInt(0xabc), 200)
+	words := x.Bits()
+	x.Rsh(x, 190) // Shorter now, the high words stay in the backing array
+	wipeInt(x)
+	for i, w := range words[:cap(words)] {
+		if w != 0 {
+			t.Fatalf("Word %d not zeroed", i)
+		}
+	}
+	if x.Sign() != 0 {
+		t.Errorf("Expected 0, got %s", x)
+	}
+}
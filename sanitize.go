@@ -0,0 +1,49 @@
+package uriuniq
+
+import (
+	"strings"
+	"unicode"
+)
+
+// pasteWrappers are chars that pasted IDs often pick up around them.
+const pasteWrappers = "\"'`\u201c\u201d\u2018\u2019\u00ab\u00bb\u2039\u203a()[]{}<>.,;:!?"
+
+// Sanitize cleans up an ID pasted from chat or email and validates it
+// against opts. It removes invisible format chars, such as zero-width
+// spaces and byte order marks, anywhere in input, then strips spaces,
+// quotes, smart quotes, brackets and punctuation around it, unless those
+// chars are part of the charset. Chars left outside the charset are
+// reported as a *ParseError indexed into the cleaned string. Inputs far
+// longer than any ID of opts are rejected with ErrInputTooLong before
+// they are cleaned.
+func Sanitize(input string, opts Options) (string, error) {
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return "", err
+	}
+	if err := checkInputLength(input, inputLimit(opts)); err != nil {
+		return "", err
+	}
+	s := strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, input)
+	s = strings.TrimFunc(s, func(r rune) bool {
+		if r < 256 && strings.IndexByte(string(charset), byte(r)) >= 0 {
+			return false
+		}
+		return unicode.IsSpace(r) || strings.ContainsRune(pasteWrappers, r)
+	})
+	if s == "" {
+		return "", ErrEmptyInput
+	}
+	if min, max := lengthRange(opts); len(s) < min || len(s) > max {
+		return "", errorf(CodeInvalidLength, "uriuniq: length %d, want %d-%d", len(s), min, max)
+	}
+	if err := checkCharset(s, Charset(charset)); err != nil {
+		return "", err
+	}
+	return s, nil
+}
@@ -0,0 +1,43 @@
+package uriuniq
+
+import "testing"
+
+// TestSanitize checks cleaning of typical pasted IDs.
+func TestSanitize(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 8
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Clean", "ab12CD34", "ab12CD34"},
+		{"Zero-Width Space", "ab12\u200bCD34", "ab12CD34"},
+		{"Byte Order Mark", "\ufeffab12CD34", "ab12CD34"},
+		{"Smart Quotes", "\u201cab12CD34\u201d", "ab12CD34"},
+		{"Trailing Punctuation", "ab12CD34.", "ab12CD34"},
+		{"Brackets And Spaces", " (ab12CD34), ", "ab12CD34"},
+		{"No-Break Space", "\u00a0ab12CD34\u00a0", "ab12CD34"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Sanitize(tc.input, opts)
+			if err != nil || got != tc.expected {
+				t.Errorf("Sanitize(%q) = %q, %v, expected %q", tc.input, got, err, tc.expected)
+			}
+		})
+	}
+
+	// Chars of the charset are kept even around the ID.
+	opts.CustomCharset = "abc."
+	opts.Length = 4
+	if got, err := Sanitize("\"ab.c\"", opts); err != nil || got != "ab.c" {
+		t.Errorf("Expected charset dot to be kept, got %q, %v", got, err)
+	}
+	opts.CustomCharset = ""
+	for _, input := range []string{"ab 12CD3", "ab12CD3", "..."} {
+		if _, err := Sanitize(input, opts); err == nil {
+			t.Errorf("Sanitize(%q) should fail", input)
+		}
+	}
+}
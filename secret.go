@@ -0,0 +1,133 @@
+package uriuniq
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// redacted is printed in place of a Secret's value.
+const redacted = "uriuniq:redacted"
+
+// Secret holds a generated token that must not leak into logs. It prints
+// as "uriuniq:redacted" with every fmt verb, whether printed by pointer,
+// by value or as a field of a struct, so it never reveals the value. Use
+// Expose to read it and Close to zero the underlying bytes once it is no
+// longer needed.
+type Secret struct {
+	value []byte
+}
+
+// GenerateSecret creates a random Secret using Options, formatted as
+// GenerateToken formats its value, with the Prefix and groups of opts.
+// Options.Sensitive is always set, so no intermediate buffers are left behind.
+func GenerateSecret(opts Options) (*Secret, error) {
+	opts.Sensitive = true
+	output, err := generate(opts)
+	if err != nil {
+		return nil, err
+	}
+	value := outputBytes(opts, output)
+	wipe(output)
+	return &Secret{value: value}, nil
+}
+
+// Expose returns the secret value, or "" after Close.
+func (s *Secret) Expose() string {
+	return string(s.value)
+}
+
+// Close zeroes the secret value. It always returns nil.
+func (s *Secret) Close() error {
+	wipe(s.value)
+	s.value = nil
+	return nil
+}
+
+// String returns a redacted placeholder.
+func (s Secret) String() string {
+	return redacted
+}
+
+// GoString returns a redacted placeholder.
+func (s Secret) GoString() string {
+	return redacted
+}
+
+// Format writes a redacted placeholder for every verb.
+func (s Secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, redacted)
+}
+
+// tokenHintChars is the number of random chars a Token shows after its
+// prefix, when it has enough left to stay secret.
+const tokenHintChars = 2
+
+// Token is a session or API secret that must not leak into logs or be
+// compared in variable time. It prints as its prefix and first chars
+// followed by an ellipsis and "[redacted]", such as tok_ab…[redacted],
+// with every fmt verb and as JSON, enough to tell tokens apart in logs.
+// Use Reveal to read it and Equal to compare it.
+type Token struct {
+	value  string
+	prefix int // Length of the Prefix and its separator
+}
+
+// GenerateToken creates a random Token using Options. Options.Sensitive is
+// always set, so the token is the only copy of its value left behind.
+func GenerateToken(opts Options) (Token, error) {
+	opts.Sensitive = true
+	id, err := generateID(opts)
+	if err != nil {
+		return Token{}, err
+	}
+	return NewToken(id, opts), nil
+}
+
+// NewToken wraps s, such as a token presented by a client, in a Token. opts
+// give the Prefix shown when printing it; s need not be valid for them.
+func NewToken(s string, opts Options) Token {
+	t := Token{value: s}
+	if p := idPrefix(opts); p != "" && strings.HasPrefix(s, p) {
+		t.prefix = len(p)
+	}
+	return t
+}
+
+// Reveal returns the token value.
+func (t Token) Reveal() string {
+	return t.value
+}
+
+// Equal reports whether t and other have the same value, in time that
+// depends only on their lengths.
+func (t Token) Equal(other Token) bool {
+	return subtle.ConstantTimeCompare([]byte(t.value), []byte(other.value)) == 1
+}
+
+// String returns the redacted form of t.
+func (t Token) String() string {
+	hint := t.value[:t.prefix]
+	if len(t.value)-t.prefix >= 8*tokenHintChars {
+		hint = t.value[:t.prefix+tokenHintChars]
+	}
+	return hint + "…[redacted]"
+}
+
+// GoString returns the redacted form of t.
+func (t Token) GoString() string {
+	return t.String()
+}
+
+// Format writes the redacted form of t for every verb.
+func (t Token) Format(f fmt.State, verb rune) {
+	io.WriteString(f, t.String())
+}
+
+// MarshalJSON encodes the redacted form of t, so that structured logs do
+// not reveal it. Send Reveal instead where the value is meant to leave.
+func (t Token) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
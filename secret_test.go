@@ -0,0 +1,103 @@
+package uriuniq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestSecretRedacted ensures fmt verbs never print the secret value.
+func TestSecretRedacted(t *testing.T) {
+	secret, err := GenerateSecret(NewOpts())
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %s", err)
+	}
+	value := secret.Expose()
+	if len(value) != DefaultLength {
+		t.Fatalf("Expected length %d, got %d", DefaultLength, len(value))
+	}
+
+	holder := struct{ Secret Secret }{*secret}
+	hexValue := fmt.Sprintf("%x", value)
+	for _, verb := range []string{"%v", "%s", "%+v", "%#v", "%q", "%x", "%X", "%d"} {
+		for _, arg := range []any{secret, *secret, holder, &holder} {
+			out := fmt.Sprintf(verb, arg)
+			if strings.Contains(out, value) || strings.Contains(strings.ToLower(out), hexValue) || !strings.Contains(out, redacted) {
+				t.Errorf("%s of %T: got %q", verb, arg, out)
+			}
+		}
+	}
+	if out := fmt.Sprintln(*secret); strings.Contains(out, value) {
+		t.Errorf("Println of value: got %q", out)
+	}
+}
+
+// TestSecretFormat checks that a Secret carries the prefix and groups of
+// its Options and validates against them.
+func TestSecretFormat(t *testing.T) {
+	opts := Options{Length: 12, Prefix: "sk", GroupSize: 4}
+	secret, err := GenerateSecret(opts)
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %s", err)
+	}
+	value := secret.Expose()
+	if !strings.HasPrefix(value, "sk_") || strings.Count(value, "-") != 2 || len(value) != 17 {
+		t.Errorf("Unexpected secret %q", value)
+	}
+	if err := Validate(value, opts); err != nil {
+		t.Errorf("Validate failed for %q: %s", value, err)
+	}
+}
+
+// TestSecretClose checks that Close zeroes the value.
+func TestSecretClose(t *testing.T) {
+	secret, err := GenerateSecret(NewOpts())
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %s", err)
+	}
+	backing := secret.value
+	if err := secret.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if secret.Expose() != "" {
+		t.Errorf("Expected empty value after Close")
+	}
+	for i, b := range backing {
+		if b != 0 {
+			t.Fatalf("Byte %d not zeroed", i)
+		}
+	}
+}
+
+// TestToken checks that a Token prints redacted and compares by value.
+func TestToken(t *testing.T) {
+	opts := Options{Length: 24, Prefix: "tok"}
+	tok, err := GenerateToken(opts)
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %s", err)
+	}
+	value := tok.Reveal()
+	if err := Validate(value, opts); err != nil {
+		t.Fatalf("Invalid token %q: %s", value, err)
+	}
+	want := value[:6] + "…[redacted]"
+	for _, verb := range []string{"%v", "%s", "%+v", "%#v", "%q", "%x", "%d"} {
+		if out := fmt.Sprintf(verb, tok); out != want {
+			t.Errorf("%s: got %q, expected %q", verb, out, want)
+		}
+	}
+	if out, err := json.Marshal(struct{ T Token }{tok}); err != nil || strings.Contains(string(out), value) {
+		t.Errorf("json.Marshal = %s, %v", out, err)
+	}
+	if short := NewToken("abc", NewOpts()); short.String() != "…[redacted]" {
+		t.Errorf("Unexpected short token %q", short.String())
+	}
+
+	if !tok.Equal(NewToken(value, Options{})) {
+		t.Errorf("Expected equal tokens")
+	}
+	if other, _ := GenerateToken(opts); tok.Equal(other) || tok.Equal(NewToken(value[:10], opts)) {
+		t.Errorf("Expected different tokens")
+	}
+}
@@ -0,0 +1,56 @@
+package uriuniq
+
+// glyphWidths are the advance widths, in thousandths of an em, of ASCII
+// chars in a Helvetica-like proportional font. Chars not listed are taken
+// to be as wide as a digit.
+var glyphWidths = map[byte]int{
+	'A': 667, 'B': 667, 'C': 722, 'D': 722, 'E': 667, 'F': 611, 'G': 778, 'H': 722, 'I': 278,
+	'J': 500, 'K': 667, 'L': 556, 'M': 833, 'N': 722, 'O': 778, 'P': 667, 'Q': 778, 'R': 722,
+	'S': 667, 'T': 611, 'U': 722, 'V': 667, 'W': 944, 'X': 667, 'Y': 667, 'Z': 611,
+	'a': 556, 'b': 556, 'c': 500, 'd': 556, 'e': 556, 'f': 278, 'g': 556, 'h': 556, 'i': 222,
+	'j': 222, 'k': 500, 'l': 222, 'm': 833, 'n': 556, 'o': 556, 'p': 556, 'q': 556, 'r': 333,
+	's': 500, 't': 278, 'u': 556, 'v': 500, 'w': 722, 'x': 500, 'y': 500, 'z': 500,
+	'-': 333, '.': 278, '_': 556, '~': 584, '!': 278, '*': 389, '\'': 191, '(': 333, ')': 333,
+	'@': 1015, '%': 889,
+}
+
+// defaultGlyphWidth is the width of a digit, used for unlisted chars.
+const defaultGlyphWidth = 556
+
+// WorstCaseShape returns a placeholder ID with the worst-case shape of
+// opts, for testing UI layouts before real IDs exist: it has the longest
+// length opts allows and cycles through the visually widest chars of the
+// charset, those at least 85% as wide as the widest one. Escape is applied
+// as by Generate. The result is not random and must not be used as an ID.
+func WorstCaseShape(opts Options) (string, error) {
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return "", err
+	}
+	widest := 0
+	for _, c := range charset {
+		if w := glyphWidth(c); w > widest {
+			widest = w
+		}
+	}
+	var wide []byte
+	for _, c := range charset {
+		if glyphWidth(c)*100 >= widest*85 {
+			wide = append(wide, c)
+		}
+	}
+	_, max := lengthRange(opts)
+	output := make([]byte, max+checkChars(opts))
+	for i := range output {
+		output[i] = wide[i%len(wide)]
+	}
+	return escapeOutput(opts, output), nil
+}
+
+// glyphWidth returns the width of c in thousandths of an em.
+func glyphWidth(c byte) int {
+	if w, ok := glyphWidths[c]; ok {
+		return w
+	}
+	return defaultGlyphWidth
+}
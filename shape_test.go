@@ -0,0 +1,28 @@
+package uriuniq
+
+import "testing"
+
+// TestWorstCaseShape checks the length and chars of placeholder IDs.
+func TestWorstCaseShape(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"Default", Options{Length: 6}, "mMWmMW"},
+		{"Lowercase", Options{Length: 4, ExcludeUppercase: true, ExcludeNumeric: true}, "mwmw"},
+		{"Length Range", Options{MinLength: 2, MaxLength: 5, CustomCharset: Numeric}, "01234"},
+		{"Escaped", Options{Length: 2, CustomCharset: "ab%", Escape: EscapePercent}, "%25%25"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := WorstCaseShape(tc.opts)
+			if err != nil || got != tc.want {
+				t.Errorf("Expected %q, got %q, %v", tc.want, got, err)
+			}
+		})
+	}
+	if _, err := WorstCaseShape(Options{Length: 4, MinLength: 3, MaxLength: 1}); err == nil {
+		t.Errorf("Expected error for invalid Options")
+	}
+}
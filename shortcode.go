@@ -0,0 +1,123 @@
+package uriuniq
+
+import (
+	"math"
+	"strings"
+)
+
+// ShortCodeCharset is the alphabet of short codes: digits and uppercase
+// letters without I, L, O and U, which are easily misread or misheard.
+const ShortCodeCharset Charset = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ErrBadCheckChar is returned for a short code whose check char is wrong,
+// usually a typo.
+var ErrBadCheckChar = newError(CodeBadCheckChar, "uriuniq: bad check char")
+
+// ShortCodes issues short redemption codes, such as gift card or invite
+// codes typed in by hand. Each code is Length random chars of
+// ShortCodeCharset followed by a Luhn mod 32 check char, which catches
+// every single-char typo and most swaps of neighbouring chars before a
+// lookup is made. Use GuessProbability to size Length against the number of
+// attempts an attacker gets past Throttle.
+type ShortCodes struct {
+	Length int // Random chars, defaults to 8
+
+	// Throttle, if set, is called with the subject, such as a client IP or
+	// account, before every Issue and Check. A non-nil error is returned
+	// as is and stops the call.
+	Throttle func(subject string) error
+}
+
+// Options returns the Options of the random part of the codes.
+func (s *ShortCodes) Options() Options {
+	opts := NewOpts()
+	if s.Length > 0 {
+		opts.Length = s.Length
+	} else {
+		opts.Length = 8
+	}
+	opts.CustomCharset = ShortCodeCharset
+	return opts
+}
+
+// Issue creates a code for subject.
+func (s *ShortCodes) Issue(subject string) (string, error) {
+	if s.Throttle != nil {
+		if err := s.Throttle(subject); err != nil {
+			return "", err
+		}
+	}
+	code, err := generateID(s.Options())
+	if err != nil {
+		return "", err
+	}
+	return code + string(luhn32(code)), nil
+}
+
+// Check normalizes a code entered by subject and verifies its length,
+// chars and check char. It returns the code in canonical form, ready to be
+// looked up; whether the code was issued is up to the caller.
+func (s *ShortCodes) Check(subject, code string) (string, error) {
+	if s.Throttle != nil {
+		if err := s.Throttle(subject); err != nil {
+			return "", err
+		}
+	}
+	code = strings.ToUpper(stripSeparators(code, "- "))
+	if want := s.Options().Length + 1; len(code) != want {
+		return "", newError(CodeInvalidLength, "uriuniq: wrong short code length")
+	}
+	if err := checkCharset(code, ShortCodeCharset); err != nil {
+		return "", err
+	}
+	if luhn32(code[:len(code)-1]) != code[len(code)-1] {
+		return "", ErrBadCheckChar
+	}
+	return code, nil
+}
+
+// luhn32 returns the Luhn mod 32 check char of code over ShortCodeCharset.
+func luhn32(code string) byte {
+	return luhnMod(code, ShortCodeCharset)
+}
+
+// checkLuhnCharset rejects charsets luhnMod cannot protect: with an odd
+// size N, doubling is not a permutation mod N, so some single-char errors
+// keep the check char.
+func checkLuhnCharset(charset []byte) error {
+	if len(charset)%2 != 0 {
+		return errorf(CodeCharsetSize, "uriuniq: check char needs a charset of even size, got %d", len(charset))
+	}
+	return nil
+}
+
+// luhnMod returns the Luhn mod N check char of code over charset, where N
+// is the charset size. For even N, which checkLuhnCharset ensures, it
+// catches every single-char error and most swaps of neighbouring chars.
+func luhnMod(code string, charset Charset) byte {
+	n := len(charset)
+	sum := 0
+	double := true
+	for i := len(code) - 1; i >= 0; i-- {
+		v := IndexOf(charset, code[i])
+		if double {
+			v *= 2
+			v = v/n + v%n
+		}
+		sum += v
+		double = !double
+	}
+	return charset[(n-sum%n)%n]
+}
+
+// GuessProbability returns the chance that an attacker guesses a given
+// string generated with opts within attempts tries. Check chars add no
+// entropy and are not counted. With many valid strings outstanding,
+// multiply by their number for an upper bound.
+func GuessProbability(opts Options, attempts int) float64 {
+	if attempts <= 0 {
+		return 0
+	}
+	p := math.Exp2(-Entropy(opts))
+	return -math.Expm1(float64(attempts) * math.Log1p(-p))
+}
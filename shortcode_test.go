@@ -0,0 +1,72 @@
+package uriuniq
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestShortCodes checks issuing, typo detection and throttling.
+func TestShortCodes(t *testing.T) {
+	var calls []string
+	s := &ShortCodes{Length: 6, Throttle: func(subject string) error {
+		calls = append(calls, subject)
+		if subject == "banned" {
+			return errors.New("throttled")
+		}
+		return nil
+	}}
+
+	code, err := s.Issue("10.0.0.1")
+	if err != nil {
+		t.Fatalf("Issue failed: %s", err)
+	}
+	if len(code) != 7 {
+		t.Errorf("Expected 7 chars, got %q", code)
+	}
+	if got, err := s.Check("10.0.0.1", strings.ToLower(code[:3])+"-"+code[3:]); err != nil || got != code {
+		t.Errorf("Check = %q, %v, expected %q", got, err, code)
+	}
+
+	// Every single-char typo is caught.
+	for i := 0; i < len(code); i++ {
+		for _, c := range []byte(ShortCodeCharset) {
+			if c == code[i] {
+				continue
+			}
+			typo := code[:i] + string(c) + code[i+1:]
+			if _, err := s.Check("10.0.0.1", typo); !errors.Is(err, ErrBadCheckChar) {
+				t.Fatalf("Typo %q not caught: %v", typo, err)
+			}
+		}
+	}
+
+	if _, err := s.Check("10.0.0.1", code+"0"); err == nil {
+		t.Errorf("Expected error for wrong length")
+	}
+	if _, err := s.Issue("banned"); err == nil || err.Error() != "throttled" {
+		t.Errorf("Expected throttle error, got %v", err)
+	}
+	if calls[0] != "10.0.0.1" {
+		t.Errorf("Unexpected throttle calls %v", calls)
+	}
+}
+
+// TestGuessProbability checks the figures for small and large keyspaces.
+func TestGuessProbability(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 2
+	opts.CustomCharset = "0123456789"
+	if got := GuessProbability(opts, 100); math.Abs(got-(1-math.Pow(0.99, 100))) > 1e-9 {
+		t.Errorf("Unexpected probability %f", got)
+	}
+	if got := GuessProbability(opts, 0); got != 0 {
+		t.Errorf("Expected 0 for no attempts, got %f", got)
+	}
+
+	s := &ShortCodes{}
+	if got := GuessProbability(s.Options(), 1000); got > 1e-8 || got <= 0 {
+		t.Errorf("Unexpected probability %g for 8 chars", got)
+	}
+}
@@ -0,0 +1,105 @@
+// Package signedurl builds and verifies signed URLs, such as download
+// links and webhook callbacks. A signed URL carries a random nonce, an
+// expiry and an HMAC-SHA256 signature over its path and query, so it
+// cannot be altered or used after it expires.
+//
+// Example:
+//
+//	link, err := signedurl.Build("https://example.com/files/report.pdf", nil, key, time.Hour)
+//	// In the handler serving the link:
+//	if err := signedurl.Verify(r, key); err != nil {
+//	    http.Error(w, "invalid link", http.StatusForbidden)
+//	}
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// Query parameters added by Build.
+const (
+	NonceParam     = "nonce"
+	ExpiresParam   = "exp"
+	SignatureParam = "sig"
+)
+
+var (
+	// ErrInvalidSignature is returned for a URL that is not signed with the
+	// key or was altered.
+	ErrInvalidSignature = &uriuniq.Error{Code: uriuniq.CodeInvalidToken, Err: errors.New("signedurl: invalid signature")}
+	// ErrExpired is returned for a URL past its expiry.
+	ErrExpired = &uriuniq.Error{Code: uriuniq.CodeTokenExpired, Err: errors.New("signedurl: expired")}
+)
+
+// nonces generates the nonces of Build.
+var nonces, _ = uriuniq.NewGenerator(uriuniq.NewOpts())
+
+// Build returns baseURL with params, a nonce, an expiry ttl from now and
+// a signature under key added to its query.
+func Build(baseURL string, params url.Values, key []byte, ttl time.Duration) (string, error) {
+	return build(baseURL, params, key, ttl, time.Now())
+}
+
+func build(baseURL string, params url.Values, key []byte, ttl time.Duration, now time.Time) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := nonces.Next()
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for k, vs := range params {
+		for _, v := range vs {
+			q.Add(k, v)
+		}
+	}
+	q.Set(NonceParam, nonce)
+	q.Set(ExpiresParam, strconv.FormatInt(now.Add(ttl).Unix(), 10))
+	q.Del(SignatureParam)
+	q.Set(SignatureParam, sign(u.EscapedPath(), q, key))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Verify checks the signature and expiry of the URL of r under key.
+func Verify(r *http.Request, key []byte) error {
+	return verify(r.URL, key, time.Now())
+}
+
+func verify(u *url.URL, key []byte, now time.Time) error {
+	q := u.Query()
+	sig := q.Get(SignatureParam)
+	q.Del(SignatureParam)
+	if sig == "" || !hmac.Equal([]byte(sig), []byte(sign(u.EscapedPath(), q, key))) {
+		return ErrInvalidSignature
+	}
+	exp, err := strconv.ParseInt(q.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !now.Before(time.Unix(exp, 0)) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// sign returns the signature of path and the query q, which is encoded
+// with sorted keys.
+func sign(path string, q url.Values, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(path))
+	mac.Write([]byte{'?'})
+	mac.Write([]byte(q.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
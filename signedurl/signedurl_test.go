@@ -0,0 +1,56 @@
+package signedurl
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestBuildVerify checks valid, altered and expired URLs.
+func TestBuildVerify(t *testing.T) {
+	key := []byte("key")
+	now := time.Unix(1700000000, 0)
+	link, err := build("https://example.com/files/a%20b.pdf?v=1", url.Values{"user": {"42"}}, key, time.Hour, now)
+	if err != nil {
+		t.Fatalf("build failed: %s", err)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Parse failed: %s", err)
+	}
+	q := u.Query()
+	if q.Get("v") != "1" || q.Get("user") != "42" || len(q.Get(NonceParam)) == 0 || q.Get(ExpiresParam) != "1700003600" {
+		t.Errorf("Unexpected query %v", q)
+	}
+
+	if err := verify(u, key, now); err != nil {
+		t.Errorf("verify failed: %s", err)
+	}
+	live, err := Build("https://example.com/files/x", nil, key, time.Minute)
+	if err != nil {
+		t.Fatalf("Build failed: %s", err)
+	}
+	if err := Verify(httptest.NewRequest("GET", live, nil), key); err != nil {
+		t.Errorf("Verify failed: %s", err)
+	}
+	if err := verify(u, key, now.Add(time.Hour)); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+	if err := verify(u, []byte("other"), now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for another key, got %v", err)
+	}
+
+	for _, altered := range []string{
+		strings.Replace(link, "user=42", "user=43", 1),
+		strings.Replace(link, "a%20b.pdf", "c.pdf", 1),
+		strings.Replace(link, "exp=1700003600", "exp=1800000000", 1),
+	} {
+		au, _ := url.Parse(altered)
+		if err := verify(au, key, now); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature for %s, got %v", altered, err)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//go:build go1.21
+
+package uriuniq
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// SlogLogf returns an Options.Logf that logs diagnostics to l as
+// warnings.
+func SlogLogf(l *slog.Logger) func(format string, args ...any) {
+	return func(format string, args ...any) {
+		l.Log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, args...))
+	}
+}
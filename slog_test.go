@@ -0,0 +1,22 @@
+//go:build go1.21
+
+package uriuniq
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestSlogLogf checks that diagnostics reach a slog.Logger as warnings.
+func TestSlogLogf(t *testing.T) {
+	var buf bytes.Buffer
+	opts := Options{Logf: SlogLogf(slog.New(slog.NewTextHandler(&buf, nil)))}
+	if _, err := Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, "using default length 16") {
+		t.Errorf("Unexpected log %q", out)
+	}
+}
@@ -0,0 +1,146 @@
+package uriuniq
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultSlugLength is the default length budget of SlugifyWithSuffix.
+const DefaultSlugLength = 80
+
+// SlugOptions configures SlugifyWithSuffix.
+type SlugOptions struct {
+	// Suffix generates the random suffix. Defaults to 6 lowercase letters
+	// and digits.
+	Suffix *Options
+	// Lang is the language of the title, as a BCP 47 tag. German ("de")
+	// spells umlauts as ae, oe and ue; Danish and Norwegian ("da", "nb",
+	// "nn", "no") spell å, æ and ø as aa, ae and oe.
+	Lang string
+	// MaxLength bounds the slug length, suffix included. Defaults to
+	// DefaultSlugLength.
+	MaxLength int
+}
+
+// translit spells common Latin letters with diacritics in ASCII.
+var translit = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ș': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// langTranslit overrides translit for some languages.
+var langTranslit = map[string]map[rune]string{
+	"de": {'ä': "ae", 'ö': "oe", 'ü': "ue"},
+	"da": {'å': "aa", 'ø': "oe"},
+	"nb": {'å': "aa", 'ø': "oe"},
+	"nn": {'å': "aa", 'ø': "oe"},
+	"no": {'å': "aa", 'ø': "oe"},
+}
+
+// SlugifyWithSuffix turns title into a lowercase, URI-safe slug and
+// appends a random suffix, as in "cafe-au-lait-x7k9q2". Letters with
+// diacritics are transliterated, other chars separate words with '-' and
+// letters outside the Latin script are dropped; a title with nothing left
+// gives the suffix alone. The title part is cut at a word boundary where
+// possible to fit opts.MaxLength.
+func SlugifyWithSuffix(title string, opts SlugOptions) (string, error) {
+	suffixOpts := Options{Length: 6, ExcludeUppercase: true}
+	if opts.Suffix != nil {
+		suffixOpts = *opts.Suffix
+	}
+	suffix, err := generateID(suffixOpts)
+	if err != nil {
+		return "", err
+	}
+	max := opts.MaxLength
+	if max <= 0 {
+		max = DefaultSlugLength
+	}
+	if len(suffix) > max {
+		return "", errorf(CodeInvalidLength, "uriuniq: suffix length %d exceeds slug length %d", len(suffix), max)
+	}
+
+	base := Slugify(title, opts.Lang)
+	if budget := max - len(suffix) - 1; len(base) > budget {
+		if budget <= 0 {
+			base = ""
+		} else if i := strings.LastIndexByte(base[:budget+1], '-'); i > 0 {
+			base = base[:i]
+		} else {
+			base = strings.TrimRight(base[:budget], "-")
+		}
+	}
+	if base == "" {
+		return suffix, nil
+	}
+	return base + "-" + suffix, nil
+}
+
+// Slugify turns title in language lang into a lowercase, URI-safe slug
+// of ASCII letters and digits separated by '-', as SlugifyWithSuffix does
+// without the suffix or a length budget.
+func Slugify(title, lang string) string {
+	overrides := langTranslit[strings.ToLower(strings.SplitN(lang, "-", 2)[0])]
+	var b strings.Builder
+	pendingSep := false
+	emit := func(s string) {
+		if pendingSep && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingSep = false
+		b.WriteString(s)
+	}
+	for _, r := range title {
+		r = unicode.ToLower(r)
+		switch {
+		case r < unicode.MaxASCII && ('a' <= r && r <= 'z' || '0' <= r && r <= '9'):
+			emit(string(r))
+		case overrides[r] != "":
+			emit(overrides[r])
+		case translit[r] != "":
+			emit(translit[r])
+		case unicode.In(r, unicode.Mn, unicode.Cf):
+			// Combining marks and invisible chars join their neighbours.
+		default:
+			pendingSep = true
+		}
+	}
+	return b.String()
+}
+
+// MaxConflictSuffix is the longest suffix RegenerateOnConflict appends.
+const MaxConflictSuffix = 16
+
+// RegenerateOnConflict returns the slug to try after attempt slugs based
+// on slugBase were taken, as by soft-deleted rows still holding their
+// unique slugs. Attempt 0 returns slugBase itself; attempt n appends '-'
+// and a random suffix of 2^(n-1) chars from the charset of opts, up to
+// MaxConflictSuffix: "post", "post-x", "post-x7", "post-x7k9" and so on.
+// The growing suffix leaves longer and longer odds of another collision,
+// so retrying with attempt+1 converges in a few steps. Length in opts is
+// ignored.
+func RegenerateOnConflict(slugBase string, attempt int, opts Options) (string, error) {
+	if attempt <= 0 {
+		return slugBase, nil
+	}
+	opts.Length = MaxConflictSuffix
+	if attempt <= 5 {
+		opts.Length = 1 << (attempt - 1)
+	}
+	opts.MinLength, opts.MaxLength = 0, 0
+	suffix, err := generate(opts)
+	if err != nil {
+		return "", err
+	}
+	if slugBase == "" {
+		return string(suffix), nil
+	}
+	return slugBase + "-" + string(suffix), nil
+}
@@ -0,0 +1,97 @@
+package uriuniq
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSlugify checks transliteration and word separation.
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		lang  string
+		want  string
+	}{
+		{"Hello, World!", "", "hello-world"},
+		{"  Café au Lait  ", "fr", "cafe-au-lait"},
+		{"Über Straße", "", "uber-strasse"},
+		{"Über Straße", "de-AT", "ueber-strasse"},
+		{"Smørrebrød på Ærø", "da", "smoerrebroed-paa-aeroe"},
+		{"Łódź 2024", "pl", "lodz-2024"},
+		{"Café", "", "cafe"},
+		{"Привет мир", "", ""},
+		{"Go — 中文 — Rocks", "", "go-rocks"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			if got := Slugify(tc.title, tc.lang); got != tc.want {
+				t.Errorf("Expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// TestSlugifyWithSuffix checks the suffix and the length budget.
+func TestSlugifyWithSuffix(t *testing.T) {
+	slug, err := SlugifyWithSuffix("Hello World", SlugOptions{})
+	if err != nil {
+		t.Fatalf("SlugifyWithSuffix failed: %s", err)
+	}
+	if !strings.HasPrefix(slug, "hello-world-") || len(slug) != len("hello-world-")+6 || !isURISafe(slug) {
+		t.Errorf("Unexpected slug %q", slug)
+	}
+
+	tests := []struct {
+		name  string
+		title string
+		max   int
+		base  string
+	}{
+		{"Word Boundary", "the quick brown fox", 20, "the-quick"},
+		{"Long Word", "supercalifragilistic", 15, "supercal"},
+		{"Suffix Only", "中文", 20, ""},
+		{"No Room", "hello", 7, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			slug, err := SlugifyWithSuffix(tc.title, SlugOptions{MaxLength: tc.max})
+			if err != nil {
+				t.Fatalf("SlugifyWithSuffix failed: %s", err)
+			}
+			base := strings.TrimSuffix(slug, slug[len(slug)-6:])
+			if strings.TrimSuffix(base, "-") != tc.base || len(slug) > tc.max {
+				t.Errorf("Expected base %q within %d, got %q", tc.base, tc.max, slug)
+			}
+		})
+	}
+	if _, err := SlugifyWithSuffix("x", SlugOptions{MaxLength: 4}); CodeOf(err) != CodeInvalidLength {
+		t.Errorf("Expected invalid length error, got %v", err)
+	}
+	custom := Options{Length: 3, CustomCharset: Numeric}
+	if slug, _ := SlugifyWithSuffix("a", SlugOptions{Suffix: &custom}); len(slug) != 5 || checkCharset(slug[2:], Numeric) != nil {
+		t.Errorf("Unexpected custom suffix slug %q", slug)
+	}
+}
+
+// TestRegenerateOnConflict checks the growing suffix lengths.
+func TestRegenerateOnConflict(t *testing.T) {
+	opts := Options{ExcludeUppercase: true}
+	for attempt, want := range []int{0, 1, 2, 4, 8, 16, 16, 16} {
+		slug, err := RegenerateOnConflict("post", attempt, opts)
+		if err != nil {
+			t.Fatalf("Attempt %d failed: %s", attempt, err)
+		}
+		if want == 0 {
+			if slug != "post" {
+				t.Errorf("Expected the base slug, got %q", slug)
+			}
+			continue
+		}
+		if !strings.HasPrefix(slug, "post-") || len(slug) != 5+want || checkCharset(slug[5:], Numeric+Lowercase) != nil {
+			t.Errorf("Attempt %d: expected %d-char suffix, got %q", attempt, want, slug)
+		}
+	}
+	if slug, _ := RegenerateOnConflict("", 2, opts); len(slug) != 2 {
+		t.Errorf("Expected a bare suffix, got %q", slug)
+	}
+}
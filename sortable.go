@@ -0,0 +1,184 @@
+package uriuniq
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortableTimeBits is the width of the millisecond timestamp of sortable
+// IDs, enough for times up to the year 10889.
+const SortableTimeBits = 48
+
+// GenerateSortable creates an ID whose first chars encode the current
+// Unix time in milliseconds, followed by random chars, so IDs sort
+// lexicographically by creation time, as ULIDs do. Database primary keys
+// keep insert locality this way. The timestamp takes SortableTimeWidth
+// chars of Length and carries no entropy; IDs of the same millisecond sort
+// randomly, unless created by a Monotonic.
+//
+// The timestamp digits are the chars of the charset in byte order, so
+// sorting works for any charset, but all IDs must use the same Options.
+func GenerateSortable(opts Options) (string, error) {
+	return generateSortable(opts, time.Now())
+}
+
+// generateSortable creates a sortable ID for the time now.
+func generateSortable(opts Options, now time.Time) (string, error) {
+	l, err := newSortableLayout(opts)
+	if err != nil {
+		return "", err
+	}
+	ms, err := l.millis(now)
+	if err != nil {
+		return "", err
+	}
+	output, err := generate(l.random)
+	if err != nil {
+		return "", err
+	}
+	id := l.id(ms, output)
+	if opts.Sensitive {
+		wipe(output)
+	}
+	return id, nil
+}
+
+// sortableLayout splits the Options of sortable IDs into a timestamp and
+// the Options of the random part after it.
+type sortableLayout struct {
+	opts    Options // As given, for the prefix, check char and escaping
+	random  Options // Prepared, for the random part
+	charset []byte
+	digits  []byte // Timestamp digits
+	width   int    // Timestamp chars
+}
+
+// newSortableLayout prepares opts for sortable IDs.
+func newSortableLayout(opts Options) (sortableLayout, error) {
+	prepared, charset, err := prepare(opts)
+	if err != nil {
+		return sortableLayout{}, err
+	}
+	if prepared.NodeChars > 0 || prepared.NoLeadingDigit {
+		return sortableLayout{}, newError(CodeInvalidArgument, "uriuniq: sortable IDs cannot embed a node or avoid a leading digit")
+	}
+	digits, err := sortableDigits(charset)
+	if err != nil {
+		return sortableLayout{}, err
+	}
+	width := sortableWidth(len(digits))
+	if min, _ := lengthRange(prepared); min <= width {
+		return sortableLayout{}, errorf(CodeInvalidLength, "uriuniq: sortable IDs need more than %d chars", width)
+	}
+
+	random := prepared
+	random.Length -= width
+	if random.MaxLength > 0 {
+		random.MinLength -= width
+		random.MaxLength -= width
+	}
+	random.MinEntropyBits = 0 // Already applied
+	random.CheckChar = false  // Appended over the whole ID below
+	// The timestamp is predictable, so only the random part counts.
+	if err := checkEntropyFloor(random, shortestEntropy(random, charset)); err != nil {
+		return sortableLayout{}, err
+	}
+	random.RequireEntropyBits = 0 // Already checked
+	return sortableLayout{opts: opts, random: random, charset: charset, digits: digits, width: width}, nil
+}
+
+// millis returns the timestamp of now.
+func (l sortableLayout) millis(now time.Time) (int64, error) {
+	ms := now.UnixMilli()
+	if ms < 0 || ms >= 1<<SortableTimeBits {
+		return 0, errorf(CodeInvalidArgument, "uriuniq: time %s out of range for sortable IDs", now)
+	}
+	return ms, nil
+}
+
+// id returns the ID of the timestamp ms and the random chars output.
+func (l sortableLayout) id(ms int64, output []byte) string {
+	id := make([]byte, l.width, l.width+len(output)+1)
+	for i := l.width - 1; i >= 0; i-- {
+		id[i] = l.digits[ms%int64(len(l.digits))]
+		ms /= int64(len(l.digits))
+	}
+	id = appendCheckChar(l.opts, l.charset, append(id, output...))
+	s := escapeOutput(l.opts, id)
+	if l.opts.Sensitive {
+		wipe(id)
+	}
+	return s
+}
+
+// SortableTime returns the creation time encoded in id, an ID generated
+// with GenerateSortable and opts.
+func SortableTime(id string, opts Options) (time.Time, error) {
+	_, charset, err := prepare(opts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	digits, err := sortableDigits(charset)
+	if err != nil {
+		return time.Time{}, err
+	}
+	width := sortableWidth(len(digits))
+	if id, err = ungroup(opts, strings.TrimPrefix(id, idPrefix(opts))); err != nil {
+		return time.Time{}, err
+	}
+	if len(id) <= width {
+		return time.Time{}, errorf(CodeInvalidLength, "uriuniq: sortable ID shorter than %d chars", width+1)
+	}
+	var ms int64
+	for i := 0; i < width; i++ {
+		d := IndexOf(Charset(digits), id[i])
+		if d < 0 {
+			return time.Time{}, &ParseError{Index: groupedIndex(opts, i), Char: rune(id[i]), Charset: Charset(charset)}
+		}
+		ms = ms*int64(len(digits)) + int64(d)
+		if ms >= 1<<SortableTimeBits {
+			return time.Time{}, newError(CodeInvalidField, "uriuniq: sortable timestamp out of range")
+		}
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// SortableTimeWidth returns the number of chars of the timestamp of IDs
+// generated with GenerateSortable and opts, such as 9 for Alphanumeric.
+func SortableTimeWidth(opts Options) (int, error) {
+	_, charset, err := prepare(opts)
+	if err != nil {
+		return 0, err
+	}
+	digits, err := sortableDigits(charset)
+	if err != nil {
+		return 0, err
+	}
+	return sortableWidth(len(digits)), nil
+}
+
+// sortableWidth returns the chars needed for SortableTimeBits in base n.
+func sortableWidth(n int) int {
+	width := 0
+	for span := uint64(1); span < 1<<SortableTimeBits; span *= uint64(n) {
+		width++
+	}
+	return width
+}
+
+// sortableDigits returns the timestamp digits for charset, its chars in
+// byte order.
+func sortableDigits(charset []byte) ([]byte, error) {
+	if len(charset) < 2 {
+		return nil, newError(CodeCharsetSize, "uriuniq: sortable IDs need at least 2 chars")
+	}
+	digits := append([]byte(nil), charset...)
+	sort.Slice(digits, func(i, j int) bool { return digits[i] < digits[j] })
+	for i := 1; i < len(digits); i++ {
+		if digits[i] == digits[i-1] {
+			return nil, errorf(CodeDuplicateChar, "uriuniq: duplicate char %q in charset", digits[i])
+		}
+	}
+	return digits, nil
+}
@@ -0,0 +1,68 @@
+package uriuniq
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestGenerateSortable checks that IDs sort by creation time and that the
+// time can be extracted.
+func TestGenerateSortable(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		width int
+	}{
+		{"alphanumeric", Options{Length: 20}, 9},
+		{"numeric", Options{Length: 24, CustomCharset: Numeric}, 15},
+		{"range", Options{MinLength: 12, MaxLength: 16, CustomCharset: Lowercase}, 11},
+	}
+	start := time.UnixMilli(1700000000000)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w, err := SortableTimeWidth(tt.opts); err != nil || w != tt.width {
+				t.Errorf("Expected width %d, got %d, %v", tt.width, w, err)
+			}
+			var ids []string
+			for i := 0; i < 50; i++ {
+				now := start.Add(time.Duration(i*7919) * time.Millisecond)
+				id, err := generateSortable(tt.opts, now)
+				if err != nil {
+					t.Fatalf("generateSortable failed: %s", err)
+				}
+				if got, err := SortableTime(id, tt.opts); err != nil || !got.Equal(now) {
+					t.Errorf("Expected time %s, got %s, %v", now, got, err)
+				}
+				ids = append(ids, id)
+			}
+			if !sort.StringsAreSorted(ids) {
+				t.Errorf("IDs are not sorted: %v", ids)
+			}
+		})
+	}
+}
+
+// TestGenerateSortableErrors checks rejecting short lengths, times out of
+// range and malformed timestamps.
+func TestGenerateSortableErrors(t *testing.T) {
+	if _, err := GenerateSortable(Options{Length: 9}); CodeOf(err) != CodeInvalidLength {
+		t.Errorf("Expected CodeInvalidLength, got %v", err)
+	}
+	if _, err := generateSortable(Options{Length: 20}, time.UnixMilli(-1)); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+	if _, err := GenerateSortable(Options{Length: 20, CustomCharset: "aab"}); CodeOf(err) != CodeDuplicateChar {
+		t.Errorf("Expected CodeDuplicateChar, got %v", err)
+	}
+	opts := Options{Length: 20}
+	if _, err := SortableTime("short", opts); CodeOf(err) != CodeInvalidLength {
+		t.Errorf("Expected CodeInvalidLength, got %v", err)
+	}
+	if _, err := SortableTime("zzzzzzzzzabc", opts); CodeOf(err) != CodeInvalidField {
+		t.Errorf("Expected CodeInvalidField, got %v", err)
+	}
+	if _, err := SortableTime("0000-0000abc", opts); CodeOf(err) != CodeInvalidChar {
+		t.Errorf("Expected CodeInvalidChar, got %v", err)
+	}
+}
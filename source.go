@@ -0,0 +1,38 @@
+//go:build go1.22
+
+package uriuniq
+
+import (
+	"encoding/binary"
+	"math/rand/v2"
+)
+
+// MustSource returns a math/rand/v2 Source reading from the entropy source
+// of g, so code needing a *rand.Rand, such as for jitter or sampling, draws
+// from the same audited entropy chain as the IDs:
+//
+//	r := rand.New(uriuniq.MustSource(g))
+//	delay := time.Duration(r.Int64N(int64(time.Second)))
+//
+// This direction is as safe as the EntropySource of g: the values are
+// crypto-grade when it is crypto/rand. The reverse is not: never set a
+// math/rand Source, seeded or not, as Options.EntropySource, because its
+// output is predictable.
+//
+// Uint64 panics if the entropy source fails, since a Source cannot return
+// errors; hence the Must. The Source is safe for concurrent use; a *rand.Rand built on it
+// is only as safe as its own methods.
+func MustSource(g *Generator) rand.Source {
+	return generatorSource{g}
+}
+
+// generatorSource is the Source returned by MustSource.
+type generatorSource struct{ g *Generator }
+
+func (s generatorSource) Uint64() uint64 {
+	var b [8]byte
+	if err := s.g.readEntropy(b[:]); err != nil {
+		panic("uriuniq: entropy source failed: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
@@ -0,0 +1,46 @@
+//go:build go1.22
+
+package uriuniq
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+// TestMustSource checks that values come from the EntropySource of the
+// Generator and that a failing source panics.
+func TestMustSource(t *testing.T) {
+	g, err := NewGenerator(Options{Length: 8, EntropySource: bytes.NewReader(make([]byte, 8)), EntropyBufferSize: -1})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	if v := MustSource(g).Uint64(); v != 0 {
+		t.Errorf("Expected 0 from a zero source, got %d", v)
+	}
+
+	g, err = NewGenerator(NewOpts())
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	r := rand.New(MustSource(g))
+	seen := make(map[uint64]bool)
+	for i := 0; i < 100; i++ {
+		seen[r.Uint64()] = true
+	}
+	if len(seen) != 100 {
+		t.Errorf("Expected 100 distinct values, got %d", len(seen))
+	}
+
+	g, err = NewGenerator(Options{Length: 8, EntropySource: errReader{errors.New("source down")}, EntropyBufferSize: -1})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a failing source")
+		}
+	}()
+	MustSource(g).Uint64()
+}
@@ -0,0 +1,112 @@
+package uriuniq
+
+import (
+	"strings"
+	"unicode"
+)
+
+// natoWords are the words of the NATO phonetic alphabet for 'a' to 'z'.
+var natoWords = [26]string{
+	"alfa", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliett", "kilo", "lima", "mike", "november", "oscar", "papa",
+	"quebec", "romeo", "sierra", "tango", "uniform", "victor", "whiskey",
+	"xray", "yankee", "zulu",
+}
+
+// digitWords are the names of the digits.
+var digitWords = [10]string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+// symbolWords are the names of the other URI-safe chars.
+var symbolWords = map[byte]string{
+	'-': "dash", '_': "underscore", '.': "dot", '~': "tilde", '!': "bang",
+	'*': "star", '\'': "apostrophe", '(': "open", ')': "close",
+}
+
+// spellAliases are accepted by ParseSpellOut besides the words above.
+var spellAliases = map[string]byte{"alpha": 'a', "juliet": 'j', "x-ray": 'x', "niner": '9', "hyphen": '-', "period": '.'}
+
+// capitalWord marks an uppercase letter, as in "capital alfa".
+const capitalWord = "capital"
+
+// SpellOut returns the words to read id aloud, one per char: NATO words
+// for letters, prefixed with "capital" for uppercase ones, and names for
+// digits and symbols, such as "dash". Chars without a name are returned as
+// is. For IDs read over the phone, prefer a single-case charset such as
+// ShortCodeCharset, which needs no "capital".
+func SpellOut(id string) []string {
+	words := make([]string, 0, len(id))
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case 'a' <= c && c <= 'z':
+			words = append(words, natoWords[c-'a'])
+		case 'A' <= c && c <= 'Z':
+			words = append(words, capitalWord+" "+natoWords[c-'A'])
+		case '0' <= c && c <= '9':
+			words = append(words, digitWords[c-'0'])
+		default:
+			if w, ok := symbolWords[c]; ok {
+				words = append(words, w)
+			} else {
+				words = append(words, string(c))
+			}
+		}
+	}
+	return words
+}
+
+// ParseSpellOut parses words read aloud back into an ID. It accepts the
+// output of SpellOut joined by spaces or commas, in any case, and common
+// variants such as "alpha" and "niner". Unknown words are reported as a
+// *ParseError indexed by word. Inputs longer than DefaultMaxInputLength
+// are rejected with ErrInputTooLong.
+func ParseSpellOut(s string) (string, error) {
+	if err := checkInputLength(s, DefaultMaxInputLength); err != nil {
+		return "", err
+	}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	lookup := make(map[string]byte, 64)
+	for i, w := range natoWords {
+		lookup[w] = byte('a' + i)
+	}
+	for i, w := range digitWords {
+		lookup[w] = byte('0' + i)
+	}
+	for c, w := range symbolWords {
+		lookup[w] = c
+	}
+	for w, c := range spellAliases {
+		lookup[w] = c
+	}
+
+	var id []byte
+	capital := false
+	for i, w := range words {
+		if w == capitalWord {
+			capital = true
+			continue
+		}
+		c, ok := lookup[w]
+		if !ok && len(w) == 1 && w[0] < unicode.MaxASCII {
+			c, ok = w[0], true
+		}
+		if !ok || capital && !('a' <= c && c <= 'z') {
+			r := []rune(w)[0]
+			return "", &ParseError{Index: i, Char: r}
+		}
+		if capital {
+			c -= 'a' - 'A'
+			capital = false
+		}
+		id = append(id, c)
+	}
+	if capital {
+		return "", newError(CodeInvalidArgument, "uriuniq: \"capital\" without a letter")
+	}
+	if len(id) == 0 {
+		return "", ErrEmptyInput
+	}
+	return string(id), nil
+}
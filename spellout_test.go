@@ -0,0 +1,53 @@
+package uriuniq
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSpellOut checks the words and the round trip through ParseSpellOut.
+func TestSpellOut(t *testing.T) {
+	words := SpellOut("aZ9-")
+	want := []string{"alfa", "capital zulu", "nine", "dash"}
+	if strings.Join(words, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %q, got %q", want, words)
+	}
+
+	for i := 0; i < 20; i++ {
+		opts := NewOpts()
+		opts.CustomCharset = uriSafe
+		id, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate failed: %s", err)
+		}
+		got, err := ParseSpellOut(strings.Join(SpellOut(id), " "))
+		if err != nil || got != id {
+			t.Fatalf("Round trip of %q gave %q, %v", id, got, err)
+		}
+	}
+}
+
+// TestParseSpellOut checks variants and errors.
+func TestParseSpellOut(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"Alpha, Bravo, niner", "ab9", true},
+		{"CAPITAL x-ray  one", "X1", true},
+		{"echo banana", "", false},
+		{"capital one", "", false},
+		{"alfa capital", "", false},
+		{"", "", false},
+	}
+	for _, tc := range tests {
+		got, err := ParseSpellOut(tc.input)
+		if tc.valid && (err != nil || got != tc.expected) {
+			t.Errorf("ParseSpellOut(%q) = %q, %v, expected %q", tc.input, got, err, tc.expected)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("ParseSpellOut(%q) should fail, got %q", tc.input, got)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package uriuniq
+
+import "math"
+
+// DistributionReport describes the distribution of chars in a sample of
+// IDs, as returned by ChiSquare.
+type DistributionReport struct {
+	Samples int
+	Charset Charset
+	// Counts holds the occurrences of each char of Charset, over all
+	// positions.
+	Counts []int
+	// ChiSquare is Pearson's statistic of Counts against a uniform
+	// distribution, with len(Charset)-1 degrees of freedom.
+	ChiSquare float64
+	// PValue is the probability of a statistic at least as large from an
+	// unbiased generator. Values near 0 are evidence of bias.
+	PValue float64
+	// PositionPValues holds the p-value of the chars at each position, to
+	// reveal bias that averages out across positions.
+	PositionPValues []float64
+}
+
+// Biased reports whether the sample is evidence of bias at significance
+// alpha, such as 0.001, overall or at any position. The positions are
+// tested at alpha divided by their number, so that many positions do not
+// make a false alarm likely.
+func (r DistributionReport) Biased(alpha float64) bool {
+	if r.PValue < alpha {
+		return true
+	}
+	for _, p := range r.PositionPValues {
+		if p < alpha/float64(len(r.PositionPValues)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChiSquare draws samples IDs from g and tests the chars they are made of
+// for uniformity, overall and per position, as evidence that the sampler
+// is unbiased for the charset of g. IDs are taken before any prefix,
+// grouping or escaping. Chars that are not drawn uniformly by design show
+// as bias: test Options without a fingerprint, Node, check char,
+// NoLeadingDigit, DNSLabel or Min counts to test the sampler alone.
+// Each position needs a few samples per char, say 50 times the charset
+// size, for the p-values to be reliable.
+func ChiSquare(g *Generator, samples int) (DistributionReport, error) {
+	if samples <= 0 {
+		return DistributionReport{}, newError(CodeInvalidArgument, "uriuniq: samples must be positive")
+	}
+	if g.runes != nil {
+		return DistributionReport{}, newError(CodeInvalidArgument, "uriuniq: ChiSquare needs a single-byte charset")
+	}
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i, c := range g.charset {
+		index[c] = i
+	}
+	size := len(g.charset)
+	counts := make([]int, size)
+	var positions [][]int
+	buf := make([]byte, 0, 64)
+	for n := 0; n < samples; n++ {
+		output, err := g.next(buf[:0])
+		if err != nil {
+			return DistributionReport{}, err
+		}
+		g.count()
+		for len(positions) < len(output) {
+			positions = append(positions, make([]int, size))
+		}
+		for pos, c := range output {
+			// Check chars may fall outside a charset they are not drawn from.
+			if i := index[c]; i >= 0 {
+				counts[i]++
+				positions[pos][i]++
+			}
+		}
+		buf = output
+	}
+
+	r := DistributionReport{Samples: samples, Charset: Charset(g.charset), Counts: counts}
+	r.ChiSquare, r.PValue = chiSquare(counts)
+	for _, pos := range positions {
+		_, p := chiSquare(pos)
+		r.PositionPValues = append(r.PositionPValues, p)
+	}
+	return r, nil
+}
+
+// chiSquare returns Pearson's statistic of counts against a uniform
+// distribution and its p-value.
+func chiSquare(counts []int) (stat, p float64) {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 || len(counts) < 2 {
+		return 0, 1
+	}
+	expected := float64(total) / float64(len(counts))
+	for _, c := range counts {
+		d := float64(c) - expected
+		stat += d * d / expected
+	}
+	return stat, gammaQ(float64(len(counts)-1)/2, stat/2)
+}
+
+// gammaQ returns the regularized upper incomplete gamma function Q(a, x),
+// the survival function of the chi-square distribution with 2a degrees
+// of freedom at 2x. It uses the series of P(a, x) below x = a+1 and a
+// continued fraction above, as in Numerical Recipes.
+func gammaQ(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lg, _ := math.Lgamma(a)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1.0; n < 1000; n++ {
+			term *= x / (a + n)
+			sum += term
+			if term < sum*1e-15 {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lg)
+	}
+	const tiny = 1e-300
+	b := x + 1 - a
+	c, d := 1/tiny, 1/b
+	h := d
+	for i := 1.0; i < 1000; i++ {
+		an := -i * (i - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lg) * h
+}
@@ -0,0 +1,62 @@
+package uriuniq
+
+import (
+	"math"
+	"testing"
+)
+
+// TestChiSquare checks that the sampler shows no bias and that a leading
+// char drawn from a subset does.
+func TestChiSquare(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		biased bool
+	}{
+		{"Alphanumeric", Options{Length: 8}, false},
+		{"Base58", Options{Length: 8, CustomCharset: Base58}, false},
+		{"Hex Arithmetic", Options{Length: 8, CustomCharset: Hex, Sampler: SamplerArithmetic}, false},
+		{"No Leading Digit", Options{Length: 8, NoLeadingDigit: true}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := NewGenerator(tc.opts)
+			if err != nil {
+				t.Fatalf("NewGenerator failed: %s", err)
+			}
+			r, err := ChiSquare(g, 20000)
+			if err != nil {
+				t.Fatalf("ChiSquare failed: %s", err)
+			}
+			if len(r.PositionPValues) != 8 || len(r.Counts) != len(r.Charset) {
+				t.Fatalf("Unexpected report shape %d, %d", len(r.PositionPValues), len(r.Counts))
+			}
+			if r.Biased(1e-4) != tc.biased {
+				t.Errorf("Expected biased %t, got p-value %g, positions %v", tc.biased, r.PValue, r.PositionPValues)
+			}
+		})
+	}
+	if _, err := ChiSquare(nil, 0); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+}
+
+// TestGammaQ checks p-values against chi-square tables.
+func TestGammaQ(t *testing.T) {
+	tests := []struct {
+		df   int
+		stat float64
+		p    float64
+	}{
+		{1, 3.841, 0.05},
+		{10, 18.307, 0.05},
+		{61, 80.232, 0.05},
+		{2, 4, math.Exp(-2)},
+		{30, 20.599, 0.90},
+	}
+	for _, tc := range tests {
+		if p := gammaQ(float64(tc.df)/2, tc.stat/2); math.Abs(p-tc.p) > 1e-3 {
+			t.Errorf("gammaQ for %d degrees at %g = %g, expected %g", tc.df, tc.stat, p, tc.p)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package uriuniq
+
+import (
+	"context"
+	"sync"
+)
+
+// Stream produces IDs from a Generator in a background goroutine and
+// sends them on C, which holds up to the buffer size of IDs ready for a hot
+// path. It stops, closing C, when its context is done, the Generator fails
+// or it is closed; Err then reports why. A Stream is safe for concurrent
+// use.
+type Stream struct {
+	// C receives the IDs.
+	C <-chan string
+
+	ch     chan string
+	cancel context.CancelFunc
+	done   chan struct{} // Closed when the goroutine has exited
+
+	mu     sync.Mutex
+	err    error
+	closed bool
+}
+
+// Stream starts producing IDs into a channel with buf slots until ctx is
+// done, and returns the channel. IDs are sent in the order generated. Use
+// NewStream to learn why the channel was closed or to stop it early.
+func (g *Generator) Stream(ctx context.Context, buf int) <-chan string {
+	return g.NewStream(ctx, buf).C
+}
+
+// NewStream starts producing IDs like Stream and returns the Stream.
+func (g *Generator) NewStream(ctx context.Context, buf int) *Stream {
+	if buf < 0 {
+		buf = 0
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan string, buf)
+	s := &Stream{C: ch, ch: ch, cancel: cancel, done: make(chan struct{})}
+	go s.run(ctx, g)
+	return s
+}
+
+// run generates IDs until ctx is done or g fails.
+func (s *Stream) run(ctx context.Context, g *Generator) {
+	// Close done first, so Err reports the error once C is closed.
+	defer close(s.ch)
+	defer close(s.done)
+	for {
+		if err := ctx.Err(); err != nil {
+			s.setErr(err)
+			return
+		}
+		id, err := g.Next()
+		if err != nil {
+			s.setErr(err)
+			return
+		}
+		select {
+		case s.ch <- id:
+		case <-ctx.Done():
+			// id was never served, so dropping it keeps uniqueness.
+			s.setErr(ctx.Err())
+			return
+		}
+	}
+}
+
+// setErr records the first reason the Stream stopped.
+func (s *Stream) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// Err returns nil while the Stream runs, ErrClosed after Close or Drain,
+// and otherwise the context or Generator error that stopped it.
+func (s *Stream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Close implements Drainer. It waits for the goroutine to exit.
+func (s *Stream) Close() error {
+	_, err := s.Drain(context.Background())
+	return err
+}
+
+// Drain implements Drainer, returning the IDs left in C.
+func (s *Stream) Drain(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var ids []string
+	for id := range s.ch {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,91 @@
+package uriuniq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/laofun/uriuniq/uriuniqtest"
+)
+
+// TestStream checks producing IDs until the context is cancelled.
+func TestStream(t *testing.T) {
+	uriuniqtest.CheckGoroutines(t)
+	g, err := NewGenerator(NewOpts())
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := g.NewStream(ctx, 4)
+	seen := make(map[string]bool)
+	for id := range s.C {
+		if seen[id] {
+			t.Fatalf("Duplicate ID %s", id)
+		}
+		seen[id] = true
+		if len(seen) == 100 {
+			cancel()
+			break
+		}
+	}
+	for range s.C {
+	}
+	if err := s.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestStreamError checks that a failing source stops the Stream.
+func TestStreamError(t *testing.T) {
+	uriuniqtest.CheckGoroutines(t)
+	failure := errors.New("source down")
+	opts := NewOpts()
+	opts.EntropySource = &switchReader{err: failure}
+	opts.EntropyBufferSize = -1
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	s := g.NewStream(context.Background(), 1)
+	if _, ok := <-s.C; ok {
+		t.Errorf("Expected closed channel")
+	}
+	if err := s.Err(); !errors.Is(err, failure) {
+		t.Errorf("Expected source error, got %v", err)
+	}
+}
+
+// TestStreamDrain checks that Drain stops the Stream and returns the
+// unserved IDs.
+func TestStreamDrain(t *testing.T) {
+	uriuniqtest.CheckGoroutines(t)
+	g, err := NewGenerator(NewOpts())
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	var d Drainer = g.NewStream(context.Background(), 8)
+	s := d.(*Stream)
+	first := <-s.C
+	ids, err := d.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("Drain failed: %s", err)
+	}
+	if len(ids) > 8 {
+		t.Errorf("Expected at most 8 IDs, got %d", len(ids))
+	}
+	for _, id := range ids {
+		if id == first {
+			t.Errorf("Served ID %s returned by Drain", id)
+		}
+	}
+	if _, ok := <-s.C; ok {
+		t.Errorf("Expected closed channel")
+	}
+	if err := s.Err(); err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close after Drain failed: %s", err)
+	}
+}
@@ -0,0 +1,40 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestStrict checks that Strict returns the sentinel errors instead of
+// falling back.
+func TestStrict(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		err  error
+	}{
+		{"zero length", Options{Strict: true}, ErrInvalidLength},
+		{"negative length", Options{Strict: true, Length: -1}, ErrInvalidLength},
+		{"unsafe charset", Options{Strict: true, Length: 8, CustomCharset: "ab/"}, ErrUnsafeCharset},
+		{"escaped charset", Options{Strict: true, Length: 8, CustomCharset: "ab/", Escape: EscapePercent}, nil},
+		{"entropy sized", Options{Strict: true, MinEntropyBits: 64}, nil},
+		{"empty charset", Options{Length: 8, ExcludeAmbiguous: true, CustomCharset: "01"}, ErrEmptyCharset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.opts)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+	if _, err := Generate(Options{Strict: true}); CodeOf(err) != CodeInvalidLength {
+		t.Errorf("Expected CodeInvalidLength, got %s", CodeOf(err))
+	}
+	if _, err := Generate(Options{Strict: true, Length: 8, CustomCharset: "abca"}); CodeOf(err) != CodeDuplicateChar {
+		t.Errorf("Expected CodeDuplicateChar, got %v", err)
+	}
+	if _, err := BuildOptions(WithStrict(), WithCharset("ab/")); !errors.Is(err, ErrUnsafeCharset) {
+		t.Errorf("Expected ErrUnsafeCharset from BuildOptions, got %v", err)
+	}
+}
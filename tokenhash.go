@@ -0,0 +1,111 @@
+package uriuniq
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"strings"
+	"sync"
+)
+
+// Token hash algorithms registered by default.
+const (
+	HashSHA256 = "sha256"
+	HashSHA512 = "sha512"
+)
+
+// hashPrefix starts every hash created by HashToken.
+const hashPrefix = "$uuh2$"
+
+var (
+	hashAlgsMu     sync.RWMutex
+	hashAlgsFrozen bool
+	hashAlgs       = map[string]func() hash.Hash{
+		HashSHA256: sha256.New,
+		HashSHA512: sha512.New,
+	}
+)
+
+// RegisterHashAlg makes a hash algorithm available to HashToken and
+// VerifyHashedToken under name, for example BLAKE2b from
+// golang.org/x/crypto/blake2b, which this module does not depend on:
+//
+//	uriuniq.RegisterHashAlg("blake2b", func() hash.Hash {
+//	    h, _ := blake2b.New512(nil)
+//	    return h
+//	})
+//
+// The name may not contain '$'. It returns ErrFrozen after
+// FreezeHashAlgs.
+func RegisterHashAlg(name string, h func() hash.Hash) error {
+	if name == "" || strings.ContainsRune(name, '$') {
+		return errorf(CodeInvalidArgument, "uriuniq: invalid hash algorithm name %q", name)
+	}
+	hashAlgsMu.Lock()
+	defer hashAlgsMu.Unlock()
+	if hashAlgsFrozen {
+		return ErrFrozen
+	}
+	hashAlgs[name] = h
+	return nil
+}
+
+// FreezeHashAlgs makes the set of hash algorithms immutable.
+func FreezeHashAlgs() {
+	hashAlgsMu.Lock()
+	defer hashAlgsMu.Unlock()
+	hashAlgsFrozen = true
+}
+
+// hashAlg returns the registered algorithm name.
+func hashAlg(name string) (func() hash.Hash, error) {
+	hashAlgsMu.RLock()
+	defer hashAlgsMu.RUnlock()
+	h, ok := hashAlgs[name]
+	if !ok {
+		return nil, errorf(CodeInvalidArgument, "uriuniq: unknown hash algorithm %q", name)
+	}
+	return h, nil
+}
+
+// HashToken returns the digest of token to store in place of it: the HMAC
+// of token under pepper with the hash algorithm alg. The digest is self
+// describing, as in "$uuh2$sha256$<base64url digest>", so stored digests
+// can move to a new algorithm over time. Keep the pepper out of the
+// database holding the digests.
+func HashToken(token string, pepper []byte, alg string) (string, error) {
+	h, err := hashAlg(alg)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(h, pepper)
+	mac.Write([]byte(token))
+	return hashPrefix + alg + "$" + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// VerifyHashedToken reports whether token matches a digest created by
+// HashToken with pepper, in constant time. Use HashedTokenAlg to find
+// digests to rehash after verifying.
+func VerifyHashedToken(token string, pepper []byte, hashed string) (bool, error) {
+	alg, err := HashedTokenAlg(hashed)
+	if err != nil {
+		return false, err
+	}
+	want, err := HashToken(token, pepper, alg)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal([]byte(want), []byte(hashed)), nil
+}
+
+// HashedTokenAlg returns the algorithm of a digest created by HashToken.
+func HashedTokenAlg(hashed string) (string, error) {
+	rest := strings.TrimPrefix(hashed, hashPrefix)
+	alg, _, ok := strings.Cut(rest, "$")
+	if rest == hashed || !ok {
+		return "", newError(CodeMalformedHash, "uriuniq: malformed token hash")
+	}
+	return alg, nil
+}
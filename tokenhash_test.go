@@ -0,0 +1,74 @@
+package uriuniq
+
+import (
+	"crypto/md5"
+	"strings"
+	"testing"
+)
+
+// TestHashToken checks hashing and verification with every algorithm.
+func TestHashToken(t *testing.T) {
+	pepper := []byte("pepper")
+	for _, alg := range []string{HashSHA256, HashSHA512} {
+		t.Run(alg, func(t *testing.T) {
+			hashed, err := HashToken("token", pepper, alg)
+			if err != nil {
+				t.Fatalf("HashToken failed: %s", err)
+			}
+			if !strings.HasPrefix(hashed, "$uuh2$"+alg+"$") {
+				t.Errorf("Unexpected digest %s", hashed)
+			}
+			if got, err := HashedTokenAlg(hashed); err != nil || got != alg {
+				t.Errorf("HashedTokenAlg = %q, %v", got, err)
+			}
+			for _, tc := range []struct {
+				token    string
+				pepper   string
+				expected bool
+			}{
+				{"token", "pepper", true},
+				{"tokem", "pepper", false},
+				{"token", "salt", false},
+			} {
+				if ok, err := VerifyHashedToken(tc.token, []byte(tc.pepper), hashed); err != nil || ok != tc.expected {
+					t.Errorf("VerifyHashedToken(%q, %q) = %v, %v", tc.token, tc.pepper, ok, err)
+				}
+			}
+		})
+	}
+
+	// HMAC-SHA256 of "token" under "pepper".
+	if hashed, _ := HashToken("token", pepper, HashSHA256); hashed != "$uuh2$sha256$0GkAck82uI1_Cy13AEZFskV4smKaXWZiAPpas_UJtF4" {
+		t.Errorf("Unexpected SHA-256 digest %s", hashed)
+	}
+
+	if _, err := HashToken("token", pepper, "md5"); err == nil {
+		t.Errorf("Expected error for unregistered algorithm")
+	}
+	if err := RegisterHashAlg("md5", md5.New); err != nil {
+		t.Fatalf("RegisterHashAlg failed: %s", err)
+	}
+	if _, err := HashToken("token", pepper, "md5"); err != nil {
+		t.Errorf("Registered algorithm failed: %s", err)
+	}
+	for _, hashed := range []string{"", "sha256$abc", "$uuh2$sha256", "$uuh2$sha3$abc"} {
+		if _, err := VerifyHashedToken("token", pepper, hashed); err == nil {
+			t.Errorf("Expected error for %q", hashed)
+		}
+	}
+}
+
+// TestFreezeHashAlgs checks that no algorithm can be added after freezing.
+func TestFreezeHashAlgs(t *testing.T) {
+	defer func() { hashAlgsFrozen = false }()
+	if err := RegisterHashAlg("bad$name", md5.New); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected invalid name error, got %v", err)
+	}
+	FreezeHashAlgs()
+	if err := RegisterHashAlg("md5-frozen", md5.New); err != ErrFrozen {
+		t.Errorf("Expected ErrFrozen, got %v", err)
+	}
+	if _, err := HashToken("token", nil, HashSHA256); err != nil {
+		t.Errorf("Frozen algorithms should still work: %s", err)
+	}
+}
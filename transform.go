@@ -0,0 +1,65 @@
+package uriuniq
+
+// Transform changes the case of generated strings, see Options.Transform.
+type Transform int
+
+const (
+	TransformNone Transform = iota
+	TransformLower
+	TransformUpper
+	TransformLowerStrict // Like TransformLower but fails if chars would merge
+	TransformUpperStrict // Like TransformUpper but fails if chars would merge
+)
+
+// ErrTransformMerge is returned by the strict transforms when the charset has
+// chars that only differ by case.
+var ErrTransformMerge = newError(CodeTransformMerge, "uriuniq: transform merges distinct chars")
+
+// applyTransform maps charset through t. The transform is applied to the
+// charset rather than to the output, and merged chars are deduplicated, so
+// the output stays uniform over the transformed charset instead of
+// doubling the odds of letters that had both cases. Duplicates already in
+// charset are kept as they are.
+func applyTransform(charset []byte, t Transform) ([]byte, error) {
+	var mapChar func(byte) byte
+	switch t {
+	case TransformLower, TransformLowerStrict:
+		mapChar = toLower
+	case TransformUpper, TransformUpperStrict:
+		mapChar = toUpper
+	default:
+		return charset, nil
+	}
+	strict := t == TransformLowerStrict || t == TransformUpperStrict
+
+	var seen [256]bool
+	var origin [256]byte // Char mapped to each output char
+	out := make([]byte, 0, len(charset))
+	for _, c := range charset {
+		m := mapChar(c)
+		if seen[m] && origin[m] != c {
+			if strict {
+				return nil, ErrTransformMerge
+			}
+			continue
+		}
+		seen[m] = true
+		origin[m] = c
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func toLower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+func toUpper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+	return c
+}
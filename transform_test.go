@@ -0,0 +1,86 @@
+package uriuniq
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestApplyTransform checks case mapping, deduplication and strict mode.
+func TestApplyTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		charset   string
+		transform Transform
+		expected  string
+		err       error
+	}{
+		{"None", "aB1", TransformNone, "aB1", nil},
+		{"Lower", "aB1", TransformLower, "ab1", nil},
+		{"Upper", "aB1", TransformUpper, "AB1", nil},
+		{"Lower Merges", "abAB", TransformLower, "ab", nil},
+		{"Upper Merges", "abAB", TransformUpper, "AB", nil},
+		{"Strict No Merge", "aB1", TransformLowerStrict, "ab1", nil},
+		{"Strict Merge", "abA", TransformLowerStrict, "", ErrTransformMerge},
+		{"Strict Upper Merge", "aAb", TransformUpperStrict, "", ErrTransformMerge},
+		{"Keeps Duplicates", "a-a-", TransformUpper, "A-A-", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := applyTransform([]byte(tc.charset), tc.transform)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("%s: expected error %v, got %v", tc.name, tc.err, err)
+			}
+			if err == nil && string(got) != tc.expected {
+				t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestTransformGenerate ensures Generate honours Transform.
+func TestTransformGenerate(t *testing.T) {
+	opts := NewOpts()
+	opts.Transform = TransformUpper
+	result, err := Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if err := checkCharset(result, Numeric+Uppercase); err != nil {
+		t.Errorf("Unexpected char: %s", err)
+	}
+
+	opts.Transform = TransformUpperStrict
+	if _, err := Generate(opts); !errors.Is(err, ErrTransformMerge) {
+		t.Errorf("Expected ErrTransformMerge, got %v", err)
+	}
+}
+
+// TestCaseInsensitiveSafe checks that strings keep a single case and that
+// entropy counts the folded charset.
+func TestCaseInsensitiveSafe(t *testing.T) {
+	opts := Options{Length: 40, CaseInsensitiveSafe: true}
+	charset, err := CharsetOf(opts)
+	if err != nil || len(charset) != 36 {
+		t.Fatalf("Expected 36 chars, got %q, %v", charset, err)
+	}
+	s, err := Generate(opts)
+	if err != nil || strings.ToLower(s) != s {
+		t.Errorf("Expected lowercase, got %q, %v", s, err)
+	}
+	if s, _ := Generate(Options{Length: 40, CaseInsensitiveSafe: true, Transform: TransformUpper}); strings.ToUpper(s) != s {
+		t.Errorf("Expected the Transform to pick the case, got %q", s)
+	}
+	if got, want := Entropy(opts), 40*math.Log2(36); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected %f bits, got %f", want, got)
+	}
+	g, err := NewGenerator(Options{MinEntropyBits: 128, CaseInsensitiveSafe: true})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	if id, _ := g.Next(); len(id) != 25 {
+		t.Errorf("Expected 25 chars for 128 bits, got %q", id)
+	}
+}
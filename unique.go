@@ -0,0 +1,184 @@
+package uriuniq
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// DefaultUniqueAttempts is the number of attempts of GenerateUnique.
+const DefaultUniqueAttempts = 10
+
+// ErrMaxRetriesExceeded is returned when every attempt to generate a unique
+// ID collided with a taken one. It usually means the namespace is close to
+// saturation and the Length should grow.
+var ErrMaxRetriesExceeded = newError(CodeRetriesExceeded, "uriuniq: no unique ID within the allowed attempts")
+
+// UniqueOutcome is the result of an attempt of UniqueRetry.
+type UniqueOutcome int
+
+const (
+	OutcomeUnique    UniqueOutcome = iota // The ID was free and recorded
+	OutcomeCollision                      // The ID was taken, another attempt follows
+	OutcomeExhausted                      // The ID was taken and no attempts are left
+	OutcomeError                          // The Generator or the checker failed
+)
+
+func (o UniqueOutcome) String() string {
+	switch o {
+	case OutcomeUnique:
+		return "unique"
+	case OutcomeCollision:
+		return "collision"
+	case OutcomeExhausted:
+		return "exhausted"
+	case OutcomeError:
+		return "error"
+	}
+	return fmt.Sprintf("UniqueOutcome(%d)", int(o))
+}
+
+// UniqueEvent reports an attempt of UniqueRetry. The last event of a call
+// has an outcome other than OutcomeCollision.
+type UniqueEvent struct {
+	Attempt int           // 1 for the first attempt
+	Latency time.Duration // Time spent in the UniquenessChecker
+	Backoff time.Duration // Wait before the next attempt, for OutcomeCollision
+	Outcome UniqueOutcome
+	Err     error // For OutcomeError and OutcomeExhausted
+}
+
+// UniqueRetry generates IDs until one is not taken in a UniquenessChecker,
+// waiting between attempts with exponential backoff and full jitter, so
+// services filling a short-code namespace do not hammer the checker
+// backend together. The zero value makes DefaultUniqueAttempts attempts
+// without waiting.
+type UniqueRetry struct {
+	// MaxAttempts is the number of IDs tried. Defaults to
+	// DefaultUniqueAttempts.
+	MaxAttempts int
+	// Backoff is the wait after the first collision; it doubles after each
+	// further one, up to MaxBackoff if set. Each wait is drawn uniformly
+	// from [0, backoff]. Zero does not wait.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// OnEvent, if set, is called after every attempt, for metrics and logs
+	// showing how close a namespace is to saturation.
+	OnEvent func(UniqueEvent)
+}
+
+// GenerateUnique creates an ID with g that checker has not seen and
+// records it, making at most maxAttempts attempts, or
+// DefaultUniqueAttempts if maxAttempts is zero. Use UniqueRetry for
+// backoff and telemetry.
+func GenerateUnique(g *Generator, checker UniquenessChecker, maxAttempts int) (string, error) {
+	return UniqueRetry{MaxAttempts: maxAttempts}.Generate(context.Background(), g, checker)
+}
+
+// GenerateUniqueFunc creates an ID with opts for which exists reports
+// false, making at most maxAttempts attempts, or DefaultUniqueAttempts if
+// maxAttempts is zero, and returns ErrMaxRetriesExceeded once all of them
+// collided. exists typically queries the store the ID goes to, such as a
+// database table; as the ID may still be taken between the query and the
+// insert, keep a UNIQUE constraint there and retry on its violation too.
+func GenerateUniqueFunc(ctx context.Context, opts Options, exists func(context.Context, string) (bool, error), maxAttempts int) (string, error) {
+	g, err := NewGenerator(opts)
+	if err != nil {
+		return "", err
+	}
+	return UniqueRetry{MaxAttempts: maxAttempts}.Generate(ctx, g, existsChecker{ctx, exists})
+}
+
+// existsChecker is a UniquenessChecker asking an exists func, leaving the
+// recording to the caller.
+type existsChecker struct {
+	ctx    context.Context
+	exists func(context.Context, string) (bool, error)
+}
+
+func (c existsChecker) Seen(id string) (bool, error) {
+	if err := c.ctx.Err(); err != nil {
+		return false, err
+	}
+	return c.exists(c.ctx, id)
+}
+
+func (c existsChecker) Record(string) error { return nil }
+
+// Generate creates an ID with g that checker has not seen and records it.
+// It returns ErrMaxRetriesExceeded once all attempts collided, the error
+// of g or checker, or ctx.Err() if ctx is done while waiting.
+func (r UniqueRetry) Generate(ctx context.Context, g *Generator, checker UniquenessChecker) (string, error) {
+	return r.GenerateFrom(ctx, g.Next, checker)
+}
+
+// GenerateFrom is like Generate but draws the IDs from next, such as the
+// Next method of a Pool.
+func (r UniqueRetry) GenerateFrom(ctx context.Context, next func() (string, error), checker UniquenessChecker) (string, error) {
+	attempts := r.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultUniqueAttempts
+	}
+	backoff := r.Backoff
+	for attempt := 1; ; attempt++ {
+		ev := UniqueEvent{Attempt: attempt}
+		id, outcome, err := r.try(next, checker, &ev)
+		if outcome == OutcomeCollision && attempt == attempts {
+			outcome, err = OutcomeExhausted, ErrMaxRetriesExceeded
+		}
+		var wait time.Duration
+		if outcome == OutcomeCollision && backoff > 0 {
+			wait = time.Duration(rand.Int63n(int64(backoff) + 1))
+			if backoff *= 2; r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+				backoff = r.MaxBackoff
+			}
+		}
+		ev.Outcome, ev.Err, ev.Backoff = outcome, err, wait
+		if r.OnEvent != nil {
+			r.OnEvent(ev)
+		}
+		if outcome != OutcomeCollision {
+			return id, err
+		}
+		if err := sleepCtx(ctx, wait); err != nil {
+			return "", err
+		}
+	}
+}
+
+// try makes one attempt, recording the checker latency in ev.
+func (r UniqueRetry) try(next func() (string, error), checker UniquenessChecker, ev *UniqueEvent) (string, UniqueOutcome, error) {
+	id, err := next()
+	if err != nil {
+		return "", OutcomeError, err
+	}
+	start := time.Now()
+	defer func() { ev.Latency = time.Since(start) }()
+	seen, err := checker.Seen(id)
+	if err != nil {
+		return "", OutcomeError, err
+	}
+	if seen {
+		return "", OutcomeCollision, nil
+	}
+	if err := checker.Record(id); err != nil {
+		return "", OutcomeError, err
+	}
+	return id, OutcomeUnique, nil
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,130 @@
+package uriuniq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// errorChecker is a UniquenessChecker failing every call.
+type errorChecker struct{ err error }
+
+func (c errorChecker) Seen(string) (bool, error) { return false, c.err }
+func (c errorChecker) Record(string) error       { return c.err }
+
+// digitChecker returns a MemoryChecker with all digits but free taken.
+func digitChecker(t *testing.T, free string) *MemoryChecker {
+	c := NewMemoryChecker()
+	for _, d := range Numeric {
+		if string(d) != free {
+			if err := c.Record(string(d)); err != nil {
+				t.Fatalf("Record failed: %s", err)
+			}
+		}
+	}
+	return c
+}
+
+// TestGenerateUnique checks finding the one free ID and exhausting the
+// attempts when none is free.
+func TestGenerateUnique(t *testing.T) {
+	g, err := NewGenerator(Options{Length: 1, CustomCharset: Numeric})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	checker := digitChecker(t, "7")
+	id, err := GenerateUnique(g, checker, 1000)
+	if err != nil || id != "7" {
+		t.Fatalf("Expected 7, got %q, %v", id, err)
+	}
+	if seen, _ := checker.Seen("7"); !seen {
+		t.Errorf("Expected the ID to be recorded")
+	}
+	if _, err := GenerateUnique(g, checker, 0); err != ErrMaxRetriesExceeded {
+		t.Errorf("Expected ErrMaxRetriesExceeded, got %v", err)
+	}
+	failure := errors.New("backend down")
+	if _, err := GenerateUnique(g, errorChecker{failure}, 0); err != failure {
+		t.Errorf("Expected checker error, got %v", err)
+	}
+}
+
+// TestGenerateUniqueFunc checks regenerating on IDs an exists func reports
+// as taken.
+func TestGenerateUniqueFunc(t *testing.T) {
+	opts := Options{Length: 1, CustomCharset: Numeric}
+	taken := func(_ context.Context, id string) (bool, error) { return id != "7", nil }
+	if id, err := GenerateUniqueFunc(context.Background(), opts, taken, 1000); err != nil || id != "7" {
+		t.Errorf("Expected 7, got %q, %v", id, err)
+	}
+	all := func(context.Context, string) (bool, error) { return true, nil }
+	if _, err := GenerateUniqueFunc(context.Background(), opts, all, 3); !errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Errorf("Expected ErrMaxRetriesExceeded, got %v", err)
+	}
+	failure := errors.New("database down")
+	failing := func(context.Context, string) (bool, error) { return false, failure }
+	if _, err := GenerateUniqueFunc(context.Background(), opts, failing, 0); err != failure {
+		t.Errorf("Expected exists error, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GenerateUniqueFunc(ctx, opts, taken, 0); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := GenerateUniqueFunc(context.Background(), Options{Length: 4, ExcludeNumeric: true, MinDigits: 1}, taken, 0); err == nil {
+		t.Errorf("Expected error for invalid Options")
+	}
+}
+
+// TestUniqueRetryEvents checks the events and backoff of the attempts.
+func TestUniqueRetryEvents(t *testing.T) {
+	g, err := NewGenerator(Options{Length: 1, CustomCharset: Numeric})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	var events []UniqueEvent
+	r := UniqueRetry{
+		MaxAttempts: 5,
+		Backoff:     time.Millisecond,
+		MaxBackoff:  2 * time.Millisecond,
+		OnEvent:     func(ev UniqueEvent) { events = append(events, ev) },
+	}
+	if _, err := r.Generate(context.Background(), g, digitChecker(t, "")); err != ErrMaxRetriesExceeded {
+		t.Fatalf("Expected ErrMaxRetriesExceeded, got %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("Expected 5 events, got %d", len(events))
+	}
+	for i, ev := range events {
+		want, limit := OutcomeCollision, 2*time.Millisecond
+		if i == 0 {
+			limit = time.Millisecond
+		}
+		if i == 4 {
+			want, limit = OutcomeExhausted, 0
+		}
+		if ev.Attempt != i+1 || ev.Outcome != want || ev.Backoff > limit || ev.Latency < 0 {
+			t.Errorf("Unexpected event %d: %+v", i, ev)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r = UniqueRetry{Backoff: time.Hour}
+	if _, err := r.Generate(ctx, g, digitChecker(t, "")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestUniqueRetryGenerateFrom checks drawing the IDs from a Pool.
+func TestUniqueRetryGenerateFrom(t *testing.T) {
+	p, err := NewPool(Options{Length: 1, CustomCharset: Numeric}, 2)
+	if err != nil {
+		t.Fatalf("NewPool failed: %s", err)
+	}
+	id, err := UniqueRetry{MaxAttempts: 1000}.GenerateFrom(context.Background(), p.Next, digitChecker(t, "3"))
+	if err != nil || id != "3" {
+		t.Errorf("Expected 3, got %q, %v", id, err)
+	}
+}
@@ -15,9 +15,11 @@
 package uriuniq
 
 import (
-	"crypto/rand"
-	"errors"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 )
 
 type Charset string
@@ -34,8 +36,196 @@ type Options struct {
 	ExcludeNumeric   bool
 	ExcludeLowercase bool
 	ExcludeUppercase bool
-	CustomCharset    Charset
-	MaxBadReads      int // Max allowed bad reads
+	ExcludeAmbiguous bool    // Drop AmbiguousChars, also from CustomCharset
+	CustomCharset    Charset // Runes of UTF-8 are drawn whole, with fewer options
+	CharsetName      string  // Charset registered with RegisterCharset, instead of CustomCharset
+	MaxBadReads      int     // Max reads in a row giving no chars
+
+	// Sensitive zeroes the internal entropy buffer and intermediate output
+	// after use. This is best effort: the returned string itself cannot be
+	// wiped, and the Go runtime or crypto/rand may keep copies of their own.
+	// Use GenerateSecret to also be able to wipe the result.
+	Sensitive bool
+
+	// LatencyQuantum, if set, rounds the latency of every call up to the next
+	// multiple of it, so the time spent on rejection sampling is not
+	// observable. A quantum larger than the worst-case generation time gives
+	// a constant latency.
+	LatencyQuantum time.Duration
+
+	// Transform changes the case of generated chars. Applying it here keeps
+	// the output uniform, unlike calling strings.ToLower on the result.
+	Transform Transform
+
+	// ProfileLabels tags generation and entropy reads with the pprof label
+	// uriuniq=generate or uriuniq=read and matching runtime/trace regions,
+	// so CPU profiles attribute time to ID generation. Labels set by the
+	// caller are hidden while the tagged code runs. Off by default to avoid
+	// the overhead.
+	ProfileLabels bool
+
+	// Sampler selects how entropy is turned into chars. Defaults to
+	// SamplerRejection.
+	Sampler Sampler
+
+	// EntropySource supplies the random bytes. Defaults to crypto/rand.
+	// Set it to read from a specific device, such as an HSM, or to inject
+	// failures in tests; its failed reads are retried as EntropyRetries
+	// says, then returned wrapped with ErrEntropyExhausted. A Generator
+	// reads from it through its own buffer and lock, so it need not be
+	// safe for concurrent use there.
+	EntropySource io.Reader
+
+	// EntropyRetries is how many times in a row a failed read of the
+	// EntropySource is retried before giving up; 0, the default, gives up
+	// on the first failure. EntropyRetryBackoff, if set, is waited before
+	// the first retry and doubled before each further one.
+	EntropyRetries      int
+	EntropyRetryBackoff time.Duration
+
+	// MinLength and MaxLength, if MaxLength is set, make every string have a
+	// length drawn uniformly from [MinLength, MaxLength] instead of Length,
+	// so the length reveals nothing. Entropy accounts for the variable length.
+	MinLength int
+	MaxLength int
+
+	// LengthUnit is the unit of Length, MinLength and MaxLength. Defaults
+	// to Characters.
+	LengthUnit LengthUnit
+
+	// InstanceLabel, if set, replaces the first FingerprintChars chars of
+	// every string with a fingerprint of the label, such as a host or pod
+	// name, so the instance that minted an ID can be traced with
+	// ExtractFingerprint. Each fingerprint char costs the entropy of a
+	// random char, and two instances share a 1-char fingerprint with a
+	// chance of 1 in the charset size.
+	InstanceLabel    string
+	FingerprintChars int // 1 or 2, defaults to 1
+
+	// OnKeyspaceAlert, if set, is called once by a Generator when the
+	// number of strings it issued reaches KeyspaceAlertFraction of the
+	// keyspace of opts, so short codes can be lengthened before collisions
+	// become likely.
+	KeyspaceAlertFraction float64
+	OnKeyspaceAlert       func(issued uint64, keyspace float64)
+
+	// EntropyBufferSize is the size in bytes of the read-ahead entropy
+	// buffer of a Generator. Zero sizes it to the expected demand of about
+	// 32 calls, a negative value disables buffering.
+	EntropyBufferSize int
+
+	// Escape selects how chars of the charset that are not URI unreserved
+	// chars are emitted. With EscapePercent, the returned strings are
+	// longer than Length and must be unescaped before validation.
+	Escape EscapeMode
+
+	// MinEntropyBits, if set, raises Length, or MinLength and MaxLength,
+	// to the ceil(MinEntropyBits / BitsPerChar(charset)) chars needed for
+	// that much entropy, plus any fingerprint chars, so a smaller charset
+	// cannot silently weaken the strings. Length may then be left zero.
+	MinEntropyBits int
+
+	// RequireEntropyBits, if set, rejects Options whose shortest strings
+	// carry fewer bits of entropy with ErrInsufficientEntropy, instead of
+	// raising Length as MinEntropyBits does. It guards against
+	// configurations too weak for their use, such as 6 digits, about 20
+	// bits, for session tokens.
+	RequireEntropyBits int
+
+	// Prefix, if set, is put before every string with PrefixSeparator in
+	// between, as in "cus_h8aK3...", to type IDs the way Stripe does. The
+	// random part keeps the full Length and entropy. The separator defaults
+	// to DefaultPrefixSeparator; neither may contain chars of the charset,
+	// so SplitPrefix can take the ID apart.
+	Prefix          string
+	PrefixSeparator string
+
+	// CheckChar appends a Luhn mod N check char over the random part, drawn
+	// from the charset, so Verify can reject mistyped or truncated IDs
+	// before a database lookup. It is not counted in Length and carries no
+	// entropy. The charset size N must be even, as Luhn mod N misses some
+	// single-char errors otherwise.
+	CheckChar bool
+
+	// Blocklist, if set, makes strings containing any of its words, such as
+	// those of DefaultBlocklist, be regenerated transparently, at most
+	// BlockRetries times, defaulting to DefaultBlockRetries. Matching
+	// ignores case and reads 0, 1, 3, 4, 5 and 7 as o, i, e, a, s and t.
+	// Each retry draws fresh entropy, so the strings stay uniform over the
+	// unblocked ones.
+	Blocklist    Wordlist
+	BlockRetries int
+
+	// Strict returns ErrInvalidLength for a Length that is not positive,
+	// ErrUnsafeCharset for a CustomCharset that is not URI-safe, unless
+	// escaped with EscapePercent, and an error with CodeDuplicateChar for
+	// one repeating chars, instead of logging a warning and falling back to
+	// DefaultLength or using the charset anyway.
+	Strict bool
+
+	// MinDigits, MinLowercase and MinUppercase, if set, make every string
+	// contain at least that many digits, lowercase and uppercase chars, as
+	// password composition rules require. The required chars are drawn
+	// from their class, the others from the whole charset, and all are
+	// shuffled, so the required chars land at uniform positions. This is
+	// not a uniform draw among the strings meeting the minimums: strings
+	// with more chars of a required class than its minimum come up more
+	// often than under such a draw. The strings lose a little entropy, as
+	// those without the required chars are never generated and the
+	// required chars carry only the entropy of their class.
+	MinDigits    int
+	MinLowercase int
+	MinUppercase int
+
+	// GroupSize, if set, puts GroupSeparator, defaulting to
+	// DefaultGroupSeparator, after every GroupSize chars of the random part
+	// and check char, as in "4fh2-9dk1-zz30" for coupon and license codes.
+	// The separator is not counted in Length and carries no entropy; it
+	// may not contain chars of the charset. Validate expects it exactly
+	// where it is put.
+	GroupSize      int
+	GroupSeparator string
+
+	// NodeChars, if set, replaces the first NodeChars chars of every string
+	// with Node, a node or shard ID written as big-endian digits in the
+	// order of the charset, so strings of different nodes never collide
+	// and can be routed by NodeOf. Node must fit, as 2 Alphanumeric chars
+	// hold nodes up to 3843. NodeChars carry no entropy and cannot be
+	// combined with InstanceLabel or GenerateSortable.
+	NodeChars int
+	Node      uint16
+
+	// NoLeadingDigit draws the first char of every string from the
+	// non-digits of the charset, so IDs are valid identifiers in CSS, XML
+	// or JavaScript. The other chars are drawn as usual. Entropy accounts
+	// for the smaller first char.
+	NoLeadingDigit bool
+
+	// DNSLabel makes every string an RFC 1035 label, for Kubernetes names
+	// and subdomains: lowercase letters, digits and hyphens, starting with
+	// a letter and not ending with a hyphen, at most MaxDNSLabelLength
+	// chars. It sets the charset and implies NoLeadingDigit; the last char
+	// has less entropy too.
+	DNSLabel bool
+
+	// CaseInsensitiveSafe keeps every string in a single case, so no two
+	// strings differ only by case where they end up case-insensitive, as
+	// on some file systems and in email local parts. Without a Transform,
+	// it folds the charset to lowercase as TransformLower does, merging
+	// letters of both cases. Entropy and MinEntropyBits count the folded
+	// charset, so lengths grow to match.
+	CaseInsensitiveSafe bool
+
+	// Metrics, if set, is told about every string issued and the entropy
+	// consumed to draw it, for visibility into entropy consumption in
+	// production. Counters implements it for expvar and Prometheus.
+	Metrics Metrics
+
+	// Logf, if set, receives diagnostics such as the fallback to
+	// DefaultLength, formatted as by fmt.Printf, so they go through the
+	// application's logger. Without it they are dropped. SlogLogf adapts a
+	// *slog.Logger.
+	Logf func(format string, args ...any)
 }
 
 const (
@@ -52,46 +242,223 @@ func NewOpts() Options {
 	}
 }
 
-// Generate creates a random string using Options.
+// Generate creates a random string using Options. It is kept for
+// compatibility; new code should create a Generator once, which checks
+// Options a single time and buffers entropy across calls.
 func Generate(opts Options) (string, error) {
+	deprecated(DeprecatedGenerate)
+	return generateID(opts)
+}
+
+// generateID is Generate without reporting the deprecated call, for the
+// other APIs of this package built on it.
+func generateID(opts Options) (string, error) {
+	output, err := generate(opts)
+	if err != nil {
+		return "", err
+	}
+	result := escapeOutput(opts, output)
+	if opts.Sensitive {
+		wipe(output)
+	}
+	return result, nil
+}
+
+// generate applies defaults to opts and creates the random chars.
+func generate(opts Options) ([]byte, error) {
+	if opts.LatencyQuantum > 0 {
+		defer padLatency(time.Now(), opts.LatencyQuantum)
+	}
+	if isRuneCharset(opts.CustomCharset) {
+		return generateRunes(opts)
+	}
+	opts, charset, err := prepare(opts)
+	if err != nil {
+		return nil, err
+	}
+	var output []byte
+	block := newBlocklist(opts.Blocklist)
+	profiled(opts.ProfileLabels, "generate", func() {
+		output, err = drawUnblocked(opts, block, func() ([]byte, error) {
+			var chars []byte
+			var err error
+			if opts.Length, err = drawLength(opts); err == nil {
+				chars, err = randBytes(opts, charset)
+			}
+			if err == nil {
+				stampFingerprint(opts, charset, chars)
+				stampNode(opts, charset, chars)
+			}
+			if err == nil && (opts.NoLeadingDigit || opts.DNSLabel) {
+				err = drawEdges(opts, charset, chars)
+			}
+			if err == nil && composed(opts) {
+				err = compose(opts, charset, chars)
+			}
+			return chars, err
+		})
+		if err == nil {
+			output = appendCheckChar(opts, charset, output)
+			totalIssued.Add(1)
+			countGenerated(opts)
+		}
+	})
+	return output, err
+}
+
+// prepare applies defaults to opts and builds its charset. The returned
+// opts count lengths in Characters.
+func prepare(opts Options) (Options, []byte, error) {
+	if opts.CharsetName != "" {
+		if opts.CustomCharset != "" {
+			return opts, nil, newError(CodeInvalidArgument, "uriuniq: both CharsetName and CustomCharset set")
+		}
+		cs, ok := LookupCharset(opts.CharsetName)
+		if !ok {
+			return opts, nil, errorf(CodeInvalidArgument, "uriuniq: unknown charset %q", opts.CharsetName)
+		}
+		opts.CustomCharset, opts.CharsetName = cs, ""
+	}
+	if isRuneCharset(opts.CustomCharset) {
+		return opts, nil, newError(CodeInvalidArgument, "uriuniq: multi-byte charsets need Generate, a Generator or Validate")
+	}
+	opts, err := checkDNSLabel(opts)
+	if err != nil {
+		return opts, nil, err
+	}
+	if opts.Strict && opts.CustomCharset != "" && opts.Escape != EscapePercent {
+		if err := checkCharset(string(opts.CustomCharset), uriSafe); err != nil {
+			return opts, nil, fmt.Errorf("%w: %v", ErrUnsafeCharset, err)
+		}
+	}
+	transform := opts.Transform
+	if opts.CaseInsensitiveSafe && transform == TransformNone {
+		transform = TransformLower
+	}
+	charset, err := applyTransform(getCharset(opts), transform)
+	if err != nil {
+		return opts, nil, err
+	}
+	if len(charset) == 0 {
+		return opts, nil, ErrEmptyCharset
+	}
+	if err := checkDuplicates(opts, charset); err != nil {
+		return opts, nil, err
+	}
+	if opts.CheckChar {
+		if err := checkLuhnCharset(charset); err != nil {
+			return opts, nil, err
+		}
+	}
+	if err := checkEscape(opts, charset); err != nil {
+		return opts, nil, err
+	}
+	if opts, err = checkPrefix(opts, charset); err != nil {
+		return opts, nil, err
+	}
+	if opts, err = checkGroups(opts, charset); err != nil {
+		return opts, nil, err
+	}
+
+	if opts.MaxLength > 0 {
+		if opts.MinLength < 1 || opts.MinLength > opts.MaxLength {
+			return opts, nil, newError(CodeInvalidArgument, "uriuniq: invalid length range")
+		}
+		opts.Length = opts.MaxLength
+	}
+	if opts.Length <= 0 && opts.MinEntropyBits > 0 && opts.MaxLength == 0 {
+		opts.Length = 1 // Raised to the entropy below
+	}
+	if opts.Length <= 0 && opts.Strict {
+		return opts, nil, fmt.Errorf("%w %d", ErrInvalidLength, opts.Length)
+	}
 	if opts.Length <= 0 {
-		fmt.Printf("Invalid length %d provided, using default length %d\n", opts.Length, DefaultLength)
+		deprecated(DeprecatedDefaultLength)
+		logf(opts, "uriuniq: invalid length %d, using default length %d", opts.Length, DefaultLength)
 		opts.Length = DefaultLength
 	}
+	if opts.LengthUnit != Characters {
+		if opts, err = toCharacters(opts, charset); err != nil {
+			return opts, nil, err
+		}
+	}
 	if opts.MaxBadReads <= 0 {
 		opts.MaxBadReads = DefaultMaxBadReads
 	}
+	if opts.MinEntropyBits != 0 {
+		if opts, err = raiseToEntropy(opts, charset); err != nil {
+			return opts, nil, err
+		}
+	}
+	if opts.InstanceLabel != "" {
+		if opts.FingerprintChars == 0 {
+			opts.FingerprintChars = 1
+		}
+		if min, _ := lengthRange(opts); opts.FingerprintChars > 2 || opts.FingerprintChars < 0 || min <= opts.FingerprintChars {
+			return opts, nil, newError(CodeInvalidLength, "uriuniq: invalid fingerprint length")
+		}
+	}
+	if err := checkNode(opts, charset); err != nil {
+		return opts, nil, err
+	}
+	if err := checkLeading(opts, charset); err != nil {
+		return opts, nil, err
+	}
+	if err := checkDNSLength(opts); err != nil {
+		return opts, nil, err
+	}
+	if composed(opts) {
+		if err := checkComposition(opts, charset); err != nil {
+			return opts, nil, err
+		}
+	}
+	if err := checkEntropyFloor(opts, shortestEntropy(opts, charset)); err != nil {
+		return opts, nil, err
+	}
+	return opts, charset, nil
+}
 
-	charset := getCharset(opts)
-	if len(charset) == 0 {
-		return "", errors.New("uriuniq: no valid chars")
+// checkDuplicates returns an error with CodeDuplicateChar in Strict mode
+// if charset repeats a char, which makes it more likely, and logs a
+// warning otherwise.
+func checkDuplicates(opts Options, charset []byte) error {
+	var seen [256]bool
+	for _, c := range charset {
+		if seen[c] {
+			if opts.Strict {
+				return errorf(CodeDuplicateChar, "uriuniq: duplicate char %q in charset", c)
+			}
+			logf(opts, "uriuniq: charset repeats %q, which biases the output", c)
+			return nil
+		}
+		seen[c] = true
 	}
+	return nil
+}
 
-	return randString(opts.Length, opts.MaxBadReads, charset)
+// logf passes a diagnostic to opts.Logf, if set.
+func logf(opts Options, format string, args ...any) {
+	if opts.Logf != nil {
+		opts.Logf(format, args...)
+	}
 }
 
+// uriSafe lists the unreserved and sub-delim chars allowed unescaped in URIs.
+const uriSafe Charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_.~!*'()"
+
 // isURISafe checks if all chars in a string are URI-safe.
 func isURISafe(s string) bool {
-	safeChars := "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_.~!*'()"
-	safeSet := make(map[rune]bool, len(safeChars))
-	for _, c := range safeChars {
-		safeSet[c] = true
-	}
-
-	for _, c := range s {
-		if !safeSet[c] {
-			return false
-		}
-	}
-	return true
+	return checkCharset(s, uriSafe) == nil
 }
 
 // getCharset picks the charset based on Options.
 func getCharset(opts Options) []byte {
 	var charset []byte
-	if opts.CustomCharset != "" {
+	if opts.DNSLabel {
+		charset = []byte(dnsLabelCharset)
+	} else if opts.CustomCharset != "" {
 		if !isURISafe(string(opts.CustomCharset)) {
-			fmt.Printf("Warning: CustomCharset '%s' contains characters that are not URI-safe", opts.CustomCharset)
+			logf(opts, "uriuniq: CustomCharset %q contains chars that are not URI-safe", opts.CustomCharset)
 		}
 		charset = []byte(opts.CustomCharset)
 	} else {
@@ -108,6 +475,15 @@ func getCharset(opts Options) []byte {
 			charset = append(charset, Alphanumeric...)
 		}
 	}
+	if opts.ExcludeAmbiguous {
+		kept := charset[:0:0]
+		for _, c := range charset {
+			if strings.IndexByte(string(AmbiguousChars), c) < 0 {
+				kept = append(kept, c)
+			}
+		}
+		charset = kept
+	}
 	return charset
 }
 
@@ -115,38 +491,178 @@ func getCharset(opts Options) []byte {
 //
 //	allow a maximum of 256 characters
 func randString(length, maxBadReads int, charset []byte) (string, error) {
-	if length == 0 {
-		return "", nil
+	output, err := randBytes(Options{Length: length, MaxBadReads: maxBadReads}, charset)
+	if err != nil {
+		return "", err
 	}
+	return string(output), nil
+}
+
+// randBytes generates opts.Length random chars from charset, reading from
+// opts.EntropySource. The entropy buffer is sized by readSize to the
+// expected need, taken from a pool and zeroed before returning.
+func randBytes(opts Options, charset []byte) ([]byte, error) {
+	if opts.Length == 0 {
+		return nil, nil
+	}
+	size := 1
+	if len(charset) >= 2 && len(charset) <= 256 {
+		size = readSize(opts.Length, len(charset))
+	}
+	pooled := getBuffer(size)
+	defer putBuffer(pooled)
+	buffer := *pooled
+	output := make([]byte, opts.Length)
+	if _, err := randFill(opts, charset, output, buffer); err != nil {
+		if opts.Sensitive {
+			wipe(output)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// randFill fills dst with random chars from charset, reading entropy into
+// buffer. At most opts.MaxBadReads reads in a row may give no chars. It
+// returns the bytes of the last read that were not needed, so callers
+// that buffer entropy can serve them again.
+func randFill(opts Options, charset, dst, buffer []byte) (unused []byte, err error) {
+	return randFillTable(opts, charset, nil, dst, buffer)
+}
+
+// randFillTable is randFill mapping entropy with table, if set, as built
+// by newCharTable for charset. Without one, a table is built for long dst.
+func randFillTable(opts Options, charset []byte, table *charTable, dst, buffer []byte) (unused []byte, err error) {
+	if opts.Sampler == SamplerArithmetic {
+		return nil, arithmeticFill(opts, charset, dst)
+	}
+	if bits := charsetBits(len(charset)); opts.Sampler == SamplerBits && bits > 0 {
+		return nil, bitsFill(opts, charset, dst, buffer, bits)
+	}
+	src := newEntropyReader(opts)
 
 	charsetLen := len(charset)
 	if charsetLen < 2 || charsetLen > 256 {
-		return "", errors.New("uriuniq: charset size 2-256")
+		return nil, newError(CodeCharsetSize, "uriuniq: charset size 2-256")
 	}
 
-	maxByte := byte(255 - (256 % charsetLen))
-	buffer := make([]byte, MaxBuffLength)
-	var output []byte
-	badReads := 0
+	maxByte := rejectThreshold(charsetLen)
+	if table == nil && len(dst) >= chunkedMinLength {
+		table = newCharTable(charset, maxByte)
+	}
+	filled := 0
+	reads, badReads := 0, 0
+	consumedTotal := 0
+	if opts.Metrics != nil {
+		defer func() {
+			if err == nil {
+				opts.Metrics.Sampled(consumedTotal, consumedTotal-len(dst), reads-1)
+			}
+		}()
+	}
 
-	for len(output) < length {
-		readBytes, err := rand.Read(buffer)
+	for filled < len(dst) {
+		// Read only what the chars still missing need, so a read after
+		// rejections does not refill the whole buffer.
+		want := len(buffer)
+		if n := readSize(len(dst)-filled, charsetLen); n < want {
+			want = n
+		}
+		var readBytes int
+		profiled(opts.ProfileLabels, "read", func() {
+			readBytes, err = src.Read(buffer[:want])
+		})
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		for i := 0; i < readBytes && len(output) < length; i++ {
-			byteVal := buffer[i]
-			if byteVal <= maxByte {
-				output = append(output, charset[int(byteVal)%charsetLen])
+		reads++
+		before := filled
+		var consumed int
+		if table != nil {
+			var n int
+			n, consumed = table.mapChunked(dst[filled:], buffer[:readBytes])
+			filled += n
+		} else {
+			for ; consumed < readBytes && filled < len(dst); consumed++ {
+				byteVal := buffer[consumed]
+				if byteVal <= maxByte {
+					dst[filled] = charset[int(byteVal)%charsetLen]
+					filled++
+				}
 			}
 		}
+		unused = buffer[consumed:readBytes]
+		consumedTotal += consumed
 
-		badReads++
-		if badReads > maxBadReads {
-			return "", errors.New("uriuniq: too many bad reads")
+		if filled > before {
+			badReads = 0
+		} else if badReads++; badReads >= opts.MaxBadReads {
+			return nil, errBadReads(opts.MaxBadReads)
 		}
 	}
 
-	return string(output), nil
+	return unused, nil
+}
+
+// rejectThreshold returns the largest entropy byte accepted for a charset
+// of n chars; larger bytes would bias the output.
+func rejectThreshold(n int) byte {
+	return byte(255 - (256 % n))
+}
+
+// lengthRange returns the shortest and longest string generated with opts,
+// which must already be prepared.
+func lengthRange(opts Options) (min, max int) {
+	if opts.MaxLength > 0 {
+		return opts.MinLength, opts.MaxLength
+	}
+	return opts.Length, opts.Length
+}
+
+// idLengthRange returns the shortest and longest ID generated with
+// prepared opts, including the prefix, check char and group separators.
+func idLengthRange(opts Options) (min, max int) {
+	min, max = lengthRange(opts)
+	n := checkChars(opts)
+	prefix := len(idPrefix(opts))
+	return prefix + groupedLength(opts, min+n), prefix + groupedLength(opts, max+n)
+}
+
+// drawLength returns the length of the next string generated with opts,
+// which must already be prepared. It reads entropy only if opts has a
+// length range.
+func drawLength(opts Options) (int, error) {
+	min, max := lengthRange(opts)
+	if min == max {
+		return min, nil
+	}
+	src := newEntropyReader(opts)
+	n := uint32(max - min + 1)
+	limit := ^uint32(0) - ^uint32(0)%n
+	var b [4]byte
+	for reads := 0; reads < opts.MaxBadReads; reads++ {
+		if err := src.ReadFull(b[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.BigEndian.Uint32(b[:]); v < limit {
+			return min + int(v%n), nil
+		}
+	}
+	return 0, errBadReads(opts.MaxBadReads)
+}
+
+// padLatency sleeps until the time since start is a multiple of quantum.
+func padLatency(start time.Time, quantum time.Duration) {
+	elapsed := time.Since(start)
+	if rem := elapsed % quantum; rem != 0 || elapsed == 0 {
+		time.Sleep(quantum - rem)
+	}
+}
+
+// wipe zeroes b.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
 }
@@ -1,8 +1,13 @@
 package uriuniq
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestDefaultOptions validates string generation with default options.
@@ -103,6 +108,41 @@ func TestOptionsLength(t *testing.T) {
 	}
 }
 
+// TestLengthRange checks that lengths are drawn from the whole range.
+func TestLengthRange(t *testing.T) {
+	opts := NewOpts()
+	opts.MinLength, opts.MaxLength = 4, 7
+	seen := make(map[int]bool)
+	for i := 0; i < 200; i++ {
+		result, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate failed: %s", err)
+		}
+		if len(result) < 4 || len(result) > 7 {
+			t.Fatalf("Length %d outside range", len(result))
+		}
+		seen[len(result)] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("Expected all 4 lengths, got %v", seen)
+	}
+
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	if result, err := g.Next(); err != nil || len(result) < 4 || len(result) > 7 {
+		t.Errorf("Unexpected Next result %q, %v", result, err)
+	}
+
+	for _, r := range [][2]int{{0, 5}, {6, 5}} {
+		opts.MinLength, opts.MaxLength = r[0], r[1]
+		if _, err := Generate(opts); err == nil {
+			t.Errorf("Expected error for range %v", r)
+		}
+	}
+}
+
 // TestCharsetURISafe validates custom charset URI-safety.
 func TestCharsetURISafe(t *testing.T) {
 	tests := []struct {
@@ -126,6 +166,22 @@ func TestCharsetURISafe(t *testing.T) {
 	}
 }
 
+// TestLogf checks that diagnostics go to Options.Logf.
+func TestLogf(t *testing.T) {
+	var logged []string
+	logf := func(format string, args ...any) { logged = append(logged, fmt.Sprintf(format, args...)) }
+	if _, err := Generate(Options{CustomCharset: "ab<", Logf: logf}); err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	want := []string{
+		`uriuniq: CustomCharset "ab<" contains chars that are not URI-safe`,
+		"uriuniq: invalid length 0, using default length 16",
+	}
+	if strings.Join(logged, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected %q, got %q", want, logged)
+	}
+}
+
 // TestCharsetLength checks for appropriate error handling of charset length.
 func TestCharsetLength(t *testing.T) {
 	tests := []struct {
@@ -149,6 +205,19 @@ func TestCharsetLength(t *testing.T) {
 	}
 }
 
+// TestLatencyQuantum checks that generation latency is padded.
+func TestLatencyQuantum(t *testing.T) {
+	opts := NewOpts()
+	opts.LatencyQuantum = 20 * time.Millisecond
+	start := time.Now()
+	if _, err := Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < opts.LatencyQuantum {
+		t.Errorf("Expected at least %s, took %s", opts.LatencyQuantum, elapsed)
+	}
+}
+
 // BenchmarkGenerateDefault benchmarks the default generation.
 func BenchmarkGenerateDefault(b *testing.B) {
 	opts := NewOpts()
@@ -196,3 +265,163 @@ func BenchmarkCheckDuplication(b *testing.B) {
 		idSet[result] = true
 	}
 }
+
+// TestSensitive ensures Sensitive mode still produces valid output.
+func TestSensitive(t *testing.T) {
+	opts := NewOpts()
+	opts.Sensitive = true
+	result, err := Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if len(result) != DefaultLength || strings.Count(result, "\x00") != 0 {
+		t.Errorf("Unexpected result %q", result)
+	}
+}
+
+// sizeRecorder serves src and records the size of every read.
+type sizeRecorder struct {
+	src   io.Reader
+	sizes []int
+}
+
+func (r *sizeRecorder) Read(p []byte) (int, error) {
+	r.sizes = append(r.sizes, len(p))
+	return io.ReadFull(r.src, p)
+}
+
+// TestReadSize checks that Generate reads about the entropy a string
+// needs instead of a fixed buffer, and rereads only for the missing chars.
+func TestReadSize(t *testing.T) {
+	// 10 accepted bytes and 18 rejected ones leave 6 chars missing.
+	first := append(bytes.Repeat([]byte{1}, 10), bytes.Repeat([]byte{255}, 18)...)
+	src := &sizeRecorder{src: io.MultiReader(bytes.NewReader(first), bytes.NewReader(bytes.Repeat([]byte{2}, 64)))}
+	result, err := Generate(Options{Length: 16, EntropySource: src})
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if len(result) != 16 {
+		t.Errorf("Expected length 16, got %d", len(result))
+	}
+	want := []int{readSize(16, 62), readSize(6, 62)}
+	if len(src.sizes) != 2 || src.sizes[0] != want[0] || src.sizes[1] != want[1] {
+		t.Errorf("Expected read sizes %v, got %v", want, src.sizes)
+	}
+}
+
+// generatePaths lists the APIs creating IDs other than Generate and a
+// Generator, each returning IDs Validate must accept with the Options.
+var generatePaths = []struct {
+	name     string
+	generate func(opts Options) ([]string, error)
+}{
+	{"GenerateArena", func(opts Options) ([]string, error) {
+		arena, err := GenerateArena(opts, 20)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, arena.Len())
+		for i := range ids {
+			ids[i] = arena.At(i)
+		}
+		return ids, nil
+	}},
+	{"GeneratePadded", func(opts Options) ([]string, error) {
+		field, err := GeneratePadded(80, ' ', opts)
+		return []string{strings.TrimRight(field, " ")}, err
+	}},
+	{"GenerateDual", func(opts Options) ([]string, error) {
+		// The display form carries the grouping of GroupSize.
+		format := DisplayFormat{GroupSize: opts.GroupSize}
+		opts.GroupSize = 0
+		id, err := GenerateDual(opts, format)
+		return []string{id.Display}, err
+	}},
+	{"Versions", func(opts Options) ([]string, error) {
+		v := NewVersions()
+		if err := v.Register('v', opts); err != nil {
+			return nil, err
+		}
+		id, err := v.Generate()
+		if err != nil {
+			return nil, err
+		}
+		// As Validate, Versions.Validate takes IDs unescaped.
+		raw, err := url.PathUnescape(id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := v.Validate(raw); err != nil {
+			return nil, err
+		}
+		return []string{id[1:]}, nil
+	}},
+}
+
+// TestGeneratePaths checks that every API creating IDs honors the Options
+// shaping them, so Validate accepts what it creates.
+func TestGeneratePaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		check func(id string) bool // Beyond Validate, nil for none
+	}{
+		{"DNSLabel", Options{Length: 12, DNSLabel: true}, nil},
+		{"CheckChar", Options{Length: 8, CheckChar: true}, nil},
+		{"Prefix", Options{Length: 8, Prefix: "cus"}, nil},
+		{"Grouping", Options{Length: 10, Prefix: "cus", GroupSize: 4, CheckChar: true}, nil},
+		{"NoLeadingDigit", Options{Length: 8, CustomCharset: "ab0123456789", NoLeadingDigit: true}, nil},
+		{"MinDigits", Options{Length: 8, MinDigits: 3, MinUppercase: 2}, nil},
+		{"Blocklist", Options{Length: 2, CustomCharset: "abcdefgh", Blocklist: &StaticWordlist{List: []string{"a"}}},
+			func(id string) bool { return !strings.Contains(id, "a") }},
+		{"NodeChars", Options{Length: 8, Prefix: "cus", NodeChars: 2, Node: 77}, func(id string) bool {
+			node, err := NodeOf(id, Options{Length: 8, Prefix: "cus", NodeChars: 2})
+			return err == nil && node == 77
+		}},
+	}
+	for _, path := range generatePaths {
+		for _, tc := range tests {
+			t.Run(path.name+"/"+tc.name, func(t *testing.T) {
+				for i := 0; i < 50; i++ {
+					ids, err := path.generate(tc.opts)
+					if err != nil {
+						t.Fatalf("%s failed: %s", path.name, err)
+					}
+					for _, id := range ids {
+						if err := Validate(id, tc.opts); err != nil {
+							t.Fatalf("Validate(%q) failed: %s", id, err)
+						}
+						if tc.check != nil && !tc.check(id) {
+							t.Fatalf("Unexpected ID %q", id)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestGeneratePathsEscape checks that the APIs creating IDs escape them as
+// Generate does, or reject escaping they cannot hold.
+func TestGeneratePathsEscape(t *testing.T) {
+	opts := Options{Length: 12, CustomCharset: "ab!*", Escape: EscapePercent}
+	for _, path := range generatePaths {
+		ids, err := path.generate(opts)
+		switch path.name {
+		case "GenerateArena", "GenerateDual":
+			if err == nil {
+				t.Errorf("Expected %s to reject percent-encoding", path.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s failed: %s", path.name, err)
+		}
+		for _, id := range ids {
+			raw, err := url.PathUnescape(id)
+			if err != nil || raw == id || Validate(raw, opts) != nil {
+				t.Errorf("%s: expected an escaped ID, got %q", path.name, id)
+			}
+		}
+	}
+}
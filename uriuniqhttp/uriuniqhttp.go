@@ -0,0 +1,99 @@
+// Package uriuniqhttp provides net/http middleware giving every request an
+// ID. The ID is set on the response header and carried by the request
+// context through package requestid, so a requestid.Transport propagates
+// it to outgoing calls.
+//
+// Example:
+//
+//	mw := &uriuniqhttp.RequestID{TrustInbound: true}
+//	http.Handle("/", mw.Handler(handler))
+//	// In the handler:
+//	id, _ := uriuniqhttp.FromContext(r.Context())
+package uriuniqhttp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/laofun/uriuniq"
+	"github.com/laofun/uriuniq/requestid"
+)
+
+// MaxInboundLength is the longest inbound ID kept with TrustInbound.
+const MaxInboundLength = 128
+
+// RequestID is middleware that sets Header on every request and response
+// to a new ID. With TrustInbound, an ID sent by the client in Header is
+// kept instead if it has 1 to MaxInboundLength unreserved URI chars, so a
+// request can be followed from an upstream proxy; other inbound values
+// are replaced, as they may be crafted to forge log lines.
+type RequestID struct {
+	Generator    *uriuniq.Generator // Defaults to one with uriuniq.NewOpts
+	Header       string             // Defaults to requestid.DefaultHeader
+	TrustInbound bool
+	// OnPanic, if set, is called with a panic recovered while creating an
+	// ID, to log or report its stack. The request gets a 500 either way.
+	OnPanic func(*uriuniq.PanicError)
+
+	once sync.Once
+	gen  *uriuniq.Generator
+	err  error
+}
+
+// Handler wraps next. If no ID can be generated, it responds with 500.
+func (m *RequestID) Handler(next http.Handler) http.Handler {
+	header := m.Header
+	if header == "" {
+		header = requestid.DefaultHeader
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(header)
+		if !m.TrustInbound || !validInbound(id) {
+			err := uriuniq.Recover(func() error {
+				var err error
+				id, err = m.next()
+				return err
+			}, m.OnPanic)
+			if err != nil {
+				http.Error(w, "cannot create request ID", http.StatusInternalServerError)
+				return
+			}
+			r.Header.Set(header, id)
+		}
+		w.Header().Set(header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+	})
+}
+
+// next creates an ID with the Generator of m.
+func (m *RequestID) next() (string, error) {
+	m.once.Do(func() {
+		if m.gen = m.Generator; m.gen == nil {
+			m.gen, m.err = uriuniq.NewGenerator(uriuniq.NewOpts())
+		}
+	})
+	if m.err != nil {
+		return "", m.err
+	}
+	return m.gen.Next()
+}
+
+// validInbound reports whether an inbound ID may be kept.
+func validInbound(id string) bool {
+	if id == "" || len(id) > MaxInboundLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~') {
+			return false
+		}
+	}
+	return true
+}
+
+// FromContext returns the request ID carried by ctx, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	return requestid.FromContext(ctx)
+}
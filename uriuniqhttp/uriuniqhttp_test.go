@@ -0,0 +1,96 @@
+package uriuniqhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestRequestID checks the IDs set on the request context and response,
+// with and without inbound IDs.
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name    string
+		trust   bool
+		inbound string
+		kept    bool
+	}{
+		{"New", false, "", false},
+		{"Untrusted", false, "upstream-1", false},
+		{"Trusted", true, "upstream-1", true},
+		{"Trusted Empty", true, "", false},
+		{"Log Injection", true, "x\nlevel=error", false},
+		{"Too Long", true, strings.Repeat("a", MaxInboundLength+1), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mw := &RequestID{TrustInbound: tc.trust}
+			var seen, header string
+			h := mw.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen, _ = FromContext(r.Context())
+				header = r.Header.Get("X-Request-ID")
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.inbound != "" {
+				req.Header["X-Request-Id"] = []string{tc.inbound}
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header().Get("X-Request-ID")
+			if got == "" || got != seen || got != header {
+				t.Fatalf("Expected one ID, got %q in the response, %q in the context and %q in the request", got, seen, header)
+			}
+			if tc.kept != (got == tc.inbound) {
+				t.Errorf("Expected inbound kept %t, got %q", tc.kept, got)
+			}
+			if !tc.kept && !uriuniq.Verify(got, uriuniq.NewOpts()) {
+				t.Errorf("Expected a generated ID, got %q", got)
+			}
+		})
+	}
+}
+
+// TestRequestIDOptions checks a custom header and Generator, and the
+// response when generation fails.
+func TestRequestIDOptions(t *testing.T) {
+	g, err := uriuniq.NewGenerator(uriuniq.Options{Length: 8, Prefix: "req"})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	mw := &RequestID{Generator: g, Header: "X-Trace-ID"}
+	rec := httptest.NewRecorder()
+	mw.Handler(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if id := rec.Header().Get("X-Trace-ID"); !strings.HasPrefix(id, "req_") || len(id) != 12 {
+		t.Errorf("Expected a req_ ID, got %q", id)
+	}
+
+	g, _ = uriuniq.NewGenerator(uriuniq.Options{Length: 8, EntropySource: failingReader{}, EntropyBufferSize: -1})
+	mw = &RequestID{Generator: g}
+	rec = httptest.NewRecorder()
+	mw.Handler(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rec.Code)
+	}
+
+	g, _ = uriuniq.NewGenerator(uriuniq.Options{Length: 8, EntropySource: panickingReader{}, EntropyBufferSize: -1})
+	var recovered *uriuniq.PanicError
+	mw = &RequestID{Generator: g, OnPanic: func(p *uriuniq.PanicError) { recovered = p }}
+	rec = httptest.NewRecorder()
+	mw.Handler(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError || recovered == nil {
+		t.Errorf("Expected status 500 and a recovered panic, got %d, %v", rec.Code, recovered)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("broken") }
+
+type panickingReader struct{}
+
+func (panickingReader) Read([]byte) (int, error) { panic("broken") }
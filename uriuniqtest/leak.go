@@ -0,0 +1,35 @@
+package uriuniqtest
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// leakTimeout is how long CheckGoroutines waits for goroutines to exit.
+const leakTimeout = time.Second
+
+// CheckGoroutines fails t if, once the test and its cleanups are done,
+// more goroutines run than when CheckGoroutines was called. Goroutines get
+// a grace period to exit. Call it first in tests that do not run in
+// parallel with others.
+func CheckGoroutines(t testing.TB) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(leakTimeout)
+		for {
+			after := runtime.NumGoroutine()
+			if after <= before {
+				return
+			}
+			if time.Now().After(deadline) {
+				buf := make([]byte, 64<<10)
+				buf = buf[:runtime.Stack(buf, true)]
+				t.Errorf("%d goroutines leaked:\n%s", after-before, buf)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package uriuniqtest
+
+import (
+	"testing"
+	"time"
+)
+
+// recordingTB records errors instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	cleanups []func()
+	failed   bool
+}
+
+func (r *recordingTB) Helper()               {}
+func (r *recordingTB) Cleanup(f func())      { r.cleanups = append(r.cleanups, f) }
+func (r *recordingTB) Errorf(string, ...any) { r.failed = true }
+func (r *recordingTB) runCleanups() {
+	for _, f := range r.cleanups {
+		f()
+	}
+}
+
+// TestCheckGoroutines checks that leaked goroutines are reported.
+func TestCheckGoroutines(t *testing.T) {
+	clean := &recordingTB{TB: t}
+	CheckGoroutines(clean)
+	done := make(chan struct{})
+	go func() { <-done }()
+	close(done)
+	clean.runCleanups()
+	if clean.failed {
+		t.Errorf("Expected no leak for an exited goroutine")
+	}
+
+	leaky := &recordingTB{TB: t}
+	CheckGoroutines(leaky)
+	stop := make(chan struct{})
+	go func() { <-stop }()
+	start := time.Now()
+	leaky.runCleanups()
+	close(stop)
+	if !leaky.failed || time.Since(start) < leakTimeout {
+		t.Errorf("Expected a leak after the grace period")
+	}
+}
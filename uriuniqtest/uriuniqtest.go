@@ -0,0 +1,44 @@
+// Package uriuniqtest provides entropy source doubles for testing code that
+// generates IDs under entropy failures and latency. Set them as
+// uriuniq.Options.EntropySource. CheckGoroutines catches goroutines left
+// running by Pools, Streams and servers that were not closed.
+package uriuniqtest
+
+import (
+	"crypto/rand"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// FailingSource returns a source that serves afterN reads from crypto/rand
+// and fails every read after that with err.
+func FailingSource(afterN int, err error) io.Reader {
+	return &failingReader{afterN: int64(afterN), err: err}
+}
+
+type failingReader struct {
+	afterN int64
+	reads  atomic.Int64
+	err    error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.reads.Add(1) > r.afterN {
+		return 0, r.err
+	}
+	return rand.Read(p)
+}
+
+// SlowSource returns a source that waits for latency before every read
+// from crypto/rand.
+func SlowSource(latency time.Duration) io.Reader {
+	return slowReader(latency)
+}
+
+type slowReader time.Duration
+
+func (r slowReader) Read(p []byte) (int, error) {
+	time.Sleep(time.Duration(r))
+	return rand.Read(p)
+}
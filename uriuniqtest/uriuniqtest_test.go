@@ -0,0 +1,37 @@
+package uriuniqtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// TestFailingSource checks that reads fail after the configured count.
+func TestFailingSource(t *testing.T) {
+	errEntropy := errors.New("entropy gone")
+	opts := uriuniq.NewOpts()
+	opts.EntropySource = FailingSource(2, errEntropy)
+	for i := 0; i < 2; i++ {
+		if _, err := uriuniq.Generate(opts); err != nil {
+			t.Fatalf("Generate %d failed: %s", i, err)
+		}
+	}
+	if _, err := uriuniq.Generate(opts); !errors.Is(err, errEntropy) {
+		t.Errorf("Expected entropy error, got %v", err)
+	}
+}
+
+// TestSlowSource checks that reads are delayed.
+func TestSlowSource(t *testing.T) {
+	opts := uriuniq.NewOpts()
+	opts.EntropySource = SlowSource(10 * time.Millisecond)
+	start := time.Now()
+	if _, err := uriuniq.Generate(opts); err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("Expected at least 10ms, took %s", elapsed)
+	}
+}
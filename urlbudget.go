@@ -0,0 +1,37 @@
+package uriuniq
+
+// DefaultURLLimit is a URL length that browsers, proxies and CDNs commonly
+// accept. Some accept more, but links longer than this break silently in
+// some of them.
+const DefaultURLLimit = 2048
+
+// FitsURLBudget reports whether baseURL followed by an ID of idLen
+// unreserved chars, which need no percent-encoding, fits in limit bytes.
+// limit <= 0 uses DefaultURLLimit.
+func FitsURLBudget(baseURL string, idLen int, limit int) bool {
+	if limit <= 0 {
+		limit = DefaultURLLimit
+	}
+	return len(baseURL)+idLen <= limit
+}
+
+// MaxURLIDLength returns the most chars of an ID generated with opts that
+// always fit after baseURL in limit bytes, once percent-encoded: chars
+// other than letters, digits and "-._~" count 3 bytes. It returns 0 if
+// none fit or opts is invalid. limit <= 0 uses DefaultURLLimit.
+func MaxURLIDLength(baseURL string, limit int, opts Options) int {
+	if limit <= 0 {
+		limit = DefaultURLLimit
+	}
+	_, charset, err := prepare(opts)
+	if err != nil || len(baseURL) >= limit {
+		return 0
+	}
+	width := 1
+	for _, c := range charset {
+		if w := percentEncodedLength(string(c)); w > width {
+			width = w
+		}
+	}
+	return (limit - len(baseURL)) / width
+}
@@ -0,0 +1,40 @@
+package uriuniq
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFitsURLBudget checks the budget around the limit.
+func TestFitsURLBudget(t *testing.T) {
+	base := "https://example.com/d/"
+	if !FitsURLBudget(base, DefaultURLLimit-len(base), 0) || FitsURLBudget(base, DefaultURLLimit-len(base)+1, 0) {
+		t.Errorf("Unexpected result at DefaultURLLimit")
+	}
+	if !FitsURLBudget(base, 10, len(base)+10) || FitsURLBudget(base, 11, len(base)+10) {
+		t.Errorf("Unexpected result at custom limit")
+	}
+}
+
+// TestMaxURLIDLength checks the encoding overhead of charsets.
+func TestMaxURLIDLength(t *testing.T) {
+	base := strings.Repeat("a", 48)
+	tests := []struct {
+		charset  Charset
+		expected int
+	}{
+		{"", 52},
+		{"ab-_.~", 52},
+		{"ab!*", 17},
+	}
+	for _, tc := range tests {
+		opts := NewOpts()
+		opts.CustomCharset = tc.charset
+		if got := MaxURLIDLength(base, 100, opts); got != tc.expected {
+			t.Errorf("%q: expected %d, got %d", tc.charset, tc.expected, got)
+		}
+	}
+	if got := MaxURLIDLength(strings.Repeat("a", 200), 100, NewOpts()); got != 0 {
+		t.Errorf("Expected 0 for a long base URL, got %d", got)
+	}
+}
@@ -0,0 +1,109 @@
+package uriuniq
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"time"
+)
+
+// UUID is an RFC 9562 UUID. Its String form is the canonical one, for
+// database columns of type UUID; Encode gives a compact form for URLs,
+// such as 22 chars of Base58, which DecodeUUID reverses.
+type UUID [16]byte
+
+// NewUUIDv4 creates a random UUID version 4.
+func NewUUIDv4() (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(rand.Reader, u[:]); err != nil {
+		return UUID{}, err
+	}
+	u.setVersion(4)
+	return u, nil
+}
+
+// NewUUIDv7 creates a UUID version 7: a millisecond Unix timestamp and 74
+// random bits, so UUIDs sort by creation time to the millisecond.
+func NewUUIDv7() (UUID, error) {
+	return newUUIDv7(rand.Reader, time.Now())
+}
+
+// newUUIDv7 creates a UUID version 7 for the time now with entropy from
+// src.
+func newUUIDv7(src io.Reader, now time.Time) (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(src, u[6:]); err != nil {
+		return UUID{}, err
+	}
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(now.UnixMilli()))
+	copy(u[:6], ms[2:])
+	u.setVersion(7)
+	return u, nil
+}
+
+// setVersion sets the version and the RFC 9562 variant bits of u.
+func (u *UUID) setVersion(v byte) {
+	u[6] = u[6]&0x0f | v<<4
+	u[8] = u[8]&0x3f | 0x80
+}
+
+// Version returns the version of u, as set by NewUUIDv4 and NewUUIDv7.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// String returns u in canonical lowercase form, such as
+// 0190c6e4-4b1c-7cde-8f3a-0123456789ab.
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+// Encode encodes u in charset as Encode does. It fails on an invalid
+// charset.
+func (u UUID) Encode(charset Charset) (string, error) {
+	return Encode(u[:], charset)
+}
+
+// ParseUUID parses a UUID in canonical form or as 32 hex digits without
+// hyphens, in either case.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	digits := s
+	if len(s) == 36 {
+		for _, i := range []int{8, 13, 18, 23} {
+			if s[i] != '-' {
+				return UUID{}, &ParseError{Index: i, Char: rune(s[i])}
+			}
+		}
+		digits = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	} else if len(s) != 32 {
+		return UUID{}, errorf(CodeInvalidLength, "uriuniq: UUID length %d, expected 36 or 32", len(s))
+	}
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return UUID{}, newError(CodeInvalidChar, "uriuniq: UUID has a char that is not a hex digit")
+	}
+	return u, nil
+}
+
+// DecodeUUID decodes a UUID encoded by UUID.Encode with the same charset.
+func DecodeUUID(s string, charset Charset) (UUID, error) {
+	var u UUID
+	b, err := DecodeBytes(s, len(u), charsetOptions(charset))
+	if err != nil {
+		return UUID{}, err
+	}
+	copy(u[:], b)
+	return u, nil
+}
@@ -0,0 +1,74 @@
+package uriuniq
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestUUID checks version bits, the canonical form and the compact
+// encoding of UUIDs.
+func TestUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		new     func() (UUID, error)
+		version int
+	}{
+		{"V4", NewUUIDv4, 4},
+		{"V7", NewUUIDv7, 7},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := tc.new()
+			if err != nil {
+				t.Fatalf("New failed: %s", err)
+			}
+			if u.Version() != tc.version || u[8]&0xc0 != 0x80 {
+				t.Errorf("Unexpected version or variant in %s", u)
+			}
+			if parsed, err := ParseUUID(u.String()); err != nil || parsed != u {
+				t.Errorf("ParseUUID(%s) = %s, %v", u, parsed, err)
+			}
+			s, err := u.Encode(Base58)
+			if err != nil || len(s) != 22 {
+				t.Errorf("Expected 22 chars, got %q, %v", s, err)
+			}
+			if decoded, err := DecodeUUID(s, Base58); err != nil || decoded != u {
+				t.Errorf("DecodeUUID(%q) = %s, %v", s, decoded, err)
+			}
+		})
+	}
+
+	now := time.UnixMilli(0x0190c6e44b1c)
+	u, _ := newUUIDv7(bytes.NewReader(make([]byte, 10)), now)
+	if s := u.String(); s != "0190c6e4-4b1c-7000-8000-000000000000" {
+		t.Errorf("Unexpected UUIDv7 %s", s)
+	}
+	if s, _ := (UUID{}).Encode(Base58); s != strings.Repeat("1", 22) {
+		t.Errorf("Unexpected nil UUID encoding %q", s)
+	}
+}
+
+// TestParseUUID checks accepted and rejected UUID forms.
+func TestParseUUID(t *testing.T) {
+	want := "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"
+	for _, s := range []string{want, strings.ToUpper(want), strings.ReplaceAll(want, "-", "")} {
+		if u, err := ParseUUID(s); err != nil || u.String() != want {
+			t.Errorf("ParseUUID(%q) = %s, %v", s, u, err)
+		}
+	}
+	tests := []struct {
+		s    string
+		code Code
+	}{
+		{"f81d4fae-7dec-11d0-a765", CodeInvalidLength},
+		{"f81d4fae_7dec-11d0-a765-00a0c91e6bf6", CodeInvalidChar},
+		{"g81d4fae-7dec-11d0-a765-00a0c91e6bf6", CodeInvalidChar},
+	}
+	for _, tc := range tests {
+		if _, err := ParseUUID(tc.s); CodeOf(err) != tc.code {
+			t.Errorf("ParseUUID(%q): expected %s, got %v", tc.s, tc.code, err)
+		}
+	}
+}
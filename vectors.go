@@ -0,0 +1,122 @@
+package uriuniq
+
+import (
+	"strings"
+)
+
+// VectorKind is the kind of a Vector.
+type VectorKind int
+
+const (
+	VectorGenerate VectorKind = iota // Seed and Info to expected Outputs
+	VectorValidate                   // Input to expected validity
+)
+
+// Vector is a test vector for checking another implementation of a Format.
+type Vector struct {
+	Kind VectorKind
+	Name string
+
+	// Generate vectors: the first IDs of DeriveGenerator(Seed, Info, opts).
+	Seed    []byte
+	Info    string
+	Outputs []string
+
+	// Validate vectors: whether Input matches the Format.
+	Input string
+	Valid bool
+}
+
+// vectorSeeds are the master seeds of the generate vectors.
+var vectorSeeds = []string{"uriuniq test vector 1", "uriuniq test vector 2"}
+
+// vectorOutputs is the number of IDs in each generate vector.
+const vectorOutputs = 4
+
+// TestVectors returns test vectors for format, so implementations of the
+// same format in other languages can be checked programmatically. Formats
+// made with OptionsFormat get generate vectors, following the algorithm
+// documented on DeriveGenerator, and validate vectors derived from them.
+// The well-known formats get a fixed set of validate vectors.
+func TestVectors(format Format) []Vector {
+	switch format.Name {
+	case FormatULID.Name:
+		return validateVectors([]string{
+			"01ARZ3NDEKTSV4RRFFQ69G5FAV", "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", "00000000000000000000000000",
+		}, []string{
+			"", "81ARZ3NDEKTSV4RRFFQ69G5FAV", "01arz3ndektsv4rrffq69g5fav", "01ARZ3NDEKTSV4RRFFQ69G5FAI",
+			"01ARZ3NDEKTSV4RRFFQ69G5FAL", "01ARZ3NDEKTSV4RRFFQ69G5FAO", "01ARZ3NDEKTSV4RRFFQ69G5FAU",
+			"01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAVV",
+		})
+	case FormatUUIDv7.Name:
+		return validateVectors([]string{
+			"01890a5d-ac96-774b-bcce-b302099a8057", "00000000-0000-7000-8000-000000000000",
+		}, []string{
+			"", "01890a5d-ac96-474b-bcce-b302099a8057", "01890a5d-ac96-774b-ccce-b302099a8057",
+			"01890A5D-AC96-774B-BCCE-B302099A8057", "01890a5dac96774bbcceb302099a8057",
+			"{01890a5d-ac96-774b-bcce-b302099a8057}",
+		})
+	case FormatTypeID.Name:
+		return validateVectors([]string{
+			"01h455vb4pex5vsknk084sn02q", "user_01h455vb4pex5vsknk084sn02q",
+			"my_type_01h455vb4pex5vsknk084sn02q",
+		}, []string{
+			"", "User_01h455vb4pex5vsknk084sn02q", "_01h455vb4pex5vsknk084sn02q",
+			"user__01h455vb4pex5vsknk084sn02q", "user_81h455vb4pex5vsknk084sn02q",
+			"user_01h455vb4pex5vsknk084sn02", strings.Repeat("a", 64) + "_01h455vb4pex5vsknk084sn02q",
+		})
+	}
+	if format.Options == nil {
+		return nil
+	}
+
+	var vectors []Vector
+	for _, seed := range vectorSeeds {
+		gen, err := DeriveGenerator([]byte(seed), format.Name, *format.Options)
+		if err != nil {
+			return nil
+		}
+		v := Vector{Kind: VectorGenerate, Name: "generate " + seed, Seed: []byte(seed), Info: format.Name}
+		for i := 0; i < vectorOutputs; i++ {
+			id, err := gen.Next()
+			if err != nil {
+				return nil
+			}
+			v.Outputs = append(v.Outputs, id)
+		}
+		vectors = append(vectors, v)
+	}
+
+	id := vectors[0].Outputs[0]
+	invalid := []string{"", id[:len(id)-1], id + id[:1]}
+	if c, err := outsideChar(format.Options); err == nil {
+		invalid = append(invalid, id[:len(id)-1]+string(c))
+	}
+	return append(vectors, validateVectors(vectors[1].Outputs, invalid)...)
+}
+
+// validateVectors builds validate vectors from valid and invalid inputs.
+func validateVectors(valid, invalid []string) []Vector {
+	var vectors []Vector
+	for _, s := range valid {
+		vectors = append(vectors, Vector{Kind: VectorValidate, Name: "valid " + s, Input: s, Valid: true})
+	}
+	for _, s := range invalid {
+		vectors = append(vectors, Vector{Kind: VectorValidate, Name: "invalid " + s, Input: s})
+	}
+	return vectors
+}
+
+// outsideChar returns a char that is not in the charset of opts.
+func outsideChar(opts *Options) (byte, error) {
+	_, charset, err := prepare(*opts)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range []byte("#/%@ ") {
+		if strings.IndexByte(string(charset), c) < 0 {
+			return c, nil
+		}
+	}
+	return 0, newError(CodeInvalidArgument, "uriuniq: no outside char")
+}
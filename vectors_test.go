@@ -0,0 +1,60 @@
+package uriuniq
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// TestVectorsValidate checks validate vectors against the format pattern.
+func TestVectorsValidate(t *testing.T) {
+	session, err := OptionsFormat("session", NewOpts())
+	if err != nil {
+		t.Fatalf("OptionsFormat failed: %s", err)
+	}
+	for _, format := range []Format{FormatULID, FormatUUIDv7, FormatTypeID, session} {
+		re := regexp.MustCompile(format.Pattern)
+		vectors := TestVectors(format)
+		if len(vectors) == 0 {
+			t.Fatalf("%s: no vectors", format.Name)
+		}
+		for _, v := range vectors {
+			if v.Kind != VectorValidate {
+				continue
+			}
+			valid := re.MatchString(v.Input) && len(v.Input) >= format.MinLength && len(v.Input) <= format.MaxLength
+			if valid != v.Valid {
+				t.Errorf("%s: %s: expected %v", format.Name, v.Name, v.Valid)
+			}
+		}
+	}
+
+	if vectors := TestVectors(Format{Name: "unknown"}); vectors != nil {
+		t.Errorf("Expected no vectors for unknown format")
+	}
+}
+
+// TestVectorsGenerate pins the generate vectors of an options format, so
+// changes to the documented derivation are caught.
+func TestVectorsGenerate(t *testing.T) {
+	opts := NewOpts()
+	opts.Length = 10
+	format, err := OptionsFormat("order", opts)
+	if err != nil {
+		t.Fatalf("OptionsFormat failed: %s", err)
+	}
+	vectors := TestVectors(format)
+	// Cross-checked with an independent HKDF and AES-CTR implementation.
+	expected := [][]string{
+		{"r7WwhBzbdm", "lawSZmy2yw", "IAGZbegX4M", "zaW88mQbTN"},
+		{"4deSBaHRpJ", "x1je845CP9", "V6Bhp3MU3F", "adBSkAYkFI"},
+	}
+	for i, v := range vectors[:2] {
+		if v.Kind != VectorGenerate || v.Info != "order" || len(v.Outputs) != vectorOutputs {
+			t.Fatalf("Unexpected vector %+v", v)
+		}
+		if !reflect.DeepEqual(v.Outputs, expected[i]) {
+			t.Errorf("%s: expected %q, got %q", v.Name, expected[i], v.Outputs)
+		}
+	}
+}
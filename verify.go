@@ -0,0 +1,97 @@
+package uriuniq
+
+import (
+	"sync"
+	"time"
+)
+
+// Report summarizes a VerifyConcurrent run.
+type Report struct {
+	Generated   int           // IDs generated successfully
+	Duplicates  int           // IDs seen more than once
+	Errors      int           // Failed Next calls
+	FirstError  error         // First error returned by Next, if any
+	Elapsed     time.Duration // Wall time of the run
+	PerSecond   float64       // Throughput in IDs per second
+	MeanLatency time.Duration // Mean latency of Next
+	MaxLatency  time.Duration // Worst latency of Next, a sign of contention
+}
+
+// OK reports whether the run had no errors and no duplicates.
+func (r Report) OK() bool {
+	return r.Errors == 0 && r.Duplicates == 0
+}
+
+// VerifyConcurrent calls Next on one Generator built from opts from many
+// goroutines at once, each generating perG IDs, and reports duplicates,
+// throughput and latency. Run it with -race to also check for data races.
+func VerifyConcurrent(opts Options, goroutines, perG int) Report {
+	gen, err := NewGenerator(opts)
+	if err != nil {
+		return Report{Errors: 1, FirstError: err}
+	}
+
+	type result struct {
+		ids     []string
+		errs    int
+		err     error
+		total   time.Duration
+		maxCall time.Duration
+	}
+	results := make([]result, goroutines)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(r *result) {
+			defer wg.Done()
+			r.ids = make([]string, 0, perG)
+			for i := 0; i < perG; i++ {
+				callStart := time.Now()
+				id, err := gen.Next()
+				elapsed := time.Since(callStart)
+				r.total += elapsed
+				if elapsed > r.maxCall {
+					r.maxCall = elapsed
+				}
+				if err != nil {
+					r.errs++
+					if r.err == nil {
+						r.err = err
+					}
+					continue
+				}
+				r.ids = append(r.ids, id)
+			}
+		}(&results[g])
+	}
+	wg.Wait()
+
+	report := Report{Elapsed: time.Since(start)}
+	seen := make(map[string]bool, goroutines*perG)
+	var total time.Duration
+	for _, r := range results {
+		for _, id := range r.ids {
+			if seen[id] {
+				report.Duplicates++
+			}
+			seen[id] = true
+		}
+		report.Generated += len(r.ids)
+		report.Errors += r.errs
+		if report.FirstError == nil {
+			report.FirstError = r.err
+		}
+		total += r.total
+		if r.maxCall > report.MaxLatency {
+			report.MaxLatency = r.maxCall
+		}
+	}
+	if calls := goroutines * perG; calls > 0 {
+		report.MeanLatency = total / time.Duration(calls)
+	}
+	if report.Elapsed > 0 {
+		report.PerSecond = float64(report.Generated) / report.Elapsed.Seconds()
+	}
+	return report
+}
@@ -0,0 +1,32 @@
+package uriuniq
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestVerifyConcurrent hammers a Generator and expects no duplicates.
+func TestVerifyConcurrent(t *testing.T) {
+	report := VerifyConcurrent(NewOpts(), 8, 500)
+	if !report.OK() || report.Generated != 4000 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if report.PerSecond <= 0 || report.MaxLatency < report.MeanLatency {
+		t.Errorf("Unexpected timings %+v", report)
+	}
+}
+
+// TestVerifyConcurrentDuplicates checks that duplicates are detected.
+func TestVerifyConcurrentDuplicates(t *testing.T) {
+	opts := NewOpts()
+	opts.EntropySource = bytes.NewReader(make([]byte, 4*MaxBuffLength))
+	report := VerifyConcurrent(opts, 2, 2)
+	if report.Duplicates != 3 || report.OK() {
+		t.Errorf("Expected 3 duplicates, got %+v", report)
+	}
+
+	opts.CustomCharset = "a"
+	if report := VerifyConcurrent(opts, 2, 2); report.FirstError == nil {
+		t.Errorf("Expected error for invalid options")
+	}
+}
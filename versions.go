@@ -0,0 +1,112 @@
+package uriuniq
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownVersion is returned when an ID has no registered version marker.
+var ErrUnknownVersion = newError(CodeUnknownVersion, "uriuniq: unknown version")
+
+// Versions is a registry of ID formats keyed by a one-char version marker,
+// which is the first char of every ID. New IDs use the latest version, while
+// IDs of older versions keep validating, so a format can evolve without
+// invalidating what was already issued.
+//
+// Register all versions before use, then Freeze the registry; the other
+// methods are then safe for concurrent use.
+type Versions struct {
+	frozen   bool
+	current  byte
+	versions map[byte]version
+}
+
+// version is a registered format with its prepared Options.
+type version struct {
+	opts Options
+}
+
+// NewVersions creates an empty registry.
+func NewVersions() *Versions {
+	return &Versions{versions: make(map[byte]version)}
+}
+
+// Register adds the format opts under marker, which must be URI-safe and
+// may not be registered already. The last registered version is used by
+// Generate.
+func (v *Versions) Register(marker byte, opts Options) error {
+	if v.frozen {
+		return ErrFrozen
+	}
+	if strings.IndexByte(string(uriSafe), marker) < 0 {
+		return errorf(CodeInvalidArgument, "uriuniq: version marker %q is not URI-safe", marker)
+	}
+	if _, ok := v.versions[marker]; ok {
+		return errorf(CodeInvalidArgument, "uriuniq: version %q already registered", marker)
+	}
+	opts, _, err := prepare(opts)
+	if err != nil {
+		return err
+	}
+	v.versions[marker] = version{opts: opts}
+	v.current = marker
+	return nil
+}
+
+// Freeze makes the registry immutable: Register fails with ErrFrozen from
+// then on. Call it once startup is done, so a reviewed ID policy cannot be
+// changed later at runtime.
+func (v *Versions) Freeze() {
+	v.frozen = true
+}
+
+// Generate creates an ID of the latest version: its marker followed by an
+// ID as Generate creates it with the Options of the version.
+func (v *Versions) Generate() (string, error) {
+	cur, ok := v.versions[v.current]
+	if !ok {
+		return "", newError(CodeInvalidArgument, "uriuniq: no versions registered")
+	}
+	id, err := generateID(cur.opts)
+	if err != nil {
+		return "", err
+	}
+	return string(v.current) + id, nil
+}
+
+// Validate checks id against the format of its version marker, as
+// Validate does for the rest of id, and returns the version. Invalid chars
+// are reported as a *ParseError indexed into id.
+func (v *Versions) Validate(id string) (marker byte, err error) {
+	if id == "" {
+		return 0, ErrEmptyInput
+	}
+	ver, ok := v.versions[id[0]]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownVersion, id[0])
+	}
+	if err := Validate(id[1:], ver.opts); err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Index++
+		}
+		return 0, err
+	}
+	return id[0], nil
+}
+
+// Options returns the format registered under marker.
+func (v *Versions) Options(marker byte) (Options, bool) {
+	ver, ok := v.versions[marker]
+	return ver.opts, ok
+}
+
+// Markers returns the registered version markers in ascending order.
+func (v *Versions) Markers() []byte {
+	markers := make([]byte, 0, len(v.versions))
+	for m := range v.versions {
+		markers = append(markers, m)
+	}
+	sort.Slice(markers, func(i, j int) bool { return markers[i] < markers[j] })
+	return markers
+}
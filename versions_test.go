@@ -0,0 +1,104 @@
+package uriuniq
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestVersions checks that old versions keep validating after a new one.
+func TestVersions(t *testing.T) {
+	v := NewVersions()
+	if _, err := v.Generate(); err == nil {
+		t.Errorf("Expected error without versions")
+	}
+
+	old := NewOpts()
+	old.Length = 8
+	old.ExcludeUppercase = true
+	if err := v.Register('1', old); err != nil {
+		t.Fatalf("Register failed: %s", err)
+	}
+	oldID, err := v.Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+
+	if err := v.Register('2', NewOpts()); err != nil {
+		t.Fatalf("Register failed: %s", err)
+	}
+	newID, err := v.Generate()
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	if newID[0] != '2' || len(newID) != DefaultLength+1 {
+		t.Errorf("Unexpected new ID %q", newID)
+	}
+
+	for id, want := range map[string]byte{oldID: '1', newID: '2'} {
+		if got, err := v.Validate(id); err != nil || got != want {
+			t.Errorf("Validate(%q) = %q, %v", id, got, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"Empty", ""},
+		{"Unknown Version", "9abcdefgh"},
+		{"Wrong Length", "1abc"},
+		{"Uppercase In Old Version", "1abcdefgH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Validate(tt.id); err == nil {
+				t.Errorf("Expected error for %q", tt.id)
+			}
+		})
+	}
+
+	var perr *ParseError
+	if _, err := v.Validate("1abcdefgH"); !errors.As(err, &perr) || perr.Index != 8 {
+		t.Errorf("Expected ParseError at index 8, got %v", err)
+	}
+	if _, err := v.Validate("9abc"); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("Expected ErrUnknownVersion, got %v", err)
+	}
+	if err := v.Register('1', NewOpts()); err == nil {
+		t.Errorf("Expected error for duplicate version")
+	}
+	if err := v.Register('/', NewOpts()); err == nil {
+		t.Errorf("Expected error for unsafe marker")
+	}
+	if opts, ok := v.Options('1'); !ok || opts.Length != 8 {
+		t.Errorf("Unexpected Options %+v, %v", opts, ok)
+	}
+}
+
+// TestVersionsFreeze checks that a frozen registry rejects new versions.
+func TestVersionsFreeze(t *testing.T) {
+	v := NewVersions()
+	if err := v.Register('1', NewOpts()); err != nil {
+		t.Fatalf("Register failed: %s", err)
+	}
+	v.Freeze()
+	if err := v.Register('2', NewOpts()); !errors.Is(err, ErrFrozen) {
+		t.Errorf("Expected ErrFrozen, got %v", err)
+	}
+	if id, err := v.Generate(); err != nil || id[0] != '1' {
+		t.Errorf("Frozen registry should still generate, got %q, %v", id, err)
+	}
+}
+
+// TestVersionsMarkers checks that markers are listed in order.
+func TestVersionsMarkers(t *testing.T) {
+	v := NewVersions()
+	for _, m := range []byte("b2a") {
+		if err := v.Register(m, NewOpts()); err != nil {
+			t.Fatalf("Register failed: %s", err)
+		}
+	}
+	if got := string(v.Markers()); got != "2ab" {
+		t.Errorf("Expected markers 2ab, got %q", got)
+	}
+}
@@ -0,0 +1,20 @@
+package uriuniq
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// VisualSeed derives stable values from id for identicons and default
+// avatars, so every service draws the same picture for an ID. hue is in
+// [0, 360) degrees and pattern holds 32 bits to pick shapes from.
+//
+// With d = SHA-256("uriuniq visual\x00" + id), hue is the big-endian uint32
+// of d[0:4] times 360, shifted right by 32, and pattern is the big-endian
+// uint32 of d[4:8]. Hashing keeps similar IDs from getting similar colors.
+func VisualSeed(id string) (hue, pattern uint32) {
+	d := sha256.Sum256([]byte("uriuniq visual\x00" + id))
+	hue = uint32(uint64(binary.BigEndian.Uint32(d[0:4])) * 360 >> 32)
+	pattern = binary.BigEndian.Uint32(d[4:8])
+	return hue, pattern
+}
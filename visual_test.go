@@ -0,0 +1,31 @@
+package uriuniq
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+)
+
+// TestVisualSeed checks the documented derivation and its range.
+func TestVisualSeed(t *testing.T) {
+	d := sha256.Sum256([]byte("uriuniq visual\x00usr_1"))
+	hue, pattern := VisualSeed("usr_1")
+	if want := uint32(uint64(binary.BigEndian.Uint32(d[:4])) * 360 >> 32); hue != want {
+		t.Errorf("Expected hue %d, got %d", want, hue)
+	}
+	if want := binary.BigEndian.Uint32(d[4:8]); pattern != want {
+		t.Errorf("Expected pattern %d, got %d", want, pattern)
+	}
+
+	hues := make(map[uint32]bool)
+	for _, id := range []string{"usr_1", "usr_2", "usr_3", "usr_4", "usr_5", "usr_6"} {
+		hue, _ := VisualSeed(id)
+		if hue >= 360 {
+			t.Fatalf("Hue %d out of range", hue)
+		}
+		hues[hue] = true
+	}
+	if len(hues) < 5 {
+		t.Errorf("Similar IDs got similar hues: %v", hues)
+	}
+}
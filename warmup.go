@@ -0,0 +1,49 @@
+package uriuniq
+
+import "context"
+
+// Warmup creates and drops n strings, so the entropy source is opened, the
+// read-ahead buffer is filled and the code paths are primed before the
+// first real call, as after a deploy or a serverless cold start. Dropped
+// strings are not counted as issued. It stops early with the error of ctx.
+func (g *Generator) Warmup(ctx context.Context, n int) error {
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		output, err := g.next(nil)
+		if err != nil {
+			return err
+		}
+		wipe(output)
+	}
+	return nil
+}
+
+// Warmup fills the reserve of p and warms up its Generator with n strings.
+func (p *Pool) Warmup(ctx context.Context, n int) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p.mu.Lock()
+		full, closed := len(p.reserve) >= p.size, p.closed
+		p.mu.Unlock()
+		if closed {
+			return ErrClosed
+		}
+		if full {
+			break
+		}
+		id, err := p.g.Next()
+		if err != nil {
+			return err
+		}
+		p.mu.Lock()
+		if !p.closed {
+			p.reserve = append(p.reserve, id)
+		}
+		p.mu.Unlock()
+	}
+	return p.g.Warmup(ctx, n)
+}
@@ -0,0 +1,58 @@
+package uriuniq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestWarmup checks that warming up reads entropy without issuing.
+func TestWarmup(t *testing.T) {
+	src := &countingReader{r: &switchReader{}}
+	opts := NewOpts()
+	opts.EntropySource = src
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %s", err)
+	}
+	if err := g.Warmup(context.Background(), 4); err != nil {
+		t.Fatalf("Warmup failed: %s", err)
+	}
+	if src.reads == 0 || g.Issued() != 0 {
+		t.Errorf("Expected reads without issuing, got %d reads, %d issued", src.reads, g.Issued())
+	}
+	// The buffer is filled, so the first call reads nothing.
+	reads := src.reads
+	if _, err := g.Next(); err != nil || src.reads != reads {
+		t.Errorf("Expected no read after warmup, got %d, %v", src.reads-reads, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.Warmup(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestPoolWarmup checks that warming up refills the reserve.
+func TestPoolWarmup(t *testing.T) {
+	src := &switchReader{}
+	opts := NewOpts()
+	opts.EntropySource = src
+	opts.EntropyBufferSize = -1
+	p, err := NewPool(opts, 2)
+	if err != nil {
+		t.Fatalf("NewPool failed: %s", err)
+	}
+	p.MaxDegraded = 1 << 62
+	src.err = errors.New("down")
+	p.Next()
+	p.Next()
+	if p.Reserve() != 0 {
+		t.Fatalf("Expected empty reserve, got %d", p.Reserve())
+	}
+	src.err = nil
+	if err := p.Warmup(context.Background(), 1); err != nil || p.Reserve() != 2 {
+		t.Errorf("Expected full reserve, got %d, %v", p.Reserve(), err)
+	}
+}
@@ -0,0 +1,139 @@
+// Package webhook mints webhook secrets and signs and verifies webhook
+// deliveries. A delivery carries the header
+//
+//	X-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256(secret, "<t>." + body)>
+//
+// and is rejected outside of a replay window around its timestamp.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/laofun/uriuniq"
+)
+
+// SignatureHeader is the header carrying the signature of a delivery.
+const SignatureHeader = "X-Signature"
+
+// DefaultTolerance is the replay window of Verify.
+const DefaultTolerance = 5 * time.Minute
+
+// Secret layout: prefix, "_", secretRandom random chars and
+// secretChecksum chars of the CRC-32 of everything before them.
+const (
+	secretRandom   = 32
+	secretChecksum = 6
+)
+
+var (
+	// ErrBadSecret is returned by CheckSecret for a mistyped or truncated
+	// secret.
+	ErrBadSecret = &uriuniq.Error{Code: uriuniq.CodeBadCheckChar, Err: errors.New("webhook: bad secret checksum")}
+	// ErrInvalidSignature is returned for a missing or wrong signature.
+	ErrInvalidSignature = &uriuniq.Error{Code: uriuniq.CodeInvalidToken, Err: errors.New("webhook: invalid signature")}
+	// ErrOutsideWindow is returned for a delivery outside the replay window.
+	ErrOutsideWindow = &uriuniq.Error{Code: uriuniq.CodeTokenExpired, Err: errors.New("webhook: timestamp outside replay window")}
+)
+
+// NewSecret mints a secret for one endpoint, such as
+// "whsec_3hG9...kQ2Lm0Xb". The prefix makes secrets findable by secret
+// scanners, and the checksum lets CheckSecret catch copy errors.
+func NewSecret(prefix string) (string, error) {
+	body, err := secrets.Next()
+	if err != nil {
+		return "", err
+	}
+	s := prefix + "_" + body
+	return s + checksum(s), nil
+}
+
+// secrets generates the random part of NewSecret. It does not read
+// entropy ahead, so no material of future secrets sits in memory.
+var secrets, _ = uriuniq.NewGenerator(uriuniq.Options{Length: secretRandom, EntropyBufferSize: -1})
+
+// CheckSecret verifies the checksum of a secret minted with prefix.
+func CheckSecret(secret, prefix string) error {
+	if len(secret) != len(prefix)+1+secretRandom+secretChecksum || !strings.HasPrefix(secret, prefix+"_") {
+		return ErrBadSecret
+	}
+	n := len(secret) - secretChecksum
+	if !hmac.Equal([]byte(checksum(secret[:n])), []byte(secret[n:])) {
+		return ErrBadSecret
+	}
+	return nil
+}
+
+// checksum returns the CRC-32 of s in secretChecksum base62 chars.
+func checksum(s string) string {
+	v := crc32.ChecksumIEEE([]byte(s))
+	out := make([]byte, secretChecksum)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = uriuniq.Alphanumeric[v%62]
+		v /= 62
+	}
+	return string(out)
+}
+
+// Sign returns the SignatureHeader value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+// Verify checks the SignatureHeader value header of body and that its
+// timestamp is within tolerance of now. tolerance <= 0 uses
+// DefaultTolerance. Headers with several v1 signatures, as sent while
+// rotating secrets, are accepted if any matches.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	return verify(secret, header, body, tolerance, time.Now())
+}
+
+func verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+	want := mac(secret, ts, body)
+	ok := false
+	for _, sig := range sigs {
+		ok = hmac.Equal([]byte(sig), []byte(want)) || ok
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+		return fmt.Errorf("%w: %s", ErrOutsideWindow, d.Round(time.Second))
+	}
+	return nil
+}
+
+// mac returns the hex HMAC-SHA256 of "<ts>." + body under secret.
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte{'.'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
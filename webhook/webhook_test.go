@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSecret checks the prefix and checksum of secrets.
+func TestSecret(t *testing.T) {
+	secret, err := NewSecret("whsec")
+	if err != nil {
+		t.Fatalf("NewSecret failed: %s", err)
+	}
+	if !strings.HasPrefix(secret, "whsec_") || len(secret) != 6+32+6 {
+		t.Errorf("Unexpected secret %q", secret)
+	}
+	if err := CheckSecret(secret, "whsec"); err != nil {
+		t.Errorf("CheckSecret failed: %s", err)
+	}
+	typo := []byte(secret)
+	typo[10] ^= 1
+	for _, bad := range []string{string(typo), secret[:len(secret)-1], "other" + secret[5:]} {
+		if err := CheckSecret(bad, "whsec"); !errors.Is(err, ErrBadSecret) {
+			t.Errorf("Expected ErrBadSecret for %q, got %v", bad, err)
+		}
+	}
+}
+
+// TestSignVerify checks signatures and the replay window.
+func TestSignVerify(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"event":"paid"}`)
+	sent := time.Unix(1700000000, 0)
+	header := Sign(secret, sent, body)
+	if !strings.HasPrefix(header, "t=1700000000,v1=") {
+		t.Errorf("Unexpected header %q", header)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		now    time.Time
+		err    error
+	}{
+		{"Valid", header, string(body), sent.Add(time.Minute), nil},
+		{"Rotated Secrets", header + ",v1=00ff", string(body), sent, nil},
+		{"Altered Body", header, `{"event":"refunded"}`, sent, ErrInvalidSignature},
+		{"Missing Signature", "t=1700000000", string(body), sent, ErrInvalidSignature},
+		{"Replayed", header, string(body), sent.Add(time.Hour), ErrOutsideWindow},
+		{"From The Future", header, string(body), sent.Add(-time.Hour), ErrOutsideWindow},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verify(secret, tc.header, []byte(tc.body), 0, tc.now)
+			if tc.err == nil && err != nil || tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("Expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+	if err := Verify(secret, Sign(secret, time.Now(), body), body, time.Minute); err != nil {
+		t.Errorf("Verify failed: %s", err)
+	}
+}
@@ -0,0 +1,97 @@
+package uriuniq
+
+import (
+	"io"
+	"math"
+	"strings"
+)
+
+// DefaultWordCount is the default number of words of GenerateWordID.
+const DefaultWordCount = 3
+
+// WordIDOptions configures GenerateWordID.
+type WordIDOptions struct {
+	// Wordlist is the list words are drawn from. Defaults to
+	// DefaultWordlist.
+	Wordlist Wordlist
+	// Words is the number of words. Defaults to DefaultWordCount.
+	Words int
+	// Separator joins the words and the suffix. Defaults to "-".
+	Separator string
+	// Suffix, if set, generates a random part appended after the words, to
+	// add entropy without making the ID much harder to remember.
+	Suffix *Options
+	// EntropySource is used to draw the words. Defaults to crypto/rand.
+	EntropySource io.Reader
+}
+
+// GenerateWordID creates a memorable ID of random words, such as
+// "crew-luck-j9f2" with two words and a 4-char suffix, for room codes
+// and magic links read by people. Each word is drawn uniformly from the
+// wordlist; see WordIDEntropy for the strength of the result.
+func GenerateWordID(opts WordIDOptions) (string, error) {
+	words, err := wordIDWords(opts)
+	if err != nil {
+		return "", err
+	}
+	n := opts.Words
+	if n == 0 {
+		n = DefaultWordCount
+	}
+	if n < 0 {
+		return "", newError(CodeInvalidArgument, "uriuniq: word count must be positive")
+	}
+	sep := opts.Separator
+	if sep == "" {
+		sep = "-"
+	}
+	src := newEntropyReader(Options{EntropySource: opts.EntropySource})
+
+	parts := make([]string, 0, n+1)
+	for i := 0; i < n; i++ {
+		j, err := randIndex(src, len(words))
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, words[j])
+	}
+	if opts.Suffix != nil {
+		suffix, err := generateID(*opts.Suffix)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, suffix)
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// WordIDEntropy returns the bits of entropy of the IDs GenerateWordID
+// creates with opts, or 0 for invalid opts.
+func WordIDEntropy(opts WordIDOptions) float64 {
+	words, err := wordIDWords(opts)
+	if err != nil || opts.Words < 0 {
+		return 0
+	}
+	n := opts.Words
+	if n == 0 {
+		n = DefaultWordCount
+	}
+	bits := float64(n) * math.Log2(float64(len(words)))
+	if opts.Suffix != nil {
+		bits += Entropy(*opts.Suffix)
+	}
+	return bits
+}
+
+// wordIDWords returns the words of the wordlist of opts.
+func wordIDWords(opts WordIDOptions) ([]string, error) {
+	w := opts.Wordlist
+	if w == nil {
+		w = DefaultWordlist()
+	}
+	words := w.Words()
+	if len(words) == 0 {
+		return nil, newError(CodeEmptyInput, "uriuniq: empty wordlist")
+	}
+	return words, nil
+}
@@ -0,0 +1,66 @@
+package uriuniq
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestGenerateWordID checks the shape of word IDs.
+func TestGenerateWordID(t *testing.T) {
+	suffix := &Options{Length: 4, ExcludeUppercase: true}
+	tests := []struct {
+		name  string
+		opts  WordIDOptions
+		parts int
+		sep   string
+		bits  float64
+	}{
+		{"Default", WordIDOptions{}, 3, "-", 24},
+		{"Suffix", WordIDOptions{Words: 2, Suffix: suffix}, 3, "-", 16 + Entropy(*suffix)},
+		{"Separator", WordIDOptions{Words: 4, Separator: "."}, 4, ".", 32},
+		{"Custom", WordIDOptions{Wordlist: &StaticWordlist{List: []string{"ab", "cd"}}, Words: 5}, 5, "-", 5},
+	}
+	words := map[string]bool{"ab": true, "cd": true}
+	for _, w := range DefaultWordlist().Words() {
+		words[w] = true
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := GenerateWordID(tc.opts)
+			if err != nil {
+				t.Fatalf("GenerateWordID failed: %s", err)
+			}
+			parts := strings.Split(id, tc.sep)
+			if len(parts) != tc.parts {
+				t.Fatalf("Unexpected ID %q", id)
+			}
+			if tc.opts.Suffix != nil {
+				if err := Validate(parts[len(parts)-1], *tc.opts.Suffix); err != nil {
+					t.Errorf("Invalid suffix of %q: %s", id, err)
+				}
+				parts = parts[:len(parts)-1]
+			}
+			for _, p := range parts {
+				if !words[p] {
+					t.Errorf("Unknown word %q in %q", p, id)
+				}
+			}
+			if bits := WordIDEntropy(tc.opts); bits != tc.bits {
+				t.Errorf("Expected %g bits, got %g", tc.bits, bits)
+			}
+		})
+	}
+
+	opts := WordIDOptions{Words: 2, EntropySource: bytes.NewReader(make([]byte, 8))}
+	first := DefaultWordlist().Words()[0]
+	if id, err := GenerateWordID(opts); err != nil || id != first+"-"+first {
+		t.Errorf("Unexpected ID %q, %v", id, err)
+	}
+	if _, err := GenerateWordID(WordIDOptions{Words: -1}); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected CodeInvalidArgument, got %v", err)
+	}
+	if _, err := GenerateWordID(WordIDOptions{Wordlist: &StaticWordlist{}}); CodeOf(err) != CodeEmptyInput {
+		t.Errorf("Expected CodeEmptyInput, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package uriuniq
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Wordlist is a list of words in one language, used by word-based IDs and
+// by blocklists. Words must be unique and free of whitespace.
+type Wordlist interface {
+	Lang() string // BCP 47 language tag, such as "en" or "de-CH"
+	Words() []string
+}
+
+// StaticWordlist is a Wordlist held in memory.
+type StaticWordlist struct {
+	Language string
+	List     []string
+}
+
+// Lang implements Wordlist.
+func (w *StaticWordlist) Lang() string { return w.Language }
+
+// Words implements Wordlist.
+func (w *StaticWordlist) Words() []string { return w.List }
+
+// The default lists are written for this module and released under its
+// license, so they can be shipped without third-party terms.
+var (
+	//go:embed wordlists/en.txt
+	defaultWords string
+	//go:embed wordlists/blocklist-en.txt
+	defaultBlocklist string
+)
+
+// DefaultWordlist returns the embedded English list of 256 short, common
+// words, 8 bits of entropy each.
+func DefaultWordlist() Wordlist {
+	w, _ := LoadWordlist(strings.NewReader(defaultWords), "en")
+	return w
+}
+
+// DefaultBlocklist returns the embedded English list of terms generated
+// IDs should not contain.
+func DefaultBlocklist() Wordlist {
+	w, _ := LoadWordlist(strings.NewReader(defaultBlocklist), "en")
+	return w
+}
+
+// LoadWordlist reads a Wordlist in language lang with one word per line.
+// Blank lines and lines starting with '#' are skipped and words are
+// trimmed of surrounding whitespace.
+func LoadWordlist(r io.Reader, lang string) (Wordlist, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return newWordlist(lang, words)
+}
+
+// LoadWordlistJSON reads a Wordlist from a JSON object such as
+// {"lang": "de", "words": ["apfel", "birne"]}.
+func LoadWordlistJSON(r io.Reader) (Wordlist, error) {
+	var doc struct {
+		Lang  string   `json:"lang"`
+		Words []string `json:"words"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return newWordlist(doc.Lang, doc.Words)
+}
+
+// newWordlist checks words and returns them as a Wordlist.
+func newWordlist(lang string, words []string) (Wordlist, error) {
+	if len(words) == 0 {
+		return nil, newError(CodeEmptyInput, "uriuniq: empty wordlist")
+	}
+	seen := make(map[string]bool, len(words))
+	for _, w := range words {
+		if w == "" || strings.IndexFunc(w, unicode.IsSpace) >= 0 {
+			return nil, errorf(CodeInvalidArgument, "uriuniq: invalid word %q", w)
+		}
+		if seen[w] {
+			return nil, errorf(CodeInvalidArgument, "uriuniq: duplicate word %q", w)
+		}
+		seen[w] = true
+	}
+	return &StaticWordlist{Language: lang, List: words}, nil
+}
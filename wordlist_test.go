@@ -0,0 +1,58 @@
+package uriuniq
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDefaultWordlists checks the embedded lists.
+func TestDefaultWordlists(t *testing.T) {
+	words := DefaultWordlist()
+	if words.Lang() != "en" || len(words.Words()) != 256 {
+		t.Errorf("Expected 256 English words, got %d %q", len(words.Words()), words.Lang())
+	}
+	for _, w := range words.Words() {
+		if checkCharset(w, Lowercase) != nil {
+			t.Errorf("Word %q is not lowercase ASCII", w)
+		}
+	}
+	if len(DefaultBlocklist().Words()) == 0 {
+		t.Errorf("Expected a non-empty blocklist")
+	}
+}
+
+// TestLoadWordlist checks both file formats and validation.
+func TestLoadWordlist(t *testing.T) {
+	w, err := LoadWordlist(strings.NewReader("# Farben\nrot\n\n  grün \nblau\n"), "de")
+	if err != nil {
+		t.Fatalf("LoadWordlist failed: %s", err)
+	}
+	if w.Lang() != "de" || strings.Join(w.Words(), ",") != "rot,grün,blau" {
+		t.Errorf("Unexpected wordlist %q %v", w.Lang(), w.Words())
+	}
+
+	w, err = LoadWordlistJSON(strings.NewReader(`{"lang": "fr", "words": ["pomme", "poire"]}`))
+	if err != nil || w.Lang() != "fr" || len(w.Words()) != 2 {
+		t.Errorf("Unexpected JSON wordlist %v, %v", w, err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		code  Code
+	}{
+		{"Empty", "# nothing\n", CodeEmptyInput},
+		{"Duplicate", "rot\nrot\n", CodeInvalidArgument},
+		{"Inner Space", "dunkel rot\n", CodeInvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadWordlist(strings.NewReader(tc.input), "de"); CodeOf(err) != tc.code {
+				t.Errorf("Expected code %s, got %v", tc.code, err)
+			}
+		})
+	}
+	if _, err := LoadWordlistJSON(strings.NewReader(`{"words": [""]}`)); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected invalid word error, got %v", err)
+	}
+}